@@ -1,9 +1,12 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/ratelimit"
 	"go.uber.org/zap"
 )
 
@@ -14,9 +17,93 @@ func InitApplicationContext(
 	clock clock.Interface,
 ) *ApplicationContext {
 	return &ApplicationContext{
-		Logger:    logger,
-		Config:    config,
-		Localizer: localizer,
-		Clock:     clock,
+		Logger:          logger,
+		Config:          config,
+		Localizer:       localizer,
+		Clock:           clock,
+		TMDBRateLimiter: ratelimit.New(ratelimit.TMDBRequestsPerSecond, ratelimit.TMDBRequestsBurst),
+	}
+}
+
+// derivedContext returns a context for a part of the configuration of app (a profile, a Jellyfin user or server),
+// sharing the TMDB rate limiter of app.
+func (app *ApplicationContext) derivedContext(
+	config *config.Configuration,
+	logger *zap.Logger,
+	localizer *i18n.Localizer,
+) *ApplicationContext {
+	return &ApplicationContext{
+		Logger:          logger,
+		Config:          config,
+		Localizer:       localizer,
+		Clock:           app.Clock,
+		TMDBRateLimiter: app.TMDBRateLimiter,
+	}
+}
+
+// ReloadedContext returns the application context of a reloaded configuration of app.
+// The TMDB rate limiter of app is kept, so the newsletters still running share it with the next ones.
+func (app *ApplicationContext) ReloadedContext(
+	config *config.Configuration,
+	localizer *i18n.Localizer,
+) *ApplicationContext {
+	return app.derivedContext(config, app.Logger, localizer)
+}
+
+// NewsletterContexts returns one application context per newsletter to send (see config.NewsletterConfigurations).
+// If no profile is defined, app is returned as-is. Otherwise each profile gets its own localizer,
+// and a logger tagged with the profile name.
+func (app *ApplicationContext) NewsletterContexts() ([]*ApplicationContext, error) {
+	if len(app.Config.Profiles) == 0 {
+		return []*ApplicationContext{app}, nil
+	}
+
+	newsletterContexts := make([]*ApplicationContext, 0, len(app.Config.Profiles))
+	for _, profileConfig := range app.Config.NewsletterConfigurations() {
+		localizer, err := i18n.NewLocalizer(profileConfig.EmailTemplate.Language)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profileConfig.ProfileName, err)
+		}
+		newsletterContexts = append(newsletterContexts, app.derivedContext(
+			profileConfig,
+			app.Logger.With(zap.String("Profile", profileConfig.ProfileName)),
+			localizer,
+		))
+	}
+	return newsletterContexts, nil
+}
+
+// JellyfinUserContexts returns one application context per Jellyfin user the newsletter is built for
+// (see config.JellyfinUserConfigurations). The logger of a user newsletter is tagged with the Jellyfin user.
+func (app *ApplicationContext) JellyfinUserContexts() []*ApplicationContext {
+	if len(app.Config.JellyfinUsers) == 0 {
+		return []*ApplicationContext{app}
+	}
+
+	userConfigs := app.Config.JellyfinUserConfigurations()
+	userContexts := make([]*ApplicationContext, 0, len(userConfigs))
+	for _, userConfig := range userConfigs {
+		logger := app.Logger
+		if userConfig.Jellyfin.User != "" {
+			logger = logger.With(zap.String("Jellyfin user", userConfig.Jellyfin.User))
+		}
+		userContexts = append(userContexts, app.derivedContext(userConfig, logger, app.Localizer))
+	}
+	return userContexts
+}
+
+// JellyfinServerContexts returns one application context per Jellyfin server aggregated in the newsletter
+// (see config.JellyfinServerConfigurations). With several servers, loggers are tagged with the server.
+func (app *ApplicationContext) JellyfinServerContexts() []*ApplicationContext {
+	if len(app.Config.AdditionalJellyfinServers) == 0 {
+		return []*ApplicationContext{app}
+	}
+
+	serverConfigs := app.Config.JellyfinServerConfigurations()
+	serverContexts := make([]*ApplicationContext, 0, len(serverConfigs))
+	for _, serverConfig := range serverConfigs {
+		logger := app.Logger.With(zap.String("Jellyfin server", serverConfig.JellyfinServerLabel()))
+		serverContexts = append(serverContexts, app.derivedContext(serverConfig, logger, app.Localizer))
 	}
+	return serverContexts
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/ratelimit"
 	"go.uber.org/zap"
 )
 
@@ -12,4 +13,7 @@ type ApplicationContext struct {
 	Logger    *zap.Logger
 	Localizer *i18n.Localizer
 	Clock     clock.Interface
+	// TMDBRateLimiter limits the requests to TMDB. It is created with the top-level context and shared by
+	// the contexts derived from it, so the limit applies to all the newsletters together.
+	TMDBRateLimiter *ratelimit.Limiter
 }
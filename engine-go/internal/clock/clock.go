@@ -14,3 +14,12 @@ type RealClock struct{}
 func (r RealClock) Now() time.Time {
 	return time.Now()
 }
+
+// FixedClock always returns the same time, for reproducible outputs (e.g. the demo newsletter).
+type FixedClock struct {
+	Time time.Time
+}
+
+func (f FixedClock) Now() time.Time {
+	return f.Time
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// demoConfigYAML is the configuration of the demo newsletter (see the demo package).
+// The URLs and the token are placeholders: the demo never connects to Jellyfin, TMDB or an SMTP server.
+const demoConfigYAML = `
+jellyfin:
+  url: https://jellyfin.example.com
+  api_token: demo
+  watched_film_folders:
+    - Movies
+  watched_tv_folders:
+    - Shows
+  watched_music_folders:
+    - Music
+  watched_book_folders:
+    - Books
+  observed_period_days: 30
+  announce_removed_media: true
+
+tmdb:
+  api_key: eyJhbGciOiJIUzI1NiJ9.eyJhdWQiOiJkZW1vIiwic3ViIjoiZGVtbyJ9.ZGVtbw
+
+email_template:
+  theme: classic
+  language: en
+  subject: "Jellyfin Newsletter demo"
+  title: "What's new on Jellyfin"
+  subtitle: "A preview built with the demo library"
+  jellyfin_url: https://jellyfin.example.com
+  unsubscribe_email: unsubscribe@example.com
+  jellyfin_owner_name: Demo
+
+email:
+  smtp_server: localhost
+  smtp_port: 25
+  smtp_tls_type: NONE
+  smtp_sender_email: newsletter@example.com
+
+dry-run:
+  enabled: true
+  output_directory: ./previews/ # Replaced by the output directory given to LoadDemoConfig
+  output_filename: demo_newsletter.html
+
+recipients:
+  - demo@example.com
+`
+
+// LoadDemoConfig returns the configuration of the demo newsletter. The files saved between runs
+// (movies quality, library snapshot ...) are stored in stateDirectory, and the newsletter is written
+// in outputDirectory. Environment variables are ignored, so the demo output is the same everywhere.
+func LoadDemoConfig(stateDirectory string, outputDirectory string, themesDir string) (*Configuration, error) {
+	conf, err := loadConfigFromReader(
+		filepath.Join(stateDirectory, "config.yml"),
+		strings.NewReader(demoConfigYAML),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	conf.DryRun.OutputDirectory = outputDirectory
+	conf.loadThemesDir(themesDir)
+	return conf, nil
+}
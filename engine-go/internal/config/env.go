@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+const (
+	EnvPrefix         = "JFN_"
+	envPathSeparator  = "__"
+	envListSeparator  = ","
+	envListFlowPrefix = "["
+)
+
+// LookupEnvFunc has the same signature as os.LookupEnv. It is injected to ease testing.
+type LookupEnvFunc func(key string) (string, bool)
+
+// envOverride represents a configuration key that can be overridden by an environment variable.
+//   - path is the list of YAML keys leading to the field. For example ["jellyfin", "url"]
+//   - kind is the Go type of the field in yamlConfiguration, used to convert the raw env value
+type envOverride struct {
+	path []string
+	kind reflect.Type
+}
+
+// EnvVarName returns the environment variable name overriding the given YAML path.
+// Sections are separated by a double underscore, and dashes are replaced by underscores.
+// Example: ["jellyfin", "url"] => JFN_JELLYFIN__URL ; ["dry-run", "enabled"] => JFN_DRY_RUN__ENABLED.
+func EnvVarName(path []string) string {
+	parts := make([]string, 0, len(path))
+	for _, key := range path {
+		parts = append(parts, strings.ToUpper(strings.ReplaceAll(key, "-", "_")))
+	}
+	return EnvPrefix + strings.Join(parts, envPathSeparator)
+}
+
+// yamlKeyFromTag extracts the key name from a yaml struct tag. Returns "" if the field is not mapped.
+func yamlKeyFromTag(field reflect.StructField) string {
+	tag := field.Tag.Get("yaml")
+	name, _, _ := strings.Cut(tag, ",")
+	name = strings.TrimSpace(name)
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// listEnvOverrides walks recursively the yamlConfiguration type and returns every leaf field that can be overridden.
+func listEnvOverrides(t reflect.Type, parentPath []string) []envOverride {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var overrides []envOverride
+	for field := range t.Fields() {
+		key := yamlKeyFromTag(field)
+		if key == "" {
+			continue
+		}
+		path := append(append([]string{}, parentPath...), key)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType == reflect.TypeFor[yamlJellyfinServers]() {
+			// Only the single server form can be overridden (see setNodeValue)
+			overrides = append(overrides, listEnvOverrides(fieldType.Elem(), path)...)
+			continue
+		}
+		if fieldType.Kind() == reflect.Struct {
+			overrides = append(overrides, listEnvOverrides(fieldType, path)...)
+			continue
+		}
+		if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Struct {
+			// Lists of sections (e.g. profiles) can't be expressed with environment variables
+			continue
+		}
+		overrides = append(overrides, envOverride{path: path, kind: fieldType})
+	}
+	return overrides
+}
+
+// parseEnvList parses a list given in an environment variable.
+// Two syntaxes are accepted:
+//   - a comma separated list: "a@example.com, b@example.com"
+//   - a YAML/JSON flow sequence, useful when items contain commas: '["Doe, John <j@example.com>", "b@example.com"]'
+func parseEnvList(rawValue string) ([]string, error) {
+	trimmedValue := strings.TrimSpace(rawValue)
+	if strings.HasPrefix(trimmedValue, envListFlowPrefix) {
+		var list []string
+		if err := yaml.Unmarshal([]byte(trimmedValue), &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	list := []string{}
+	for item := range strings.SplitSeq(trimmedValue, envListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// convertEnvValue converts the raw string value of an environment variable in the Go type expected by the field.
+func convertEnvValue(rawValue string, kind reflect.Type) (any, error) {
+	switch kind.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(strings.TrimSpace(rawValue))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.Atoi(strings.TrimSpace(rawValue))
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(strings.TrimSpace(rawValue), kind.Bits())
+	case reflect.Slice:
+		return parseEnvList(rawValue)
+	case reflect.String:
+		return rawValue, nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", kind.String())
+	}
+}
+
+// nestedValue wraps value in maps following path. Example: (["a", "b"], 1) => {a: {b: 1}}.
+func nestedValue(path []string, value any) any {
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]any{path[i]: value}
+	}
+	return value
+}
+
+// findMappingValue returns the MappingValueNode with the given key in mapping, or nil.
+func findMappingValue(mapping *ast.MappingNode, key string) *ast.MappingValueNode {
+	for _, mappingValue := range mapping.Values {
+		if mappingValue.Key != nil && mappingValue.Key.String() == key {
+			return mappingValue
+		}
+	}
+	return nil
+}
+
+// setNodeValue sets value at path in the YAML tree. Existing nodes are replaced, missing sections are created.
+// Nodes that are not touched keep their original position so that validation errors still point to the config file.
+func setNodeValue(mapping *ast.MappingNode, path []string, value any) error {
+	mappingValue := findMappingValue(mapping, path[0])
+	if mappingValue == nil {
+		newNode, err := yaml.ValueToNode(nestedValue(path, value))
+		if err != nil {
+			return err
+		}
+		newMapping, ok := newNode.(*ast.MappingNode)
+		if !ok {
+			return fmt.Errorf("unexpected node type %s", newNode.Type().String())
+		}
+		mapping.Values = append(mapping.Values, newMapping.Values...)
+		return nil
+	}
+
+	if len(path) == 1 {
+		newNode, err := yaml.ValueToNode(value)
+		if err != nil {
+			return err
+		}
+		mappingValue.Value = newNode
+		return nil
+	}
+
+	if _, isList := mappingValue.Value.(*ast.SequenceNode); isList {
+		return fmt.Errorf("%s is a list, its items can't be overridden by environment variables", path[0])
+	}
+	childMapping, ok := mappingValue.Value.(*ast.MappingNode)
+	if !ok {
+		// The section exists but is empty or not a mapping (e.g. `scheduler:` with no value). It is replaced.
+		newNode, err := yaml.ValueToNode(nestedValue(path[1:], value))
+		if err != nil {
+			return err
+		}
+		mappingValue.Value = newNode
+		return nil
+	}
+	return setNodeValue(childMapping, path[1:], value)
+}
+
+// applyEnvOverrides overrides the parsed YAML document with the JFN_ prefixed environment variables.
+// It must be called before decoding, so the validator runs against the effective values.
+// It returns the applied environment variable names, indexed by the dot notation path they override.
+func applyEnvOverrides(document *ast.DocumentNode, lookupEnv LookupEnvFunc) (map[string]string, error) {
+	appliedEnvVars := map[string]string{}
+	if lookupEnv == nil {
+		return appliedEnvVars, nil
+	}
+	overrides := append(listEnvOverrides(reflect.TypeFor[yamlConfiguration](), nil), secretFileEnvOverrides()...)
+	for _, override := range overrides {
+		envName := EnvVarName(override.path)
+		rawValue, ok := lookupEnv(envName)
+		if !ok {
+			continue
+		}
+		value, err := convertEnvValue(rawValue, override.kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for environment variable %s: %w", envName, err)
+		}
+
+		rootMapping, isMapping := document.Body.(*ast.MappingNode)
+		if !isMapping {
+			return nil, fmt.Errorf("configuration root must be a mapping, got %s", document.Body.Type().String())
+		}
+
+		if err = setNodeValue(rootMapping, override.path, value); err != nil {
+			return nil, fmt.Errorf("failed to apply environment variable %s: %w", envName, err)
+		}
+		appliedEnvVars[strings.Join(override.path, ".")] = envName
+		// A secret set by env takes precedence over the one set in the config file, whatever the way it is defined.
+		if counterpart := counterpartSecretPath(override.path); counterpart != nil {
+			removeMappingValue(rootMapping, counterpart)
+		}
+	}
+	return appliedEnvVars, nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapLookupEnv(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "JFN_JELLYFIN__URL", EnvVarName([]string{"jellyfin", "url"}))
+	assert.Equal(t, "JFN_EMAIL__SMTP_PORT", EnvVarName([]string{"email", "smtp_port"}))
+	assert.Equal(t, "JFN_DRY_RUN__OUTPUT_DIRECTORY", EnvVarName([]string{"dry-run", "output_directory"}))
+	assert.Equal(t, "JFN_RECIPIENTS", EnvVarName([]string{"recipients"}))
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"JFN_JELLYFIN__URL":                    "http://jellyfin.example.com:8096/",
+		"JFN_JELLYFIN__API_TOKEN":              "env-token",
+		"JFN_JELLYFIN__WATCHED_FILM_FOLDERS":   "movies, kids-movies",
+		"JFN_EMAIL__SMTP_PORT":                 "465",
+		"JFN_RECIPIENTS":                       `["Doe, John <john@example.com>", "jane@example.com"]`,
+		"JFN_LOG__LEVEL":                       "DEBUG",
+		"JFN_DRY_RUN__SAVE_EMAIL_DATA":         "false",
+		"JFN_TMDB__MATCH_CONFIDENCE_THRESHOLD": "0.8",
+	}
+
+	config, err := loadConfigFromReader("./config/config.yml", strings.NewReader(validConfigYAML), mapLookupEnv(env))
+
+	require.NoError(t, err)
+	assert.Equal(t, "http://jellyfin.example.com:8096", config.Jellyfin.URL)
+	assert.Equal(t, "env-token", config.Jellyfin.APIKey.SafeString())
+	assert.Equal(t, []string{"movies", "kids-movies"}, config.Jellyfin.WatchedFilmFolders)
+	assert.Equal(t, []string{"/series"}, config.Jellyfin.WatchedSeriesFolders)
+	assert.Equal(t, 465, config.SMTP.Port)
+	assert.Equal(t, []string{"Doe, John <john@example.com>", "jane@example.com"}, config.EmailRecipients)
+	assert.Equal(t, "DEBUG", config.Log.Level)
+	assert.False(t, config.DryRun.SaveEmailData)
+	assert.True(t, config.DryRun.IncludeMetadata)
+	assert.InDelta(t, 0.8, config.TMDB.MatchConfidenceThreshold, 0.0001)
+}
+
+func TestLoadConfig_EnvOverridesCreateMissingSections(t *testing.T) {
+	yamlWithoutScheduler := RemoveYamlPartHelper(validConfigYAML, "scheduler")
+	yamlWithoutRecipients := RemoveYamlPartHelper(yamlWithoutScheduler, "recipients")
+	env := map[string]string{
+		"JFN_SCHEDULER__CRON": "0 9 * * 1",
+		"JFN_RECIPIENTS":      "a@example.com,b@example.com",
+	}
+
+	config, err := loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(yamlWithoutRecipients),
+		mapLookupEnv(env),
+	)
+
+	require.NoError(t, err)
+	assert.True(t, config.Scheduler.Enabled)
+	assert.Equal(t, "0 9 * * 1", config.Scheduler.CronExpr)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, config.EmailRecipients)
+}
+
+func TestLoadConfig_EnvOverridesAreValidated(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedError string
+	}{
+		{
+			name:          "Invalid integer",
+			env:           map[string]string{"JFN_EMAIL__SMTP_PORT": "not-a-port"},
+			expectedError: "invalid value for environment variable JFN_EMAIL__SMTP_PORT",
+		},
+		{
+			name:          "Invalid boolean",
+			env:           map[string]string{"JFN_DRY_RUN__ENABLED": "maybe"},
+			expectedError: "invalid value for environment variable JFN_DRY_RUN__ENABLED",
+		},
+		{
+			name:          "Invalid float",
+			env:           map[string]string{"JFN_TMDB__MATCH_CONFIDENCE_THRESHOLD": "high"},
+			expectedError: "invalid value for environment variable JFN_TMDB__MATCH_CONFIDENCE_THRESHOLD",
+		},
+		{
+			name:          "Value rejected by the validator",
+			env:           map[string]string{"JFN_EMAIL__SMTP_PORT": "70000"},
+			expectedError: "Field validation for 'SMTPPort' failed on the 'max' tag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := loadConfigFromReader(
+				"./config/config.yml",
+				strings.NewReader(validConfigYAML),
+				mapLookupEnv(tt.env),
+			)
+
+			require.Error(t, err)
+			assert.Nil(t, config)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
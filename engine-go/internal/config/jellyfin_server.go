@@ -0,0 +1,24 @@
+package config
+
+// JellyfinServerConfigurations returns one configuration per Jellyfin server aggregated in the newsletter:
+// the configuration itself for the first server, then a copy per additional server, with Jellyfin set to
+// this server. The Jellyfin user of the newsletter is kept, its ID must be resolved on each server.
+func (conf *Configuration) JellyfinServerConfigurations() []*Configuration {
+	configurations := make([]*Configuration, 0, len(conf.AdditionalJellyfinServers)+1)
+	configurations = append(configurations, conf)
+	for _, server := range conf.AdditionalJellyfinServers {
+		serverConf := *conf
+		serverConf.Jellyfin = server
+		serverConf.Jellyfin.User = conf.Jellyfin.User
+		configurations = append(configurations, &serverConf)
+	}
+	return configurations
+}
+
+// JellyfinServerLabel returns the name of the Jellyfin server, or its URL when it has no name.
+func (conf *Configuration) JellyfinServerLabel() string {
+	if conf.Jellyfin.Name != "" {
+		return conf.Jellyfin.Name
+	}
+	return conf.Jellyfin.URL
+}
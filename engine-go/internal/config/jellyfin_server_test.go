@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jellyfinServersYAML = `jellyfin:
+  - name: home
+    url: http://home:8096/
+    api_token: home-secret
+    watched_film_folders:
+      - /movies
+    watched_tv_folders:
+      - /series
+    observed_period_days: 30
+  - name: cabin
+    url: http://cabin:8096
+    api_token_file: %s
+    library_selection: auto
+    observed_period_days: 7
+    public_url: https://cabin.example.com/
+`
+
+// withJellyfinServers replaces the jellyfin section of the valid configuration by servers.
+func withJellyfinServers(servers string) string {
+	return RemoveYamlPartHelper(validConfigYAML, "jellyfin") + "\n" + servers
+}
+
+func TestLoadConfig_JellyfinServers(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "cabin_token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("cabin-secret\n"), 0600))
+
+	config, err := loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(withJellyfinServers(strings.Replace(jellyfinServersYAML, "%s", tokenFile, 1))),
+		nil,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "home", config.Jellyfin.Name)
+	assert.Equal(t, "http://home:8096", config.Jellyfin.URL)
+	assert.Empty(t, config.Jellyfin.PublicURL)
+	require.Len(t, config.AdditionalJellyfinServers, 1)
+	cabin := config.AdditionalJellyfinServers[0]
+	assert.Equal(t, "cabin", cabin.Name)
+	assert.Equal(t, "http://cabin:8096", cabin.URL)
+	assert.Equal(t, Secret("cabin-secret"), cabin.APIKey)
+	assert.True(t, cabin.AutoDiscoverLibraries)
+	assert.Equal(t, 7, cabin.ObservedPeriodDays)
+	assert.Equal(t, "https://cabin.example.com", cabin.PublicURL)
+}
+
+func TestLoadConfig_AdditionalJellyfinServerLinksToItsURL(t *testing.T) {
+	servers := strings.Replace(jellyfinServersYAML, "    api_token_file: %s\n", "    api_token: cabin-secret\n", 1)
+	servers = strings.Replace(servers, "    public_url: https://cabin.example.com/\n", "", 1)
+
+	config, err := loadConfigFromReader("./config/config.yml", strings.NewReader(withJellyfinServers(servers)), nil)
+
+	require.NoError(t, err)
+	require.Len(t, config.AdditionalJellyfinServers, 1)
+	assert.Equal(t, "http://cabin:8096", config.AdditionalJellyfinServers[0].PublicURL)
+}
+
+func TestLoadConfig_InvalidJellyfinServers(t *testing.T) {
+	servers := strings.Replace(jellyfinServersYAML, "    api_token_file: %s\n", "    api_token: cabin-secret\n", 1)
+	tests := []struct {
+		name    string
+		servers string
+	}{
+		{name: "Empty list", servers: "jellyfin: []\n"},
+		{name: "Same name", servers: strings.Replace(servers, "name: cabin", "name: home", 1)},
+		{name: "Without names", servers: strings.NewReplacer("  - name: home\n    url", "  - url",
+			"  - name: cabin\n    url", "  - url").Replace(servers)},
+		{name: "Invalid server", servers: strings.Replace(servers, "url: http://cabin:8096", "url: cabin", 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfigFromReader(
+				"./config/config.yml",
+				strings.NewReader(withJellyfinServers(tt.servers)),
+				nil,
+			)
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_EnvOverridesOfJellyfinServers(t *testing.T) {
+	servers := strings.Replace(jellyfinServersYAML, "    api_token_file: %s\n", "    api_token: cabin-secret\n", 1)
+	lookupEnv := func(key string) (string, bool) {
+		if key == "JFN_JELLYFIN__URL" {
+			return "http://override:8096", true
+		}
+		return "", false
+	}
+
+	_, err := loadConfigFromReader("./config/config.yml", strings.NewReader(withJellyfinServers(servers)), lookupEnv)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JFN_JELLYFIN__URL")
+}
+
+func TestConfiguration_JellyfinServerConfigurations(t *testing.T) {
+	conf := &Configuration{
+		Jellyfin: JellyfinConfig{Name: "home", URL: "http://home:8096", User: "kid", UserID: "home-kid-id"},
+		AdditionalJellyfinServers: []JellyfinConfig{
+			{Name: "cabin", URL: "http://cabin:8096"},
+		},
+		EmailRecipients: []string{"kid@example.com"},
+	}
+
+	configurations := conf.JellyfinServerConfigurations()
+
+	require.Len(t, configurations, 2)
+	assert.Same(t, conf, configurations[0])
+	assert.Equal(t, "cabin", configurations[1].Jellyfin.Name)
+	assert.Equal(t, "kid", configurations[1].Jellyfin.User)
+	assert.Empty(t, configurations[1].Jellyfin.UserID)
+	assert.Equal(t, []string{"kid@example.com"}, configurations[1].EmailRecipients)
+	assert.Equal(t, "http://cabin:8096", (&Configuration{Jellyfin: JellyfinConfig{URL: "http://cabin:8096"}}).
+		JellyfinServerLabel())
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"net/mail"
+	"strings"
+)
+
+func buildJellyfinUsersConfig(yamlParsedConfig *yamlConfiguration) []JellyfinUserMapping {
+	jellyfinUsers := make([]JellyfinUserMapping, 0, len(yamlParsedConfig.JellyfinUsers))
+	for _, yamlJellyfinUser := range yamlParsedConfig.JellyfinUsers {
+		jellyfinUsers = append(jellyfinUsers, JellyfinUserMapping{
+			Recipient: yamlJellyfinUser.Recipient,
+			User:      yamlJellyfinUser.User,
+		})
+	}
+	return jellyfinUsers
+}
+
+// recipientAddress returns the email address of a recipient, which may have a display name
+// (e.g. "Name <name@example.com>"). Addresses are compared case-insensitively.
+func recipientAddress(recipient string) string {
+	if parsedAddress, err := mail.ParseAddress(recipient); err == nil {
+		return strings.ToLower(parsedAddress.Address)
+	}
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
+
+// jellyfinUserOf returns the Jellyfin user the recipient is mapped to.
+func (conf *Configuration) jellyfinUserOf(recipient string) (string, bool) {
+	address := recipientAddress(recipient)
+	for _, mapping := range conf.JellyfinUsers {
+		if recipientAddress(mapping.Recipient) == address {
+			return mapping.User, true
+		}
+	}
+	return "", false
+}
+
+// IsNewsletterRecipient reports whether the recipient receives a newsletter: it is listed in the recipients,
+// or in the recipients of a profile when profiles are defined.
+func (conf *Configuration) IsNewsletterRecipient(recipient string) bool {
+	address := recipientAddress(recipient)
+	for _, newsletterConf := range conf.NewsletterConfigurations() {
+		for _, newsletterRecipient := range newsletterConf.EmailRecipients {
+			if recipientAddress(newsletterRecipient) == address {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JellyfinUserConfigurations splits the newsletter by Jellyfin user. The recipients mapped to a Jellyfin user
+// get a newsletter of their own, with Jellyfin.User set. The other recipients get the global newsletter,
+// returned first. Newsletters without recipients are not returned.
+// Without mapping, the configuration itself is returned.
+func (conf *Configuration) JellyfinUserConfigurations() []*Configuration {
+	if len(conf.JellyfinUsers) == 0 {
+		return []*Configuration{conf}
+	}
+
+	globalRecipients := []string{}
+	users := []string{} // Keeps the recipients order
+	recipientsByUser := map[string][]string{}
+	for _, recipient := range conf.EmailRecipients {
+		user, isMapped := conf.jellyfinUserOf(recipient)
+		if !isMapped {
+			globalRecipients = append(globalRecipients, recipient)
+			continue
+		}
+		if _, known := recipientsByUser[user]; !known {
+			users = append(users, user)
+		}
+		recipientsByUser[user] = append(recipientsByUser[user], recipient)
+	}
+
+	configurations := make([]*Configuration, 0, len(users)+1)
+	if len(globalRecipients) > 0 {
+		globalConf := *conf
+		globalConf.EmailRecipients = globalRecipients
+		configurations = append(configurations, &globalConf)
+	}
+	for _, user := range users {
+		userConf := *conf
+		userConf.EmailRecipients = recipientsByUser[user]
+		userConf.Jellyfin.User = user
+		configurations = append(configurations, &userConf)
+	}
+	return configurations
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jellyfinUsersYAML = `
+jellyfin_users:
+  - recipient: "Kid <USER2@example.com>"
+    user: kid
+`
+
+func TestLoadConfig_JellyfinUsers(t *testing.T) {
+	config, err := loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(validConfigYAML+jellyfinUsersYAML),
+		nil,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []JellyfinUserMapping{{Recipient: "Kid <USER2@example.com>", User: "kid"}}, config.JellyfinUsers)
+}
+
+func TestLoadConfig_JellyfinUsersWithoutUser(t *testing.T) {
+	_, err := loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(validConfigYAML+"\njellyfin_users:\n  - recipient: user2@example.com\n"),
+		nil,
+	)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required")
+}
+
+func TestConfiguration_JellyfinUserConfigurations(t *testing.T) {
+	tests := []struct {
+		name               string
+		recipients         []string
+		jellyfinUsers      []JellyfinUserMapping
+		expectedRecipients [][]string
+		expectedUsers      []string
+	}{
+		{
+			name:               "No mapping",
+			recipients:         []string{"user1@example.com", "user2@example.com"},
+			expectedRecipients: [][]string{{"user1@example.com", "user2@example.com"}},
+			expectedUsers:      []string{""},
+		},
+		{
+			name:       "Mapped and global recipients",
+			recipients: []string{"user1@example.com", "Kid <kid@example.com>", "kid2@example.com", "teen@example.com"},
+			jellyfinUsers: []JellyfinUserMapping{
+				{Recipient: "teen@example.com", User: "teen"},
+				{Recipient: "KID@example.com", User: "kids"},
+				{Recipient: "kid2@example.com", User: "kids"},
+			},
+			expectedRecipients: [][]string{
+				{"user1@example.com"},
+				{"Kid <kid@example.com>", "kid2@example.com"},
+				{"teen@example.com"},
+			},
+			expectedUsers: []string{"", "kids", "teen"},
+		},
+		{
+			name:       "All recipients mapped",
+			recipients: []string{"kid@example.com"},
+			jellyfinUsers: []JellyfinUserMapping{
+				{Recipient: "kid@example.com", User: "kid"},
+				{Recipient: "not-a-recipient@example.com", User: "other"},
+			},
+			expectedRecipients: [][]string{{"kid@example.com"}},
+			expectedUsers:      []string{"kid"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Configuration{EmailRecipients: test.recipients, JellyfinUsers: test.jellyfinUsers}
+
+			userConfigs := config.JellyfinUserConfigurations()
+
+			require.Len(t, userConfigs, len(test.expectedRecipients))
+			for i, userConfig := range userConfigs {
+				assert.Equal(t, test.expectedRecipients[i], userConfig.EmailRecipients)
+				assert.Equal(t, test.expectedUsers[i], userConfig.Jellyfin.User)
+			}
+			// The configuration itself is not modified
+			assert.Equal(t, test.recipients, config.EmailRecipients)
+			assert.Empty(t, config.Jellyfin.User)
+		})
+	}
+}
+
+func TestConfiguration_IsNewsletterRecipient(t *testing.T) {
+	config, err := loadConfigFromReader("./config/config.yml", strings.NewReader(validConfigYAML), nil)
+	require.NoError(t, err)
+
+	assert.True(t, config.IsNewsletterRecipient("User1 <USER1@example.com>"))
+	assert.False(t, config.IsNewsletterRecipient("family@example.com"))
+
+	// With profiles, only the profiles recipients receive a newsletter
+	config, err = loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(validConfigYAML+profilesYAML),
+		nil,
+	)
+	require.NoError(t, err)
+
+	assert.True(t, config.IsNewsletterRecipient("family@example.com"))
+	assert.True(t, config.IsNewsletterRecipient("user2@example.com")) // Inherited by the friends profile
+}
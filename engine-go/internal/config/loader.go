@@ -1,12 +1,15 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 )
 
 func LoadConfig(configPath string, themesDir string) (*Configuration, error) {
@@ -14,7 +17,8 @@ func LoadConfig(configPath string, themesDir string) (*Configuration, error) {
 	if err != nil {
 		return nil, err
 	}
-	conf, err := loadConfigFromReader(configPath, file)
+	defer file.Close()
+	conf, err := loadConfigFromReader(configPath, file, os.LookupEnv)
 	if err != nil {
 		return nil, err
 	}
@@ -28,41 +32,59 @@ func (conf *Configuration) loadThemesDir(themesDir string) {
 	if themesDir != "" {
 		fs := os.DirFS(themesDir)
 		conf.EmailTemplate.ThemesDirFS = &fs
+		for i := range conf.Profiles {
+			conf.Profiles[i].EmailTemplate.ThemesDirFS = &fs
+		}
 	}
 }
 
-func loadConfigFromReader(configPath string, r io.Reader) (*Configuration, error) {
+// loadConfigFromReader parses the YAML configuration, applies the environment variable overrides
+// (see env.go) and builds the final Configuration.
+func loadConfigFromReader(configPath string, r io.Reader, lookupEnv LookupEnvFunc) (*Configuration, error) {
 	yamlParsedConfig := &yamlConfiguration{}
 
-	if err := parseYaml(r, yamlParsedConfig); err != nil {
+	if err := parseYaml(r, yamlParsedConfig, lookupEnv); err != nil {
 		return nil, err
 	}
 
+	return buildConfiguration(configPath, yamlParsedConfig), nil
+}
+
+func buildConfiguration(configPath string, yamlParsedConfig *yamlConfiguration) *Configuration {
 	config := &Configuration{}
 
 	config.Log = buildLogConfig(yamlParsedConfig)
 	config.Scheduler = buildSchedulerConfig(yamlParsedConfig)
-	config.Jellyfin = buildJellyfinConfig(yamlParsedConfig)
+	config.Jellyfin, config.AdditionalJellyfinServers = buildJellyfinServersConfig(yamlParsedConfig)
 	config.TMDB = buildTMDBConfig(yamlParsedConfig)
+	config.Webhook = buildWebhookConfig(yamlParsedConfig)
 	config.EmailTemplate = buildEmailTemplateConfig(yamlParsedConfig)
 	config.SMTP = buildSMTPConfig(yamlParsedConfig)
 	config.DryRun = buildDryRunConfig(yamlParsedConfig)
 	config.EmailRecipients = buildRecipientsConfig(yamlParsedConfig)
+	config.JellyfinUsers = buildJellyfinUsersConfig(yamlParsedConfig)
+	// Profiles inherit the top-level values, so they must be built last
+	config.Profiles = buildProfilesConfig(yamlParsedConfig, config)
 	config.ConfigFilePath = configPath
 
-	return config, nil
+	return config
 }
 
-func parseYaml(r io.Reader, yamlParsedConfig *yamlConfiguration) error {
-	validate := validator.New()
+func parseYaml(r io.Reader, yamlParsedConfig *yamlConfiguration, lookupEnv LookupEnvFunc) error {
+	validate := newValidator()
+
+	document, _, err := parseYamlDocument(r, lookupEnv)
+	if err != nil {
+		return err
+	}
 
 	decoder := yaml.NewDecoder(
-		r,
+		bytes.NewReader(nil),
 		yaml.Validator(validate),
 		yaml.Strict(),
 	)
 
-	err := decoder.Decode(yamlParsedConfig)
+	err = decoder.DecodeFromNode(document.Body, yamlParsedConfig)
 	if err != nil {
 		yaml.FormatError(err, true, true)
 		return fmt.Errorf("failed to decode configuration file: %w", err)
@@ -70,6 +92,47 @@ func parseYaml(r io.Reader, yamlParsedConfig *yamlConfiguration) error {
 	return nil
 }
 
+// parseYamlDocument parses the YAML configuration in a tree, without decoding it,
+// and applies the environment variables overrides and the secret files on the tree.
+// It also returns the environment variable names applied, indexed by dot notation path.
+func parseYamlDocument(r io.Reader, lookupEnv LookupEnvFunc) (*ast.DocumentNode, map[string]string, error) {
+	rawYaml, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	parsedFile, err := parser.ParseBytes(rawYaml, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode configuration file: %w", err)
+	}
+	if len(parsedFile.Docs) == 0 {
+		parsedFile.Docs = append(parsedFile.Docs, ast.Document(nil, nil))
+	}
+	document := parsedFile.Docs[0]
+	if document.Body == nil {
+		// Empty configuration file. Values may all be provided by environment variables.
+		emptyRoot, nodeErr := yaml.ValueToNode(map[string]any{})
+		if nodeErr != nil {
+			return nil, nil, nodeErr
+		}
+		document.Body = emptyRoot
+	}
+
+	// Environment variables are applied on the YAML tree before decoding.
+	// This way the validator checks the effective values, and errors still point to the config file lines.
+	appliedEnvVars, err := applyEnvOverrides(document, lookupEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rootMapping, ok := document.Body.(*ast.MappingNode); ok {
+		if err = resolveSecretFiles(rootMapping); err != nil {
+			return nil, nil, fmt.Errorf("failed to load secrets: %w", err)
+		}
+	}
+	return document, appliedEnvVars, nil
+}
+
 func buildLogConfig(yamlParsedConfig *yamlConfiguration) LogConfig {
 	logConfig := LogConfig{
 		Level:  "INFO",
@@ -98,31 +161,108 @@ func buildSchedulerConfig(yamlParsedConfig *yamlConfiguration) SchedulerConfig {
 	return schedulerConfig
 }
 
-func buildJellyfinConfig(yamlParsedConfig *yamlConfiguration) JellyfinConfig {
-	// Remove trailing / :
-	jellyfinURL := yamlParsedConfig.Jellyfin.URL
-	if string(yamlParsedConfig.Jellyfin.URL[len(yamlParsedConfig.Jellyfin.URL)-1]) == "/" {
-		jellyfinURL = yamlParsedConfig.Jellyfin.URL[0 : len(yamlParsedConfig.Jellyfin.URL)-1]
+// buildJellyfinServersConfig returns the first Jellyfin server, and the additional ones.
+// Additional servers link their items to their own URL, unless public_url is set.
+func buildJellyfinServersConfig(yamlParsedConfig *yamlConfiguration) (JellyfinConfig, []JellyfinConfig) {
+	if len(yamlParsedConfig.Jellyfin) == 0 {
+		return JellyfinConfig{}, nil
+	}
+	additionalServers := []JellyfinConfig{}
+	for _, yamlServer := range yamlParsedConfig.Jellyfin[1:] {
+		server := buildJellyfinConfig(yamlServer)
+		if server.PublicURL == "" {
+			server.PublicURL = server.URL
+		}
+		additionalServers = append(additionalServers, server)
 	}
+	return buildJellyfinConfig(yamlParsedConfig.Jellyfin[0]), additionalServers
+}
+
+func buildJellyfinConfig(yamlServer yamlJellyfin) JellyfinConfig {
+	// Remove trailing / :
+	jellyfinURL := strings.TrimSuffix(yamlServer.URL, "/")
 	jellyfinConfig := JellyfinConfig{
+		Name:                                yamlServer.Name,
 		URL:                                 jellyfinURL,
-		APIKey:                              yamlParsedConfig.Jellyfin.APIToken,
-		WatchedFilmFolders:                  yamlParsedConfig.Jellyfin.WatchedFilmFolders,
-		WatchedSeriesFolders:                yamlParsedConfig.Jellyfin.WatchedSeriesFolders,
-		ObservedPeriodDays:                  yamlParsedConfig.Jellyfin.ObservedPeriodDays,
+		APIKey:                              yamlServer.APIToken,
+		WatchedFilmFolders:                  yamlServer.WatchedFilmFolders,
+		WatchedSeriesFolders:                yamlServer.WatchedSeriesFolders,
+		WatchedMusicFolders:                 yamlServer.WatchedMusicFolders,
+		WatchedBookFolders:                  yamlServer.WatchedBookFolders,
+		ObservedPeriodDays:                  yamlServer.ObservedPeriodDays,
+		AutoDiscoverLibraries:               yamlServer.LibrarySelection == "auto",
+		IncludedLibraries:                   yamlServer.IncludeLibraries,
+		ExcludedLibraries:                   yamlServer.ExcludeLibraries,
 		IgnoreItemsAddedAfterLastNewsletter: false,
+		NewItemDetection:                    NewItemDetectionDate,
+		AnnounceUpgrades:                    true,
+		AnnounceRemovedMedia:                false,
+		RemovedMediaFolders:                 yamlServer.RemovedMediaFolders,
+		PublicURL:                           strings.TrimSuffix(yamlServer.PublicURL, "/"),
+		PublicImageURL:                      strings.TrimSuffix(yamlServer.PublicImageURL, "/"),
 	}
-	if yamlParsedConfig.Jellyfin.IgnoreItemsAddedAfterLastNewsletter != nil &&
-		*yamlParsedConfig.Jellyfin.IgnoreItemsAddedAfterLastNewsletter {
+	if yamlServer.IgnoreItemsAddedAfterLastNewsletter != nil &&
+		*yamlServer.IgnoreItemsAddedAfterLastNewsletter {
 		jellyfinConfig.IgnoreItemsAddedAfterLastNewsletter = true
 	}
+	if yamlServer.NewItemDetection != "" {
+		jellyfinConfig.NewItemDetection = yamlServer.NewItemDetection
+	}
+	if yamlServer.AnnounceUpgrades != nil {
+		jellyfinConfig.AnnounceUpgrades = *yamlServer.AnnounceUpgrades
+	}
+	if exclude := yamlServer.Exclude; exclude != nil {
+		jellyfinConfig.Exclusions = ExclusionRules{
+			Tags:            exclude.Tags,
+			Genres:          exclude.Genres,
+			PathGlobs:       exclude.Paths,
+			OfficialRatings: exclude.OfficialRatings,
+			ItemIDs:         exclude.ItemIDs,
+			TMDBIds:         exclude.TMDBIds,
+		}
+	}
+	if yamlServer.AnnounceRemovedMedia != nil {
+		jellyfinConfig.AnnounceRemovedMedia = *yamlServer.AnnounceRemovedMedia
+	}
 	return jellyfinConfig
 }
 
 func buildTMDBConfig(yamlParsedConfig *yamlConfiguration) TMDBConfig {
-	return TMDBConfig{
-		APIKey: yamlParsedConfig.TMDB.APIKey,
+	tmdbConfig := TMDBConfig{
+		Enabled:                  false,
+		CacheTTLDays:             7,
+		MatchConfidenceThreshold: 0.6,
+	}
+	if yamlParsedConfig.TMDB != nil {
+		tmdbConfig.Enabled = true
+		tmdbConfig.APIKey = yamlParsedConfig.TMDB.APIKey
+		if yamlParsedConfig.TMDB.CacheTTLDays != nil {
+			tmdbConfig.CacheTTLDays = *yamlParsedConfig.TMDB.CacheTTLDays
+		}
+		if yamlParsedConfig.TMDB.MatchConfidenceThreshold != nil {
+			tmdbConfig.MatchConfidenceThreshold = *yamlParsedConfig.TMDB.MatchConfidenceThreshold
+		}
+	}
+	return tmdbConfig
+}
+
+func buildWebhookConfig(yamlParsedConfig *yamlConfiguration) WebhookConfig {
+	webhookConfig := WebhookConfig{
+		Enabled:       false,
+		ListenAddress: ":8787",
+		Mode:          WebhookModeMerge,
+	}
+	if yamlParsedConfig.Webhook != nil {
+		webhookConfig.Enabled = true
+		webhookConfig.Secret = yamlParsedConfig.Webhook.Secret
+		if yamlParsedConfig.Webhook.ListenAddress != "" {
+			webhookConfig.ListenAddress = yamlParsedConfig.Webhook.ListenAddress
+		}
+		if yamlParsedConfig.Webhook.Mode != "" {
+			webhookConfig.Mode = yamlParsedConfig.Webhook.Mode
+		}
 	}
+	return webhookConfig
 }
 
 func buildEmailTemplateConfig(yamlParsedConfig *yamlConfiguration) EmailTemplateConfig {
@@ -13,16 +13,92 @@ type SchedulerConfig struct {
 }
 
 type JellyfinConfig struct {
-	URL                                 string
-	APIKey                              Secret
-	WatchedFilmFolders                  []string
-	WatchedSeriesFolders                []string
+	// Name identifies the server in the logs and in the saved run state. Empty if a single server is configured.
+	Name                 string
+	URL                  string
+	APIKey               Secret
+	WatchedFilmFolders   []string
+	WatchedSeriesFolders []string
+	WatchedMusicFolders  []string
+	WatchedBookFolders   []string
+	// AutoDiscoverLibraries watches every movies, tvshows, music and books library instead of the watched folders.
+	// IncludedLibraries and ExcludedLibraries filter the discovered libraries.
+	AutoDiscoverLibraries               bool
+	IncludedLibraries                   []string
+	ExcludedLibraries                   []string
 	ObservedPeriodDays                  int
 	IgnoreItemsAddedAfterLastNewsletter bool
+	// NewItemDetection is how new items are detected, NewItemDetectionDate or NewItemDetectionSnapshot.
+	NewItemDetection string
+	// AnnounceUpgrades lists the movies replaced by another version (e.g. 1080p by 4K) in an "Upgraded" section.
+	// If false, they are left out of the newsletter.
+	AnnounceUpgrades bool
+	// AnnounceRemovedMedia lists the movies and series removed since the previous run in a "No longer available" section.
+	AnnounceRemovedMedia bool
+	// RemovedMediaFolders are the folders checked for removed media (name, ID or path of a library).
+	// Empty to check the watched film and series folders.
+	RemovedMediaFolders []string
+	// Exclusions are the movies and series never announced.
+	Exclusions ExclusionRules
+	// User is the Jellyfin user (name or ID) the newsletter is built for, see JellyfinUserConfigurations.
+	// Empty for the global newsletter, built with the API key permissions.
+	User string
+	// UserID is the ID of User, resolved by the newsletter workflow.
+	UserID string
+	// PublicURL is the public URL the items of the server are linked to. Empty for the first server if not set,
+	// which links to EmailTemplate.JellyfinURL. Defaults to URL for the additional servers.
+	PublicURL string
+	// PublicImageURL is the public URL the images are served from in the email.
+	// Defaults to PublicURL, then to EmailTemplate.JellyfinURL, then to URL.
+	PublicImageURL string
 }
 
+// ExclusionRules exclude movies and series from the newsletter, e.g. home videos or adult content.
+// An item is excluded as soon as one rule matches. Tags, genres and official ratings are matched without case.
+type ExclusionRules struct {
+	Tags            []string
+	Genres          []string
+	PathGlobs       []string // Glob patterns (e.g. "/media/home-videos/*"). A matching folder excludes its content.
+	OfficialRatings []string
+	ItemIDs         []string
+	TMDBIds         []string
+}
+
+const (
+	// NewItemDetectionDate announces the items whose Jellyfin creation date is within the observed period.
+	NewItemDetectionDate = "date"
+	// NewItemDetectionSnapshot announces the items never announced before, whatever their creation date.
+	// It survives library rescans and server migrations, which reset the creation dates.
+	NewItemDetectionSnapshot = "snapshot"
+)
+
+// TMDBConfig is optional. When enabled, TMDB completes the overview and the poster of the movies and series
+// Jellyfin has no metadata for.
+// TMDB responses are cached on disk for CacheTTLDays days. Expired entries are still used when TMDB is unreachable.
+// A media found by a search by name is used only if it matches with a confidence of at least
+// MatchConfidenceThreshold, between 0 and 1.
 type TMDBConfig struct {
-	APIKey Secret
+	Enabled                  bool
+	APIKey                   Secret
+	CacheTTLDays             int
+	MatchConfidenceThreshold float64
+}
+
+const (
+	// WebhookModeMerge adds the movies received by the webhook to the movies found by the folder scan.
+	WebhookModeMerge = "merge"
+	// WebhookModeReplace announces only the movies received by the webhook, the movies are not scanned by date.
+	WebhookModeReplace = "replace"
+)
+
+// WebhookConfig is optional. When enabled, with the scheduler, an HTTP listener receives the ItemAdded events of
+// the Jellyfin Webhook plugin and queues them for the next newsletter run.
+type WebhookConfig struct {
+	Enabled       bool
+	ListenAddress string
+	// Secret must be sent by Jellyfin in the X-Webhook-Secret header.
+	Secret Secret
+	Mode   string // WebhookModeMerge or WebhookModeReplace
 }
 
 type EmailTemplateConfig struct {
@@ -58,16 +134,47 @@ type DryRunConfig struct {
 	SaveEmailData      bool
 }
 
+// ProfileConfig is a newsletter profile. Profiles share the Jellyfin, TMDB and SMTP connections,
+// but each one has its own recipients, watched folders, observed period, cron expression and email template.
+// Values not defined by the profile are inherited from the top-level configuration.
+type ProfileConfig struct {
+	Name                 string
+	EmailRecipients      []string
+	CronExpr             string
+	WatchedFilmFolders   []string
+	WatchedSeriesFolders []string
+	WatchedMusicFolders  []string
+	WatchedBookFolders   []string
+	ObservedPeriodDays   int
+	EmailTemplate        EmailTemplateConfig
+}
+
+// JellyfinUserMapping maps a recipient to a Jellyfin user (name or ID). The recipient receives a newsletter
+// built for this user, so Jellyfin library access and parental rating restrictions apply.
+type JellyfinUserMapping struct {
+	Recipient string
+	User      string
+}
+
 type Configuration struct {
 	Log             LogConfig
 	EmailRecipients []string
 	Scheduler       SchedulerConfig
 	Jellyfin        JellyfinConfig
-	TMDB            TMDBConfig
-	EmailTemplate   EmailTemplateConfig
-	SMTP            SMTPConfig
-	DryRun          DryRunConfig
-	ConfigFilePath  string
+	// AdditionalJellyfinServers are the servers aggregated in the newsletter with Jellyfin, the first server.
+	// See JellyfinServerConfigurations.
+	AdditionalJellyfinServers []JellyfinConfig
+	TMDB                      TMDBConfig
+	Webhook                   WebhookConfig
+	EmailTemplate             EmailTemplateConfig
+	SMTP                      SMTPConfig
+	DryRun                    DryRunConfig
+	Profiles                  []ProfileConfig
+	JellyfinUsers             []JellyfinUserMapping
+	// ProfileName is the name of the profile this configuration has been built for (see ForProfile).
+	// Empty if no profile is defined.
+	ProfileName    string
+	ConfigFilePath string
 }
 
 type yamlConfiguration struct {
@@ -78,17 +185,17 @@ type yamlConfiguration struct {
 	Scheduler *struct {
 		Cron string `yaml:"cron" validate:"cron"`
 	} `yaml:"scheduler,omitempty"`
-	Jellyfin struct {
-		URL                                 string   `yaml:"url" validate:"required,http_url"`
-		APIToken                            Secret   `yaml:"api_token" validate:"required"`
-		WatchedFilmFolders                  []string `yaml:"watched_film_folders" validate:"required"`
-		WatchedSeriesFolders                []string `yaml:"watched_tv_folders" validate:"required"`
-		ObservedPeriodDays                  int      `yaml:"observed_period_days" validate:"required,numeric"`
-		IgnoreItemsAddedAfterLastNewsletter *bool    `yaml:"ignore_item_added_before_last_newsletter,omitempty" validate:"omitempty,boolean"`
-	} `yaml:"jellyfin"            validate:"required"`
-	TMDB struct {
-		APIKey Secret `yaml:"api_key" validate:"required,jwt"`
-	} `yaml:"tmdb"                validate:"required"`
+	Jellyfin yamlJellyfinServers `yaml:"jellyfin" validate:"required,min=1,unique=Name,dive"`
+	TMDB     *struct {
+		APIKey                   Secret   `yaml:"api_key" validate:"required,jwt"`
+		CacheTTLDays             *int     `yaml:"cache_ttl_days,omitempty" validate:"omitempty,numeric,min=0"`
+		MatchConfidenceThreshold *float64 `yaml:"match_confidence_threshold,omitempty" validate:"omitempty,min=0,max=1"`
+	} `yaml:"tmdb,omitempty"`
+	Webhook *struct {
+		ListenAddress string `yaml:"listen_address,omitempty" validate:"omitempty,hostname_port"`
+		Secret        Secret `yaml:"secret" validate:"required,min=16"`
+		Mode          string `yaml:"mode,omitempty" validate:"omitempty,oneof=merge replace"`
+	} `yaml:"webhook,omitempty"`
 	EmailTemplate struct {
 		Theme                   string `yaml:"theme,omitempty" validate:"omitempty"`
 		Language                string `yaml:"language" validate:"required,alpha"`
@@ -118,5 +225,91 @@ type yamlConfiguration struct {
 		IncludeMetadata    *bool  `yaml:"include_metadata,omitempty" validate:"omitempty,boolean"`
 		SaveEmailData      *bool  `yaml:"save_email_data,omitempty" validate:"omitempty,boolean"`
 	} `yaml:"dry-run,omitempty"`
-	Recipients []string `yaml:"recipients"          validate:"required"`
+	Recipients    []string           `yaml:"recipients"          validate:"required"`
+	JellyfinUsers []yamlJellyfinUser `yaml:"jellyfin_users,omitempty" validate:"omitempty,unique=Recipient,dive"`
+	Profiles      []yamlProfile      `yaml:"profiles,omitempty"  validate:"omitempty,unique=Name,dive"`
+}
+
+// yamlJellyfin is a Jellyfin server. The newsletter aggregates the items of all the servers.
+type yamlJellyfin struct {
+	Name                                string              `yaml:"name,omitempty" validate:"omitempty,profile_name"`
+	URL                                 string              `yaml:"url" validate:"required,http_url"`
+	APIToken                            Secret              `yaml:"api_token" validate:"required"`
+	WatchedFilmFolders                  []string            `yaml:"watched_film_folders,omitempty" validate:"required_unless=LibrarySelection auto"`
+	WatchedSeriesFolders                []string            `yaml:"watched_tv_folders,omitempty" validate:"required_unless=LibrarySelection auto"`
+	WatchedMusicFolders                 []string            `yaml:"watched_music_folders,omitempty"`
+	WatchedBookFolders                  []string            `yaml:"watched_book_folders,omitempty"`
+	LibrarySelection                    string              `yaml:"library_selection,omitempty" validate:"omitempty,oneof=folders auto"`
+	IncludeLibraries                    []string            `yaml:"include_libraries,omitempty"`
+	ExcludeLibraries                    []string            `yaml:"exclude_libraries,omitempty"`
+	ObservedPeriodDays                  int                 `yaml:"observed_period_days" validate:"required,numeric"`
+	IgnoreItemsAddedAfterLastNewsletter *bool               `yaml:"ignore_item_added_before_last_newsletter,omitempty" validate:"omitempty,boolean"`
+	NewItemDetection                    string              `yaml:"new_item_detection,omitempty" validate:"omitempty,oneof=date snapshot"`
+	AnnounceUpgrades                    *bool               `yaml:"announce_upgrades,omitempty" validate:"omitempty,boolean"`
+	AnnounceRemovedMedia                *bool               `yaml:"announce_removed_media,omitempty" validate:"omitempty,boolean"`
+	RemovedMediaFolders                 []string            `yaml:"removed_media_folders,omitempty"`
+	Exclude                             *yamlExclusionRules `yaml:"exclude,omitempty"`
+	PublicURL                           string              `yaml:"public_url,omitempty" validate:"omitempty,http_url"`
+	PublicImageURL                      string              `yaml:"public_image_url,omitempty" validate:"omitempty,http_url"`
+}
+
+// yamlJellyfinServers is the jellyfin section: a single server, or a list of servers.
+type yamlJellyfinServers []yamlJellyfin
+
+// UnmarshalYAML accepts a single server mapping, as well as a list of servers.
+func (servers *yamlJellyfinServers) UnmarshalYAML(unmarshal func(any) error) error {
+	var rawValue any
+	if err := unmarshal(&rawValue); err != nil {
+		return err
+	}
+	if _, isList := rawValue.([]any); isList {
+		return unmarshal((*[]yamlJellyfin)(servers))
+	}
+	var server yamlJellyfin
+	if err := unmarshal(&server); err != nil {
+		return err
+	}
+	*servers = yamlJellyfinServers{server}
+	return nil
+}
+
+// yamlExclusionRules are the movies and series never announced, see ExclusionRules.
+type yamlExclusionRules struct {
+	Tags            []string `yaml:"tags,omitempty"`
+	Genres          []string `yaml:"genres,omitempty"`
+	Paths           []string `yaml:"paths,omitempty" validate:"omitempty,dive,path_glob"`
+	OfficialRatings []string `yaml:"official_ratings,omitempty"`
+	ItemIDs         []string `yaml:"item_ids,omitempty"`
+	TMDBIds         []string `yaml:"tmdb_ids,omitempty" validate:"omitempty,dive,numeric"`
+}
+
+// yamlJellyfinUser maps a recipient to the Jellyfin user (name or ID) its newsletter is built for.
+type yamlJellyfinUser struct {
+	Recipient string `yaml:"recipient" validate:"required"`
+	User      string `yaml:"user" validate:"required"`
+}
+
+// yamlProfile is a newsletter profile. Every field but the name is optional and overrides the top-level value.
+type yamlProfile struct {
+	Name                 string   `yaml:"name" validate:"required,profile_name"`
+	Cron                 string   `yaml:"cron,omitempty" validate:"omitempty,cron"`
+	Recipients           []string `yaml:"recipients,omitempty"`
+	WatchedFilmFolders   []string `yaml:"watched_film_folders,omitempty"`
+	WatchedSeriesFolders []string `yaml:"watched_tv_folders,omitempty"`
+	WatchedMusicFolders  []string `yaml:"watched_music_folders,omitempty"`
+	WatchedBookFolders   []string `yaml:"watched_book_folders,omitempty"`
+	ObservedPeriodDays   int      `yaml:"observed_period_days,omitempty" validate:"omitempty,numeric,min=1"`
+	EmailTemplate        *struct {
+		Theme                   string `yaml:"theme,omitempty"`
+		Language                string `yaml:"language,omitempty" validate:"omitempty,alpha"`
+		Subject                 string `yaml:"subject,omitempty"`
+		Title                   string `yaml:"title,omitempty"`
+		Subtitle                string `yaml:"subtitle,omitempty"`
+		JellyfinURL             string `yaml:"jellyfin_url,omitempty" validate:"omitempty,url"`
+		UnsubscribeEmail        string `yaml:"unsubscribe_email,omitempty" validate:"omitempty,email"`
+		JellyfinOwnerName       string `yaml:"jellyfin_owner_name,omitempty"`
+		DisplayOverviewMaxItems *int   `yaml:"display_overview_max_items,omitempty" validate:"omitempty,numeric,min=-1"`
+		SortMode                string `yaml:"sort_mode,omitempty" validate:"omitempty,oneof=date_desc date_asc name_asc name_desc"`
+		MaxDisplayedItems       *int   `yaml:"max_displayed_items,omitempty" validate:"omitempty,numeric,min=0"`
+	} `yaml:"email_template,omitempty"`
 }
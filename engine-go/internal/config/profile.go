@@ -0,0 +1,138 @@
+package config
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Profile names are used in file names (e.g. LAST_NEWSLETTER_<name>.txt), so they are restricted to safe characters.
+const profileNamePattern = `^[a-zA-Z0-9_-]+$`
+
+// validateProfileName checks the profile name only contains letters, digits, '-' and '_'.
+func validateProfileName(fl validator.FieldLevel) bool {
+	return regexp.MustCompile(profileNamePattern).MatchString(fl.Field().String())
+}
+
+// validatePathGlob checks the exclusion path is a valid glob pattern (see path.Match).
+func validatePathGlob(fl validator.FieldLevel) bool {
+	_, err := path.Match(fl.Field().String(), "")
+	return err == nil
+}
+
+// newValidator returns the validator used to validate the configuration file, with the custom rules registered.
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	_ = validate.RegisterValidation("profile_name", validateProfileName) // Can only fail if the tag is empty
+	_ = validate.RegisterValidation("path_glob", validatePathGlob)
+	return validate
+}
+
+// buildProfilesConfig builds the newsletter profiles. Each profile starts from the top-level values of conf,
+// and overrides the ones it defines.
+func buildProfilesConfig(yamlParsedConfig *yamlConfiguration, conf *Configuration) []ProfileConfig {
+	profiles := make([]ProfileConfig, 0, len(yamlParsedConfig.Profiles))
+	for _, yamlProfile := range yamlParsedConfig.Profiles {
+		profile := ProfileConfig{
+			Name:                 yamlProfile.Name,
+			EmailRecipients:      conf.EmailRecipients,
+			CronExpr:             conf.Scheduler.CronExpr,
+			WatchedFilmFolders:   conf.Jellyfin.WatchedFilmFolders,
+			WatchedSeriesFolders: conf.Jellyfin.WatchedSeriesFolders,
+			WatchedMusicFolders:  conf.Jellyfin.WatchedMusicFolders,
+			WatchedBookFolders:   conf.Jellyfin.WatchedBookFolders,
+			ObservedPeriodDays:   conf.Jellyfin.ObservedPeriodDays,
+			EmailTemplate:        buildProfileEmailTemplateConfig(yamlProfile, conf.EmailTemplate),
+		}
+		if len(yamlProfile.Recipients) > 0 {
+			profile.EmailRecipients = yamlProfile.Recipients
+		}
+		if yamlProfile.Cron != "" {
+			profile.CronExpr = yamlProfile.Cron
+		}
+		// An empty list is a valid override, to disable movies or series for this profile
+		if yamlProfile.WatchedFilmFolders != nil {
+			profile.WatchedFilmFolders = yamlProfile.WatchedFilmFolders
+		}
+		if yamlProfile.WatchedSeriesFolders != nil {
+			profile.WatchedSeriesFolders = yamlProfile.WatchedSeriesFolders
+		}
+		if yamlProfile.WatchedMusicFolders != nil {
+			profile.WatchedMusicFolders = yamlProfile.WatchedMusicFolders
+		}
+		if yamlProfile.WatchedBookFolders != nil {
+			profile.WatchedBookFolders = yamlProfile.WatchedBookFolders
+		}
+		if yamlProfile.ObservedPeriodDays != 0 {
+			profile.ObservedPeriodDays = yamlProfile.ObservedPeriodDays
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+func buildProfileEmailTemplateConfig(
+	yamlProfile yamlProfile,
+	defaultEmailTemplate EmailTemplateConfig,
+) EmailTemplateConfig {
+	emailTemplateConfig := defaultEmailTemplate
+	override := yamlProfile.EmailTemplate
+	if override == nil {
+		return emailTemplateConfig
+	}
+
+	overrideString := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+	overrideString(&emailTemplateConfig.Theme, override.Theme)
+	overrideString(&emailTemplateConfig.Language, override.Language)
+	overrideString(&emailTemplateConfig.Subject, override.Subject)
+	overrideString(&emailTemplateConfig.Title, override.Title)
+	overrideString(&emailTemplateConfig.Subtitle, override.Subtitle)
+	overrideString(&emailTemplateConfig.JellyfinURL, override.JellyfinURL)
+	overrideString(&emailTemplateConfig.UnsubscribeEmail, override.UnsubscribeEmail)
+	overrideString(&emailTemplateConfig.JellyfinOwnerName, override.JellyfinOwnerName)
+	overrideString(&emailTemplateConfig.SortMode, override.SortMode)
+
+	if override.DisplayOverviewMaxItems != nil {
+		emailTemplateConfig.DisplayOverviewMaxItems = *override.DisplayOverviewMaxItems
+	}
+	if override.MaxDisplayedItems != nil {
+		emailTemplateConfig.MaxDisplayedItems = *override.MaxDisplayedItems
+	}
+	return emailTemplateConfig
+}
+
+// ForProfile returns a copy of the configuration where the newsletter settings are replaced by the profile ones.
+// The returned configuration can be used as-is by the newsletter workflow.
+// The profile folders only apply to the first Jellyfin server, the additional ones keep their own.
+func (conf *Configuration) ForProfile(profile ProfileConfig) *Configuration {
+	profileConf := *conf
+	profileConf.Profiles = nil
+	profileConf.ProfileName = profile.Name
+	profileConf.EmailRecipients = profile.EmailRecipients
+	profileConf.Scheduler.CronExpr = profile.CronExpr
+	profileConf.Jellyfin.WatchedFilmFolders = profile.WatchedFilmFolders
+	profileConf.Jellyfin.WatchedSeriesFolders = profile.WatchedSeriesFolders
+	profileConf.Jellyfin.WatchedMusicFolders = profile.WatchedMusicFolders
+	profileConf.Jellyfin.WatchedBookFolders = profile.WatchedBookFolders
+	profileConf.Jellyfin.ObservedPeriodDays = profile.ObservedPeriodDays
+	profileConf.EmailTemplate = profile.EmailTemplate
+	return &profileConf
+}
+
+// NewsletterConfigurations returns one configuration per newsletter to send:
+// one per profile, or the configuration itself if no profile is defined.
+func (conf *Configuration) NewsletterConfigurations() []*Configuration {
+	if len(conf.Profiles) == 0 {
+		return []*Configuration{conf}
+	}
+	configurations := make([]*Configuration, 0, len(conf.Profiles))
+	for _, profile := range conf.Profiles {
+		configurations = append(configurations, conf.ForProfile(profile))
+	}
+	return configurations
+}
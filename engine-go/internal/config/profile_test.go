@@ -0,0 +1,151 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesYAML = `
+profiles:
+  - name: family-fr
+    cron: "0 8 * * 1"
+    recipients:
+      - "family@example.com"
+    watched_tv_folders:
+      - /kids-series
+    observed_period_days: 7
+    email_template:
+      language: fr
+      subject: Nouveautés de la semaine
+  - name: friends
+    watched_tv_folders: []
+    email_template:
+      language: en
+      display_overview_max_items: 0
+`
+
+func TestLoadConfig_Profiles(t *testing.T) {
+	config, err := loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(validConfigYAML+profilesYAML),
+		nil,
+	)
+
+	require.NoError(t, err)
+	require.Len(t, config.Profiles, 2)
+
+	family := config.Profiles[0]
+	assert.Equal(t, "family-fr", family.Name)
+	assert.Equal(t, "0 8 * * 1", family.CronExpr)
+	assert.Equal(t, []string{"family@example.com"}, family.EmailRecipients)
+	assert.Equal(t, []string{"/movies"}, family.WatchedFilmFolders)
+	assert.Equal(t, []string{"/kids-series"}, family.WatchedSeriesFolders)
+	assert.Equal(t, 7, family.ObservedPeriodDays)
+	assert.Equal(t, "fr", family.EmailTemplate.Language)
+	assert.Equal(t, "Nouveautés de la semaine", family.EmailTemplate.Subject)
+	assert.Equal(t, "Newsletter", family.EmailTemplate.Title)
+
+	// Values not defined by the profile are inherited from the top-level configuration
+	friends := config.Profiles[1]
+	assert.Equal(t, "friends", friends.Name)
+	assert.Equal(t, "0 8 1 * *", friends.CronExpr)
+	assert.Equal(t, []string{"user1@example.com", "user2@example.com"}, friends.EmailRecipients)
+	assert.Equal(t, 30, friends.ObservedPeriodDays)
+	assert.Equal(t, []string{"/movies"}, friends.WatchedFilmFolders)
+	assert.Empty(t, friends.WatchedSeriesFolders)
+	assert.Equal(t, "en", friends.EmailTemplate.Language)
+	assert.Equal(t, "New releases", friends.EmailTemplate.Subject)
+	assert.Equal(t, 0, friends.EmailTemplate.DisplayOverviewMaxItems)
+	assert.Equal(t, "date_asc", friends.EmailTemplate.SortMode)
+}
+
+func TestConfiguration_NewsletterConfigurations(t *testing.T) {
+	config, err := loadConfigFromReader("./config/config.yml", strings.NewReader(validConfigYAML), nil)
+	require.NoError(t, err)
+
+	// Without profile, the configuration itself is the only newsletter
+	assert.Equal(t, []*Configuration{config}, config.NewsletterConfigurations())
+
+	config, err = loadConfigFromReader(
+		"./config/config.yml",
+		strings.NewReader(validConfigYAML+profilesYAML),
+		nil,
+	)
+	require.NoError(t, err)
+
+	newsletterConfigs := config.NewsletterConfigurations()
+	require.Len(t, newsletterConfigs, 2)
+	family := newsletterConfigs[0]
+	assert.Equal(t, "family-fr", family.ProfileName)
+	assert.Empty(t, family.Profiles)
+	assert.Equal(t, "0 8 * * 1", family.Scheduler.CronExpr)
+	assert.True(t, family.Scheduler.Enabled)
+	assert.Equal(t, []string{"family@example.com"}, family.EmailRecipients)
+	assert.Equal(t, []string{"/kids-series"}, family.Jellyfin.WatchedSeriesFolders)
+	assert.Equal(t, 7, family.Jellyfin.ObservedPeriodDays)
+	assert.Equal(t, "fr", family.EmailTemplate.Language)
+	// Shared connections
+	assert.Equal(t, config.Jellyfin.URL, family.Jellyfin.URL)
+	assert.Equal(t, config.SMTP, family.SMTP)
+	assert.Equal(t, config.TMDB, family.TMDB)
+	// The top-level configuration is not modified
+	assert.Equal(t, []string{"/series"}, config.Jellyfin.WatchedSeriesFolders)
+	assert.Equal(t, "0 8 1 * *", config.Scheduler.CronExpr)
+}
+
+func TestLoadConfig_InvalidProfiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		profilesYAML  string
+		expectedError string
+	}{
+		{
+			name:          "Missing name",
+			profilesYAML:  "\nprofiles:\n  - cron: \"0 8 * * 1\"\n",
+			expectedError: "Field validation for 'Name' failed on the 'required' tag",
+		},
+		{
+			name:          "Name with unsafe characters",
+			profilesYAML:  "\nprofiles:\n  - name: ../family\n",
+			expectedError: "Field validation for 'Name' failed on the 'profile_name' tag",
+		},
+		{
+			name:          "Duplicated names",
+			profilesYAML:  "\nprofiles:\n  - name: family\n  - name: family\n",
+			expectedError: "Field validation for 'Profiles' failed on the 'unique' tag",
+		},
+		{
+			name:          "Invalid cron",
+			profilesYAML:  "\nprofiles:\n  - name: family\n    cron: every monday\n",
+			expectedError: "Field validation for 'Cron' failed on the 'cron' tag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := loadConfigFromReader(
+				"./config/config.yml",
+				strings.NewReader(validConfigYAML+tt.profilesYAML),
+				nil,
+			)
+
+			require.Error(t, err)
+			assert.Nil(t, config)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestValidateConfigFile_ProfileProblemPosition(t *testing.T) {
+	configPath := writeConfigFile(t, validConfigYAML+"\nprofiles:\n  - name: family\n  - name: friends\n    cron: nope\n")
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "profiles[1].cron", report.Problems[0].Path)
+	assert.Equal(t, 57, report.Problems[0].Line)
+	assert.Equal(t, 5, report.Problems[0].Column)
+}
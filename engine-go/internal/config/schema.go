@@ -0,0 +1,271 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The JSON Schema of the configuration file is generated from yamlConfiguration: YAML keys come from the yaml tags
+// and constraints from the validate tags. This way, adding a field to yamlConfiguration updates the schema.
+// The generated schema is shipped in config/config.schema.json (see `make schema`), so YAML language servers
+// can autocomplete and validate config.yml in editors.
+
+const (
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+	jsonSchemaTitle = "Jellyfin Newsletter configuration"
+	// Same regex as the validator jwt rule.
+	jwtPattern = `^[A-Za-z0-9-_]+\.[A-Za-z0-9-_]+\.[A-Za-z0-9-_]*$`
+	// Same regex as the validator alpha rule.
+	alphaPattern   = `^[a-zA-Z]+$`
+	httpURLPattern = `^https?://`
+)
+
+type jsonSchema map[string]any
+
+// JSONSchema returns the JSON Schema (draft-07) of the configuration file, indented.
+func JSONSchema() ([]byte, error) {
+	schema := schemaForStruct(reflect.TypeFor[yamlConfiguration]())
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = jsonSchemaTitle
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// validationRules is a parsed validate tag.
+//   - rules apply to the field itself
+//   - itemRules apply to the items of a list (rules after `dive`)
+type validationRules struct {
+	omitEmpty bool
+	rules     []validationRule
+	itemRules []validationRule
+}
+
+type validationRule struct {
+	tag   string
+	param string
+}
+
+func parseValidateTag(tag string) validationRules {
+	parsedRules := validationRules{}
+	afterDive := false
+	for rawRule := range strings.SplitSeq(tag, ",") {
+		rawRule = strings.TrimSpace(rawRule)
+		switch rawRule {
+		case "":
+			continue
+		case "dive":
+			afterDive = true
+			continue
+		case "omitempty":
+			if !afterDive {
+				parsedRules.omitEmpty = true
+			}
+			continue
+		}
+		ruleTag, param, _ := strings.Cut(rawRule, "=")
+		rule := validationRule{tag: ruleTag, param: param}
+		if afterDive {
+			parsedRules.itemRules = append(parsedRules.itemRules, rule)
+		} else {
+			parsedRules.rules = append(parsedRules.rules, rule)
+		}
+	}
+	return parsedRules
+}
+
+func schemaForType(t reflect.Type) jsonSchema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeFor[yamlJellyfinServers]() {
+		// A single server, or a list of servers (see yamlJellyfinServers.UnmarshalYAML)
+		server := schemaForStruct(t.Elem())
+		return jsonSchema{"oneOf": []jsonSchema{server, {"type": "array", "items": server, "minItems": 1}}}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return schemaForStruct(t)
+	case reflect.Slice:
+		return jsonSchema{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Bool:
+		return jsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return jsonSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return jsonSchema{"type": "number"}
+	default:
+		return jsonSchema{"type": "string"}
+	}
+}
+
+// requiredSchema returns the schema requiring key. A secret can also be defined by its `<key>_file` variant.
+func requiredSchema(key string, isSecret bool) jsonSchema {
+	if !isSecret {
+		return jsonSchema{"required": []string{key}}
+	}
+	return jsonSchema{"anyOf": []jsonSchema{
+		{"required": []string{key}},
+		{"required": []string{key + secretFileSuffix}},
+	}}
+}
+
+func schemaForStruct(t reflect.Type) jsonSchema {
+	properties := jsonSchema{}
+	required := []string{}
+	conditions := []jsonSchema{}
+
+	for field := range t.Fields() {
+		key := yamlKeyFromTag(field)
+		if key == "" {
+			continue
+		}
+		parsedRules := parseValidateTag(field.Tag.Get("validate"))
+		fieldSchema := schemaForType(field.Type)
+		applyRules(fieldSchema, parsedRules.rules, field.Type, parsedRules.omitEmpty)
+		if items, isList := fieldSchema["items"].(jsonSchema); isList {
+			applyRules(items, parsedRules.itemRules, field.Type.Elem(), false)
+		}
+		properties[key] = fieldSchema
+
+		isSecret := field.Type == reflect.TypeFor[Secret]()
+		if isSecret {
+			properties[key+secretFileSuffix] = jsonSchema{"type": "string", "minLength": 1}
+			conditions = append(conditions, jsonSchema{
+				"not": jsonSchema{"required": []string{key, key + secretFileSuffix}},
+			})
+		}
+
+		for _, rule := range parsedRules.rules {
+			switch rule.tag {
+			case "required":
+				if isSecret {
+					conditions = append(conditions, requiredSchema(key, isSecret))
+				} else {
+					required = append(required, key)
+				}
+			case "required_if", "required_unless":
+				conditions = append(conditions, conditionalRequiredSchema(t, rule, requiredSchema(key, isSecret)))
+			}
+		}
+	}
+
+	schema := jsonSchema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(conditions) > 0 {
+		schema["allOf"] = conditions
+	}
+	return schema
+}
+
+// conditionalRequiredSchema translates required_if and required_unless rules (e.g. `required_unless=SMTPTlsType NONE`).
+func conditionalRequiredSchema(parentType reflect.Type, rule validationRule, required jsonSchema) jsonSchema {
+	fieldName, value, _ := strings.Cut(rule.param, " ")
+	siblingField, ok := parentType.FieldByName(fieldName)
+	if !ok {
+		return required
+	}
+	siblingKey := yamlKeyFromTag(siblingField)
+	condition := jsonSchema{
+		"properties": jsonSchema{siblingKey: jsonSchema{"const": typedValue(value, siblingField.Type)}},
+		"required":   []string{siblingKey},
+	}
+	if rule.tag == "required_if" {
+		return jsonSchema{"if": condition, "then": required}
+	}
+	return jsonSchema{"if": condition, "else": required}
+}
+
+// typedValue converts a validator param in the JSON type of the field.
+func typedValue(value string, t reflect.Type) any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	default:
+	}
+	return value
+}
+
+// applyRules adds the JSON Schema keywords matching the validator rules to schema.
+// Rules that can't be expressed in JSON Schema (cron, unique, dirpath ...) are ignored.
+func applyRules(schema jsonSchema, rules []validationRule, t reflect.Type, omitEmpty bool) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	constraints := jsonSchema{}
+	for _, rule := range rules {
+		switch rule.tag {
+		case "oneof":
+			enum := []any{}
+			for value := range strings.FieldsSeq(rule.param) {
+				enum = append(enum, typedValue(value, t))
+			}
+			constraints["enum"] = enum
+		case "min", "max":
+			applyBoundRule(schema, rule, t.Kind())
+		case "http_url":
+			constraints["format"] = "uri"
+			constraints["pattern"] = httpURLPattern
+		case "url":
+			constraints["format"] = "uri"
+		case "email":
+			constraints["format"] = "email"
+		case "jwt":
+			constraints["pattern"] = jwtPattern
+		case "alpha":
+			constraints["pattern"] = alphaPattern
+		case "profile_name":
+			constraints["pattern"] = profileNamePattern
+		case "hostname|ip":
+			constraints["anyOf"] = []jsonSchema{{"format": "hostname"}, {"format": "ipv4"}, {"format": "ipv6"}}
+		case "cron":
+			schema["description"] = "Cron expression. Test it on https://crontab.guru/"
+		}
+	}
+	if len(constraints) == 0 {
+		return
+	}
+	if omitEmpty && t.Kind() == reflect.String {
+		// The validator skips empty values when omitempty is set
+		schema["anyOf"] = []jsonSchema{{"const": ""}, constraints}
+		return
+	}
+	for keyword, value := range constraints {
+		schema[keyword] = value
+	}
+}
+
+func applyBoundRule(schema jsonSchema, rule validationRule, kind reflect.Kind) {
+	bound, err := strconv.Atoi(rule.param)
+	if err != nil {
+		return
+	}
+	keywords := map[reflect.Kind][2]string{
+		reflect.String: {"minLength", "maxLength"},
+		reflect.Slice:  {"minItems", "maxItems"},
+	}
+	keyword, ok := keywords[kind]
+	if !ok {
+		keyword = [2]string{"minimum", "maximum"}
+	}
+	if rule.tag == "min" {
+		schema[keyword[0]] = bound
+	} else {
+		schema[keyword[1]] = bound
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shippedSchemaPath = "../../../config/config.schema.json"
+
+func generatedSchema(t *testing.T) map[string]any {
+	t.Helper()
+	rawSchema, err := JSONSchema()
+	require.NoError(t, err)
+	schema := map[string]any{}
+	require.NoError(t, json.Unmarshal(rawSchema, &schema))
+	return schema
+}
+
+// schemaAt returns the sub schema at the given path of keys.
+func schemaAt(t *testing.T, schema map[string]any, path ...string) map[string]any {
+	t.Helper()
+	current := schema
+	for _, key := range path {
+		next, ok := current[key].(map[string]any)
+		require.True(t, ok, "missing key %s", key)
+		current = next
+	}
+	return current
+}
+
+func TestJSONSchema_ShippedSchemaIsUpToDate(t *testing.T) {
+	shippedSchema, err := os.ReadFile(shippedSchemaPath)
+	require.NoError(t, err)
+	rawSchema, err := JSONSchema()
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		string(rawSchema)+"\n",
+		string(shippedSchema),
+		"config/config.schema.json is outdated. Run `make schema` in engine-go to update it.",
+	)
+}
+
+func TestJSONSchema_ValidationRules(t *testing.T) {
+	schema := generatedSchema(t)
+
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.ElementsMatch(t, []any{"jellyfin", "email_template", "email", "recipients"}, schema["required"])
+
+	emailTemplate := schemaAt(t, schema, "properties", "email_template", "properties")
+	assert.Equal(t, map[string]any{"type": "integer", "minimum": float64(-1)}, emailTemplate["display_overview_max_items"])
+	assert.Equal(t, map[string]any{
+		"type": "string",
+		"anyOf": []any{
+			map[string]any{"const": ""},
+			map[string]any{"enum": []any{"date_desc", "date_asc", "name_asc", "name_desc"}},
+		},
+	}, emailTemplate["sort_mode"])
+
+	email := schemaAt(t, schema, "properties", "email")
+	tlsType := schemaAt(t, email, "properties", "smtp_tls_type")
+	assert.Contains(t, tlsType["anyOf"], map[string]any{"enum": []any{"TLS", "STARTTLS", "NONE"}})
+	smtpPort := schemaAt(t, email, "properties", "smtp_port")
+	assert.Equal(t, float64(1), smtpPort["minimum"])
+	assert.Equal(t, float64(65535), smtpPort["maximum"])
+	tmdb := schemaAt(t, schema, "properties", "tmdb", "properties")
+	assert.Equal(t, map[string]any{
+		"type":    "number",
+		"minimum": float64(0),
+		"maximum": float64(1),
+	}, tmdb["match_confidence_threshold"])
+	// smtp_username is required unless smtp_tls_type is NONE
+	assert.Contains(t, email["allOf"], map[string]any{
+		"if": map[string]any{
+			"properties": map[string]any{"smtp_tls_type": map[string]any{"const": "NONE"}},
+			"required":   []any{"smtp_tls_type"},
+		},
+		"else": map[string]any{"required": []any{"smtp_username"}},
+	})
+
+	// Secrets can be defined in a file instead
+	// jellyfin is a single server, or a list of servers
+	jellyfinForms, ok := schemaAt(t, schema, "properties", "jellyfin")["oneOf"].([]any)
+	require.True(t, ok)
+	require.Len(t, jellyfinForms, 2)
+	jellyfin, ok := jellyfinForms[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "array", jellyfinForms[1].(map[string]any)["type"])
+	assert.Equal(t, jellyfin, jellyfinForms[1].(map[string]any)["items"])
+	assert.Contains(t, schemaAt(t, jellyfin, "properties"), "api_token_file")
+	assert.Contains(t, jellyfin["allOf"], map[string]any{"anyOf": []any{
+		map[string]any{"required": []any{"api_token"}},
+		map[string]any{"required": []any{"api_token_file"}},
+	}})
+	assert.Equal(t, jwtPattern, schemaAt(t, schema, "properties", "tmdb", "properties", "api_key")["pattern"])
+
+	// dry-run.output_directory is required if dry-run.enabled is true
+	dryRun := schemaAt(t, schema, "properties", "dry-run")
+	assert.Contains(t, dryRun["allOf"], map[string]any{
+		"if": map[string]any{
+			"properties": map[string]any{"enabled": map[string]any{"const": true}},
+			"required":   []any{"enabled"},
+		},
+		"then": map[string]any{"required": []any{"output_directory"}},
+	})
+
+	profile := schemaAt(t, schema, "properties", "profiles", "items")
+	assert.Equal(t, []any{"name"}, profile["required"])
+	assert.Equal(t, profileNamePattern, schemaAt(t, profile, "properties", "name")["pattern"])
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+// Secrets can be loaded from files instead of being written in plain text in the config file.
+// For each secret key, a `<key>_file` variant is accepted. For example:
+//
+//	jellyfin:
+//	  api_token_file: /run/secrets/jellyfin_api_token
+//
+// The file content is read at load time and replaces the `<key>_file` entry before the validation,
+// so the secret is validated exactly like if it was written in the config file.
+
+const secretFileSuffix = "_file"
+
+// secretPaths lists the YAML paths of every config.Secret field that can be loaded from a file.
+func secretPaths() [][]string {
+	return [][]string{
+		{"jellyfin", "api_token"},
+		{"tmdb", "api_key"},
+		{"webhook", "secret"},
+		{"email", "smtp_password"},
+	}
+}
+
+// secretFileEnvOverrides returns the `<key>_file` keys, so they can also be set by environment variables.
+// For example JFN_JELLYFIN__API_TOKEN_FILE=/run/secrets/jellyfin_api_token.
+func secretFileEnvOverrides() []envOverride {
+	overrides := []envOverride{}
+	for _, path := range secretPaths() {
+		filePath := slices.Clone(path)
+		filePath[len(filePath)-1] += secretFileSuffix
+		overrides = append(overrides, envOverride{path: filePath, kind: reflect.TypeFor[string]()})
+	}
+	return overrides
+}
+
+// counterpartSecretPath returns the path of the other way to define the same secret.
+// jellyfin.api_token => jellyfin.api_token_file and jellyfin.api_token_file => jellyfin.api_token.
+// Returns nil if path is not a secret.
+func counterpartSecretPath(path []string) []string {
+	for _, secretPath := range secretPaths() {
+		if len(secretPath) != len(path) || !slices.Equal(secretPath[:len(path)-1], path[:len(path)-1]) {
+			continue
+		}
+		key := path[len(path)-1]
+		secretKey := secretPath[len(secretPath)-1]
+		counterpart := slices.Clone(path)
+		switch key {
+		case secretKey:
+			counterpart[len(counterpart)-1] = secretKey + secretFileSuffix
+			return counterpart
+		case secretKey + secretFileSuffix:
+			counterpart[len(counterpart)-1] = secretKey
+			return counterpart
+		}
+	}
+	return nil
+}
+
+// findMappingNode returns the mapping node at path, or nil if it doesn't exist.
+func findMappingNode(mapping *ast.MappingNode, path []string) *ast.MappingNode {
+	for _, key := range path {
+		mappingValue := findMappingValue(mapping, key)
+		if mappingValue == nil {
+			return nil
+		}
+		childMapping, ok := mappingValue.Value.(*ast.MappingNode)
+		if !ok {
+			return nil
+		}
+		mapping = childMapping
+	}
+	return mapping
+}
+
+// removeMappingValue removes the key at path from the YAML tree, if it exists.
+func removeMappingValue(rootMapping *ast.MappingNode, path []string) {
+	parent := findMappingNode(rootMapping, path[:len(path)-1])
+	if parent == nil {
+		return
+	}
+	key := path[len(path)-1]
+	parent.Values = slices.DeleteFunc(parent.Values, func(mappingValue *ast.MappingValueNode) bool {
+		return mappingValue.Key != nil && mappingValue.Key.String() == key
+	})
+}
+
+// readSecretFile reads a secret from a file. Trailing new lines are trimmed since most editors,
+// and `echo`, add one at the end of the file.
+func readSecretFile(secretFilePath string) (string, error) {
+	content, err := os.ReadFile(secretFilePath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// findSecretParents returns the mappings at path. The items of a list on the way are all returned,
+// e.g. every server of a jellyfin list.
+func findSecretParents(mapping *ast.MappingNode, path []string) []*ast.MappingNode {
+	if len(path) == 0 {
+		return []*ast.MappingNode{mapping}
+	}
+	mappingValue := findMappingValue(mapping, path[0])
+	if mappingValue == nil {
+		return nil
+	}
+	switch value := mappingValue.Value.(type) {
+	case *ast.MappingNode:
+		return findSecretParents(value, path[1:])
+	case *ast.SequenceNode:
+		parents := []*ast.MappingNode{}
+		for _, item := range value.Values {
+			if itemMapping, ok := item.(*ast.MappingNode); ok {
+				parents = append(parents, findSecretParents(itemMapping, path[1:])...)
+			}
+		}
+		return parents
+	default:
+		return nil
+	}
+}
+
+// resolveSecretFiles replaces every `<key>_file` entry of the YAML tree by `<key>` with the file content.
+func resolveSecretFiles(rootMapping *ast.MappingNode) error {
+	for _, path := range secretPaths() {
+		for _, parent := range findSecretParents(rootMapping, path[:len(path)-1]) {
+			if err := resolveSecretFile(parent, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveSecretFile replaces the `<key>_file` entry of parent, the mapping holding the secret at path.
+func resolveSecretFile(parent *ast.MappingNode, path []string) error {
+	key := path[len(path)-1]
+	fileMappingValue := findMappingValue(parent, key+secretFileSuffix)
+	if fileMappingValue == nil {
+		return nil
+	}
+	dotNotation := strings.Join(path, ".")
+	if findMappingValue(parent, key) != nil {
+		return fmt.Errorf(
+			"%s and %s are both defined. Only one of them must be used",
+			dotNotation,
+			dotNotation+secretFileSuffix,
+		)
+	}
+
+	secretFileNode, ok := fileMappingValue.Value.(*ast.StringNode)
+	if !ok || strings.TrimSpace(secretFileNode.Value) == "" {
+		return fmt.Errorf("%s must be a non empty file path", dotNotation+secretFileSuffix)
+	}
+	secret, err := readSecretFile(strings.TrimSpace(secretFileNode.Value))
+	if err != nil {
+		return fmt.Errorf("failed to read the secret file of %s: %w", dotNotation, err)
+	}
+
+	removeMappingValue(parent, []string{key + secretFileSuffix})
+	if err = setNodeValue(parent, []string{key}, secret); err != nil {
+		return fmt.Errorf("failed to load the secret file of %s: %w", dotNotation, err)
+	}
+	return nil
+}
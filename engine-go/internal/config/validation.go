@@ -0,0 +1,337 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// Problem is a configuration issue found by ValidateConfigFile.
+//   - Line and Column are the position in the config file. They equal 0 if the position is unknown
+//   - Path is the dot notation of the faulty key (e.g. jellyfin.url). Can be empty
+//   - Source is set when the value comes from an environment variable
+type Problem struct {
+	Line    int
+	Column  int
+	Path    string
+	Source  string
+	Message string
+}
+
+func (p Problem) String() string {
+	var sb strings.Builder
+	if p.Line > 0 {
+		fmt.Fprintf(&sb, "line %d, column %d: ", p.Line, p.Column)
+	}
+	if p.Path != "" {
+		fmt.Fprintf(&sb, "%s ", p.Path)
+	}
+	if p.Source != "" {
+		fmt.Fprintf(&sb, "(set by %s) ", p.Source)
+	}
+	sb.WriteString(p.Message)
+	return sb.String()
+}
+
+// ValidationReport is the result of ValidateConfigFile.
+// Config is nil if the file couldn't be decoded at all. Otherwise it is built even if problems are found,
+// so the caller can run further checks (theme, language ...) and report them with AddProblem.
+type ValidationReport struct {
+	Config         *Configuration
+	Problems       []Problem
+	rootMapping    *ast.MappingNode
+	appliedEnvVars map[string]string
+}
+
+// ValidateConfigFile loads the configuration file like LoadConfig does, but instead of stopping at the first
+// error, it collects every problem it can find, with their position in the file.
+func ValidateConfigFile(configPath string, themesDir string, lookupEnv LookupEnvFunc) *ValidationReport {
+	report := &ValidationReport{}
+	file, err := os.Open(configPath)
+	if err != nil {
+		report.Problems = append(report.Problems, Problem{Message: err.Error()})
+		return report
+	}
+	defer file.Close()
+
+	document, appliedEnvVars, err := parseYamlDocument(file, lookupEnv)
+	if err != nil {
+		report.Problems = append(report.Problems, problemFromYamlError(err))
+		return report
+	}
+	report.rootMapping, _ = document.Body.(*ast.MappingNode)
+	report.appliedEnvVars = appliedEnvVars
+
+	// Decoding without validator only reports structural errors (unknown keys, wrong types ...).
+	// The validation is done afterwards on the whole struct, to get all the validation errors at once.
+	yamlParsedConfig, faultySections, err := report.decodeSections(document.Body)
+	if err != nil {
+		report.Problems = append(report.Problems, problemFromYamlError(err))
+		return report
+	}
+
+	report.validateYamlConfiguration(yamlParsedConfig, faultySections)
+	if len(faultySections) > 0 {
+		// The configuration can't be built without the faulty sections
+		return report
+	}
+
+	report.Config = buildConfiguration(configPath, yamlParsedConfig)
+	report.Config.loadThemesDir(themesDir)
+	return report
+}
+
+// AddProblem adds a problem on the key at path (dot notation). Its position in the file is resolved automatically.
+func (report *ValidationReport) AddProblem(dotNotation string, message string) {
+	problem := Problem{
+		Path:    dotNotation,
+		Message: message,
+	}
+	if position := findClosestPosition(report.rootMapping, strings.Split(dotNotation, ".")); position != nil {
+		problem.Line = position.Line
+		problem.Column = position.Column
+	}
+	if envName, ok := report.appliedEnvVars[dotNotation]; ok {
+		problem.Source = envName
+	} else if envName, ok = report.appliedEnvVars[dotNotation+secretFileSuffix]; ok {
+		problem.Source = envName
+	}
+	report.Problems = append(report.Problems, problem)
+}
+
+func problemFromYamlError(err error) Problem {
+	var yamlErr yaml.Error
+	if errors.As(err, &yamlErr) && yamlErr.GetToken() != nil && yamlErr.GetToken().Position != nil {
+		return Problem{
+			Line:    yamlErr.GetToken().Position.Line,
+			Column:  yamlErr.GetToken().Position.Column,
+			Message: yamlErr.GetMessage(),
+		}
+	}
+	return Problem{Message: err.Error()}
+}
+
+// decodeSections decodes body section by section (root key by root key), so a structural error in a section
+// doesn't hide the problems of the other ones. The structural errors are added to the report, and the faulty
+// sections are left out of the returned configuration. An error is returned if body is not a mapping and
+// can't be decoded.
+func (report *ValidationReport) decodeSections(body ast.Node) (*yamlConfiguration, map[string]bool, error) {
+	yamlParsedConfig := &yamlConfiguration{}
+	faultySections := map[string]bool{}
+	if report.rootMapping == nil {
+		decoder := yaml.NewDecoder(strings.NewReader(""), yaml.Strict())
+		return yamlParsedConfig, faultySections, decoder.DecodeFromNode(body, yamlParsedConfig)
+	}
+
+	validSections := []*ast.MappingValueNode{}
+	for _, section := range report.rootMapping.Values {
+		decoder := yaml.NewDecoder(strings.NewReader(""), yaml.Strict())
+		sectionMapping := ast.Mapping(report.rootMapping.GetToken(), false, section)
+		if err := decoder.DecodeFromNode(sectionMapping, &yamlConfiguration{}); err != nil {
+			report.Problems = append(report.Problems, problemFromYamlError(err))
+			if section.Key != nil {
+				faultySections[section.Key.String()] = true
+			}
+			continue
+		}
+		validSections = append(validSections, section)
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(""), yaml.Strict())
+	validMapping := ast.Mapping(report.rootMapping.GetToken(), false, validSections...)
+	return yamlParsedConfig, faultySections, decoder.DecodeFromNode(validMapping, yamlParsedConfig)
+}
+
+// validateYamlConfiguration adds the validation errors to the report. The errors of the faulty sections,
+// already reported as structural errors, are ignored.
+func (report *ValidationReport) validateYamlConfiguration(
+	yamlParsedConfig *yamlConfiguration,
+	faultySections map[string]bool,
+) {
+	validate := newValidator()
+	// Use YAML keys in errors namespace, so errors can be mapped to the config file.
+	validate.RegisterTagNameFunc(yamlKeyFromTag)
+
+	err := validate.Struct(yamlParsedConfig)
+	if err == nil {
+		return
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		report.Problems = append(report.Problems, Problem{Message: err.Error()})
+		return
+	}
+
+	for _, fieldErr := range validationErrors {
+		// Namespace is yamlConfiguration.<yaml path>
+		_, dotNotation, _ := strings.Cut(fieldErr.Namespace(), ".")
+		rootSegment, _, _ := strings.Cut(dotNotation, ".")
+		if sectionKey, _, _ := splitIndexedKey(rootSegment); faultySections[sectionKey] {
+			continue
+		}
+		report.AddProblem(report.withoutSingleItemIndex(dotNotation), humanizeFieldError(fieldErr))
+	}
+}
+
+// withoutSingleItemIndex removes the index of a root key written as a single item instead of a list
+// (see yamlJellyfinServers), so jellyfin[0].url is reported as jellyfin.url.
+func (report *ValidationReport) withoutSingleItemIndex(dotNotation string) string {
+	rootSegment, rest, _ := strings.Cut(dotNotation, ".")
+	key, index, hasIndex := splitIndexedKey(rootSegment)
+	if !hasIndex || index != 0 || report.rootMapping == nil {
+		return dotNotation
+	}
+	mappingValue := findMappingValue(report.rootMapping, key)
+	if mappingValue == nil {
+		return dotNotation
+	}
+	if _, isMapping := mappingValue.Value.(*ast.MappingNode); !isMapping {
+		return dotNotation
+	}
+	if rest == "" {
+		return key
+	}
+	return key + "." + rest
+}
+
+// findClosestPosition returns the position of the key at path. If the key doesn't exist,
+// the position of the closest existing parent is returned. Returns nil for a root key that doesn't exist.
+// Keys of list items are suffixed by the item index, like in validator namespaces (e.g. profiles[1]).
+func findClosestPosition(mapping *ast.MappingNode, path []string) *tokenPosition {
+	var closest *tokenPosition
+	for _, segment := range path {
+		if mapping == nil {
+			break
+		}
+		key, index, hasIndex := splitIndexedKey(segment)
+		mappingValue := findMappingValue(mapping, key)
+		if mappingValue == nil {
+			break
+		}
+		if tk := mappingValue.Key.GetToken(); tk != nil && tk.Position != nil {
+			closest = &tokenPosition{Line: tk.Position.Line, Column: tk.Position.Column}
+		}
+		value := mappingValue.Value
+		if hasIndex {
+			sequence, ok := value.(*ast.SequenceNode)
+			if !ok || index >= len(sequence.Values) {
+				break
+			}
+			value = sequence.Values[index]
+			if tk := value.GetToken(); tk != nil && tk.Position != nil {
+				closest = &tokenPosition{Line: tk.Position.Line, Column: tk.Position.Column}
+			}
+		}
+		mapping, _ = value.(*ast.MappingNode)
+	}
+	return closest
+}
+
+// splitIndexedKey splits a key like profiles[1] in its name and index.
+func splitIndexedKey(segment string) (string, int, bool) {
+	key, rawIndex, found := strings.Cut(segment, "[")
+	if !found {
+		return segment, 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimSuffix(rawIndex, "]"))
+	if err != nil || index < 0 {
+		return key, 0, false
+	}
+	return key, index, true
+}
+
+type tokenPosition struct {
+	Line   int
+	Column int
+}
+
+// siblingYamlKey converts the Go field name used in a validator param (e.g. `required_unless=SMTPTlsType NONE`)
+// into its YAML key (e.g. smtp_tls_type), using the struct namespace of the failing field.
+func siblingYamlKey(structNamespace string, goFieldName string) string {
+	segments := strings.Split(structNamespace, ".")
+	parentType := reflect.TypeFor[yamlConfiguration]()
+	// segments[0] is the root struct name, and the last segment is the field itself
+	for _, segment := range segments[1 : len(segments)-1] {
+		fieldName, _, _ := splitIndexedKey(segment)
+		field, ok := parentType.FieldByName(fieldName)
+		if !ok {
+			return goFieldName
+		}
+		parentType = field.Type
+		if parentType.Kind() == reflect.Slice {
+			parentType = parentType.Elem()
+		}
+		if parentType.Kind() == reflect.Pointer {
+			parentType = parentType.Elem()
+		}
+	}
+	if field, ok := parentType.FieldByName(goFieldName); ok {
+		return yamlKeyFromTag(field)
+	}
+	return goFieldName
+}
+
+// humanizeFieldError translates a validator error into a human readable message.
+func humanizeFieldError(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "required_unless", "required_if":
+		fieldName, value, _ := strings.Cut(param, " ")
+		condition := "unless"
+		if fieldErr.Tag() == "required_if" {
+			condition = "when"
+		}
+		return fmt.Sprintf("is required %s %s is %s", condition, siblingYamlKey(fieldErr.StructNamespace(), fieldName), value)
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "min":
+		if fieldErr.Kind() == reflect.String {
+			return "must be at least " + param + " characters long"
+		}
+		return "must be greater than or equal to " + param
+	case "max":
+		if fieldErr.Kind() == reflect.String {
+			return "must be at most " + param + " characters long"
+		}
+		return "must be lower than or equal to " + param
+	case "http_url":
+		return "must be a valid http:// or https:// URL"
+	case "url":
+		return "must be a valid URL"
+	case "email":
+		return "must be a valid email address"
+	case "jwt":
+		return "must be a valid JWT. Use the TMDB 'API Read Access Token', not the 'API Key'"
+	case "cron":
+		return "must be a valid cron expression. Test it on https://crontab.guru/"
+	case "hostname|ip":
+		return "must be a valid hostname or IP address"
+	case "hostname_port":
+		return "must be a host and a port, e.g. 0.0.0.0:8080"
+	case "path_glob":
+		return "must be a valid glob pattern, e.g. /media/private/*"
+	case "dirpath":
+		return "must be a valid directory path"
+	case "alpha":
+		return "must only contain letters"
+	case "numeric":
+		return "must be a number"
+	case "boolean":
+		return "must be true or false"
+	case "unique":
+		return "must not contain two items with the same " + strings.ToLower(param)
+	case "profile_name":
+		return "must only contain letters, digits, '-' and '_'"
+	default:
+		return fmt.Sprintf("failed on the '%s' validation rule", fieldErr.Tag())
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0600))
+	return configPath
+}
+
+func TestValidateConfigFile_ValidConfig(t *testing.T) {
+	configPath := writeConfigFile(t, validConfigYAML)
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	assert.Empty(t, report.Problems)
+	require.NotNil(t, report.Config)
+	assert.Equal(t, "http://localhost:8096", report.Config.Jellyfin.URL)
+}
+
+func TestValidateConfigFile_ReportsEveryProblem(t *testing.T) {
+	yamlConfig := strings.Replace(validConfigYAML, "url: http://localhost:8096", "url: localhost", 1)
+	yamlConfig = strings.Replace(yamlConfig, "smtp_port: 587", "smtp_port: 70000", 1)
+	yamlConfig = RemoveYamlPartHelper(yamlConfig, "email.smtp_username")
+	configPath := writeConfigFile(t, yamlConfig)
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	require.NotNil(t, report.Config)
+	assert.Equal(t, []Problem{
+		{Line: 11, Column: 3, Path: "jellyfin.url", Message: "must be a valid http:// or https:// URL"},
+		{Line: 25, Column: 3, Path: "email.smtp_port", Message: "must be lower than or equal to 65535"},
+		{Line: 23, Column: 1, Path: "email.smtp_username", Message: "is required unless smtp_tls_type is NONE"},
+	}, report.Problems)
+}
+
+func TestValidateConfigFile_ProblemInJellyfinServersList(t *testing.T) {
+	servers := strings.Replace(jellyfinServersYAML, "    api_token_file: %s\n", "    api_token: cabin-secret\n", 1)
+	servers = strings.Replace(servers, "url: http://cabin:8096", "url: cabin", 1)
+	configPath := writeConfigFile(t, withJellyfinServers(servers))
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "jellyfin[1].url", report.Problems[0].Path)
+	assert.Equal(t, "must be a valid http:// or https:// URL", report.Problems[0].Message)
+	assert.Positive(t, report.Problems[0].Line)
+}
+
+func TestValidateConfigFile_ProblemFromEnv(t *testing.T) {
+	configPath := writeConfigFile(t, validConfigYAML)
+	env := map[string]string{"JFN_EMAIL__SMTP_PORT": "70000"}
+
+	report := ValidateConfigFile(configPath, "./themes", mapLookupEnv(env))
+
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "JFN_EMAIL__SMTP_PORT", report.Problems[0].Source)
+	assert.Equal(
+		t,
+		"line 24, column 3: email.smtp_port (set by JFN_EMAIL__SMTP_PORT) must be lower than or equal to 65535",
+		report.Problems[0].String(),
+	)
+}
+
+func TestValidateConfigFile_UnknownKey(t *testing.T) {
+	yamlConfig := strings.Replace(validConfigYAML, "jellyfin:\n", "jellyfin:\n  unknown_key: true\n", 1)
+	configPath := writeConfigFile(t, yamlConfig)
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	assert.Nil(t, report.Config)
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, 10, report.Problems[0].Line)
+	assert.Contains(t, report.Problems[0].Message, "unknown_key")
+}
+
+func TestValidateConfigFile_StructuralAndValidationProblems(t *testing.T) {
+	yamlConfig := strings.Replace(validConfigYAML, "jellyfin:\n", "jellyfin:\n  unknown_key: true\n", 1)
+	yamlConfig = strings.Replace(yamlConfig, "smtp_port: 587", "smtp_port: [587]", 1)
+	webhookYAML := "webhook:\n  listen_address: localhost\n  secret: short\n\n"
+	yamlConfig = strings.Replace(yamlConfig, "tmdb:\n", webhookYAML+"tmdb:\n", 1)
+	configPath := writeConfigFile(t, yamlConfig)
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	assert.Nil(t, report.Config)
+	require.Len(t, report.Problems, 4)
+	assert.Equal(t, 10, report.Problems[0].Line)
+	assert.Contains(t, report.Problems[0].Message, "unknown_key")
+	assert.Contains(t, report.Problems[1].Message, "Email")
+	assert.Equal(t, 29, report.Problems[1].Line)
+	assert.Equal(t, []Problem{
+		{Line: 21, Column: 3, Path: "webhook.listen_address", Message: "must be a host and a port, e.g. 0.0.0.0:8080"},
+		{Line: 22, Column: 3, Path: "webhook.secret", Message: "must be at least 16 characters long"},
+	}, report.Problems[2:])
+}
+
+func TestValidateConfigFile_InvalidExclusionPath(t *testing.T) {
+	yamlConfig := strings.Replace(
+		validConfigYAML, "jellyfin:\n", "jellyfin:\n  exclude:\n    paths:\n      - \"/media/[private\"\n", 1,
+	)
+	configPath := writeConfigFile(t, yamlConfig)
+
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "jellyfin.exclude.paths[0]", report.Problems[0].Path)
+	assert.Equal(t, "must be a valid glob pattern, e.g. /media/private/*", report.Problems[0].Message)
+}
+
+func TestValidationReport_AddProblem(t *testing.T) {
+	configPath := writeConfigFile(t, validConfigYAML)
+	report := ValidateConfigFile(configPath, "./themes", nil)
+
+	report.AddProblem("email_template.theme", "theme is not available")
+
+	assert.Equal(t, []Problem{
+		{Line: 31, Column: 3, Path: "email_template.theme", Message: "theme is not available"},
+	}, report.Problems)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+)
+
+const DefaultWatchInterval = 10 * time.Second
+
+// fileFingerprint reads the file and returns a hash of its content.
+// Polling the content is used instead of inotify because inotify is unreliable with
+// docker bind mounts and Kubernetes ConfigMaps (which are updated by swapping a symlink).
+func fileFingerprint(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(content)
+	return fingerprint[:], nil
+}
+
+// WatchFile polls the file every interval and calls onChange each time its content changes.
+// Errors while reading the file (e.g. the file is being replaced) are ignored, the next tick will try again.
+// WatchFile blocks until ctx is cancelled.
+func WatchFile(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	lastFingerprint, _ := fileFingerprint(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fingerprint, err := fileFingerprint(path)
+			if err != nil || bytes.Equal(fingerprint, lastFingerprint) {
+				continue
+			}
+			lastFingerprint = fingerprint
+			onChange()
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("recipients: []"), 0600))
+
+	changes := make(chan struct{}, 10)
+	go WatchFile(t.Context(), configPath, 10*time.Millisecond, func() { changes <- struct{}{} })
+
+	// Same content: no change must be reported
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(configPath, []byte("recipients: []"), 0600))
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, changes)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("recipients: [a@example.com]"), 0600))
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("config file change not detected")
+	}
+}
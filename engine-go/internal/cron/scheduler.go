@@ -1,30 +1,304 @@
 package cron
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/newsletter"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/template"
 	"github.com/go-co-op/gocron/v2"
 	"go.uber.org/zap"
 )
 
+var ErrSchedulerDisabled = errors.New(
+	"the scheduler section has been removed from the configuration. Disabling the scheduler requires a restart",
+)
+
+// WorkflowBuilder builds the newsletter workflow (and its API clients) from an application context.
+// It is called before each run so the clients always use the latest loaded configuration.
+type WorkflowBuilder func(app *app.ApplicationContext) newsletter.Workflow
+
+// schedulerState is the configuration used by the scheduler. It is replaced as a whole on reload.
+//   - app is the top-level application context
+//   - newsletters are the contexts of each newsletter to send, one per profile (see app.ApplicationContext.NewsletterContexts)
+type schedulerState struct {
+	app         *app.ApplicationContext
+	newsletters []*app.ApplicationContext
+}
+
+func (state *schedulerState) newsletter(profileName string) *app.ApplicationContext {
+	for _, newsletterApp := range state.newsletters {
+		if newsletterApp.Config.ProfileName == profileName {
+			return newsletterApp
+		}
+	}
+	return nil
+}
+
+// NewsletterScheduler runs the newsletter workflow of each profile on its own cron expression.
+// The configuration is stored in an atomic pointer. On reload, a brand new state is built
+// and swapped, so a running newsletter keeps the context it started with.
+type NewsletterScheduler struct {
+	scheduler gocron.Scheduler
+	// jobs are indexed by profile name. The name is empty if no profile is defined.
+	jobs          map[string]gocron.Job
+	current       atomic.Pointer[schedulerState]
+	buildWorkflow WorkflowBuilder
+	themesDir     string
+	reloadMutex   sync.Mutex
+}
+
+// CreateNewsletterScheduler registers one job per newsletter profile.
 func CreateNewsletterScheduler(
-	newsletterWorkflow newsletter.Workflow,
-	app *app.ApplicationContext,
-) (gocron.Scheduler, gocron.Job, error) {
-	scheduler, err := gocron.NewScheduler()
+	buildWorkflow WorkflowBuilder,
+	rootApp *app.ApplicationContext,
+	themesDir string,
+) (*NewsletterScheduler, error) {
+	newsletterScheduler := &NewsletterScheduler{
+		jobs:          map[string]gocron.Job{},
+		buildWorkflow: buildWorkflow,
+		themesDir:     themesDir,
+	}
+
+	newsletterApps, err := rootApp.NewsletterContexts()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	state := &schedulerState{app: rootApp, newsletters: newsletterApps}
+	newsletterScheduler.current.Store(state)
 
-	job, err := scheduler.NewJob(
-		gocron.CronJob(app.Config.Scheduler.CronExpr, false),
-		gocron.NewTask(newsletterWorkflow.Run, app),
-	)
+	newsletterScheduler.scheduler, err = gocron.NewScheduler()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = newsletterScheduler.syncJobs(nil, state); err != nil {
+		return nil, err
+	}
+	return newsletterScheduler, nil
+}
+
+func (s *NewsletterScheduler) runNewsletter(profileName string) {
+	newsletterApp := s.current.Load().newsletter(profileName)
+	if newsletterApp == nil {
+		// The profile has been removed by a reload while the job was triggered
+		return
+	}
+	s.buildWorkflow(newsletterApp).Run(newsletterApp)
+}
+
+// scheduleJob schedules the newsletter of profileName on cronExpr. existingJob is updated if not nil,
+// else a new job is created.
+func (s *NewsletterScheduler) scheduleJob(
+	existingJob gocron.Job,
+	profileName string,
+	cronExpr string,
+) (gocron.Job, error) {
+	jobOptions := []gocron.JobOption{}
+	if profileName != "" {
+		jobOptions = append(jobOptions, gocron.WithName(profileName))
+	}
+	jobDefinition := gocron.CronJob(cronExpr, false)
+	task := gocron.NewTask(s.runNewsletter, profileName)
+	if existingJob == nil {
+		return s.scheduler.NewJob(jobDefinition, task, jobOptions...)
+	}
+	return s.scheduler.Update(existingJob.ID(), jobDefinition, task, jobOptions...)
+}
+
+// syncJobs creates, updates and removes the scheduled jobs so there is exactly one job per newsletter of newState.
+// previousState is nil when the scheduler is created.
+// If a job can't be scheduled, the jobs already changed are restored as in previousState, so the jobs keep
+// matching the state in use.
+func (s *NewsletterScheduler) syncJobs(previousState, newState *schedulerState) error {
+	jobs := maps.Clone(s.jobs)
+	// Undo the changes already applied to the scheduler, in reverse order. Errors are ignored: the schedule
+	// is restored on a best effort basis.
+	rollbacks := []func(){}
+	rollback := func() {
+		for i := len(rollbacks) - 1; i >= 0; i-- {
+			rollbacks[i]()
+		}
+	}
+	previousCronExpr := func(profileName string) (string, bool) {
+		if previousState == nil || previousState.newsletter(profileName) == nil {
+			return "", false
+		}
+		return previousState.newsletter(profileName).Config.Scheduler.CronExpr, true
+	}
+
+	for _, newsletterApp := range newState.newsletters {
+		profileName := newsletterApp.Config.ProfileName
+		cronExpr := newsletterApp.Config.Scheduler.CronExpr
+		existingJob, exists := jobs[profileName]
+		previousExpr, wasScheduled := previousCronExpr(profileName)
+		if exists && wasScheduled && previousExpr == cronExpr {
+			continue
+		}
+		// A job without previous newsletter is rescheduled, as its cron expression is unknown
+
+		job, err := s.scheduleJob(existingJob, profileName, cronExpr)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("failed to schedule the newsletter of profile '%s': %w", profileName, err)
+		}
+		jobs[profileName] = job
+		switch {
+		case !exists:
+			rollbacks = append(rollbacks, func() { _ = s.scheduler.RemoveJob(job.ID()) })
+		case wasScheduled:
+			rollbacks = append(rollbacks, func() {
+				if restoredJob, restoreErr := s.scheduleJob(job, profileName, previousExpr); restoreErr == nil {
+					s.jobs[profileName] = restoredJob
+				}
+			})
+		}
+	}
+
+	for profileName, job := range jobs {
+		if newState.newsletter(profileName) != nil {
+			continue
+		}
+		if err := s.scheduler.RemoveJob(job.ID()); err != nil {
+			rollback()
+			return fmt.Errorf("failed to remove the schedule of profile '%s': %w", profileName, err)
+		}
+		delete(jobs, profileName)
+		if previousExpr, wasScheduled := previousCronExpr(profileName); wasScheduled {
+			rollbacks = append(rollbacks, func() {
+				if restoredJob, restoreErr := s.scheduleJob(nil, profileName, previousExpr); restoreErr == nil {
+					s.jobs[profileName] = restoredJob
+				}
+			})
+		}
+	}
+
+	s.jobs = jobs
+	return nil
+}
+
+// App returns the top-level application context currently in use.
+func (s *NewsletterScheduler) App() *app.ApplicationContext {
+	return s.current.Load().app
+}
+
+// Newsletters returns the context of each newsletter currently scheduled, one per profile.
+func (s *NewsletterScheduler) Newsletters() []*app.ApplicationContext {
+	return s.current.Load().newsletters
+}
+
+func (s *NewsletterScheduler) Start() {
+	s.scheduler.Start()
+	s.logNextRuns(s.current.Load())
+}
+
+func (s *NewsletterScheduler) logNextRuns(state *schedulerState) {
+	for _, newsletterApp := range state.newsletters {
+		LogNextRun(s.jobs[newsletterApp.Config.ProfileName], newsletterApp)
+	}
+}
+
+// Reload loads the configuration file again and swaps the application contexts.
+// If the new configuration is invalid, it is rejected and the previous configuration is kept.
+func (s *NewsletterScheduler) Reload() error {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	currentState := s.current.Load()
+	currentApp := currentState.app
+	currentApp.Logger.Info("Reloading configuration ...", zap.String("path", currentApp.Config.ConfigFilePath))
+
+	newState, err := s.buildReloadedState(currentApp)
+	if err != nil {
+		currentApp.Logger.Error(
+			"Configuration reload failed. The previous configuration is kept.",
+			zap.String("path", currentApp.Config.ConfigFilePath),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if err = s.syncJobs(currentState, newState); err != nil {
+		currentApp.Logger.Error(
+			"Configuration reload failed. Impossible to update the scheduled jobs. The previous configuration is kept.",
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if newState.app.Config.Log != currentApp.Config.Log {
+		currentApp.Logger.Warn("Log settings changed. They will only be applied after a restart.")
+	}
+	if newState.app.Config.Webhook != currentApp.Config.Webhook {
+		currentApp.Logger.Warn("Webhook settings changed. They will only be applied after a restart.")
+	}
+
+	s.current.Store(newState)
+	newState.app.Logger.Info("Configuration reloaded successfully.")
+	s.logNextRuns(newState)
+	return nil
+}
+
+// buildReloadedState loads and validates the configuration file, then builds the new application contexts.
+// Logger and clock are kept from the current context.
+func (s *NewsletterScheduler) buildReloadedState(currentApp *app.ApplicationContext) (*schedulerState, error) {
+	newConfig, err := config.LoadConfig(currentApp.Config.ConfigFilePath, s.themesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !newConfig.Scheduler.Enabled {
+		return nil, ErrSchedulerDisabled
+	}
+
+	localizer, err := i18n.NewLocalizer(newConfig.EmailTemplate.Language)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return scheduler, job, nil
+	newApp := currentApp.ReloadedContext(newConfig, localizer)
+	newsletterApps, err := newApp.NewsletterContexts()
+	if err != nil {
+		return nil, err
+	}
+	for _, newsletterApp := range newsletterApps {
+		if err = template.CheckIfThemeIsAvailable(newsletterApp); err != nil {
+			return nil, err
+		}
+	}
+	return &schedulerState{app: newApp, newsletters: newsletterApps}, nil
+}
+
+// WatchConfigChanges reloads the configuration each time the config file changes or a SIGHUP is received.
+// It blocks until ctx is cancelled.
+func (s *NewsletterScheduler) WatchConfigChanges(ctx context.Context) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	go config.WatchFile(ctx, s.App().Config.ConfigFilePath, config.DefaultWatchInterval, func() {
+		_ = s.Reload() // Errors are already logged
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			s.App().Logger.Info("SIGHUP received.")
+			_ = s.Reload() // Errors are already logged
+		}
+	}
 }
 
 func LogNextRun(job gocron.Job, app *app.ApplicationContext) {
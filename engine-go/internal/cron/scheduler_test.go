@@ -0,0 +1,133 @@
+package cron
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const reloadedConfigYAML = `
+scheduler:
+  cron: "0 8 1 * *"
+
+jellyfin:
+  url: http://localhost:8096
+  api_token: secret
+  watched_film_folders:
+    - /movies
+  watched_tv_folders:
+    - /series
+  observed_period_days: 30
+
+email:
+  smtp_server: smtp.example.com
+  smtp_port: 587
+  smtp_username: user
+  smtp_password: pass
+  smtp_sender_email: Jellyfin
+  smtp_tls_type: "TLS"
+
+email_template:
+  language: en
+  subject: New releases
+  title: Newsletter
+  subtitle: This week
+  jellyfin_url: http://localhost:8096
+  unsubscribe_email: unsub@example.com
+  jellyfin_owner_name: Admin
+
+recipients:
+  - "user@example.com"
+`
+
+func getTestState(cronExprByProfile map[string]string) *schedulerState {
+	state := &schedulerState{}
+	for profileName, cronExpr := range cronExprByProfile {
+		state.newsletters = append(state.newsletters, &app.ApplicationContext{Config: &config.Configuration{
+			ProfileName: profileName,
+			Scheduler:   config.SchedulerConfig{Enabled: true, CronExpr: cronExpr},
+		}})
+	}
+	return state
+}
+
+func getTestScheduler(t *testing.T) *NewsletterScheduler {
+	scheduler, err := gocron.NewScheduler()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scheduler.Shutdown() })
+	return &NewsletterScheduler{scheduler: scheduler, jobs: map[string]gocron.Job{}}
+}
+
+func jobNames(s *NewsletterScheduler) []string {
+	names := []string{}
+	for profileName := range s.jobs {
+		names = append(names, profileName)
+	}
+	return names
+}
+
+func TestSyncJobs_FailedReloadIsRolledBack(t *testing.T) {
+	s := getTestScheduler(t)
+	initialState := getTestState(map[string]string{"weekly": "0 8 * * 1", "monthly": "0 8 1 * *"})
+	require.NoError(t, s.syncJobs(nil, initialState))
+	monthlyJobID := s.jobs["monthly"].ID()
+
+	failingState := getTestState(map[string]string{
+		"weekly": "0 9 * * 1",
+		"daily":  "0 8 * * *",
+		"broken": "not a cron expression",
+	})
+	require.Error(t, s.syncJobs(initialState, failingState))
+
+	assert.ElementsMatch(t, []string{"weekly", "monthly"}, jobNames(s))
+	assert.Len(t, s.scheduler.Jobs(), 2, "the jobs created by the failed reload are removed")
+	assert.Equal(t, monthlyJobID, s.jobs["monthly"].ID())
+
+	// The next reload starts again from the initial state
+	goodState := getTestState(map[string]string{"weekly": "0 8 * * 1", "daily": "0 8 * * *"})
+	require.NoError(t, s.syncJobs(initialState, goodState))
+
+	assert.ElementsMatch(t, []string{"weekly", "daily"}, jobNames(s))
+	assert.Len(t, s.scheduler.Jobs(), 2)
+}
+
+func TestSyncJobs_JobWithoutPreviousNewsletterIsRescheduled(t *testing.T) {
+	s := getTestScheduler(t)
+	initialState := getTestState(map[string]string{"weekly": "0 8 * * 1"})
+	require.NoError(t, s.syncJobs(nil, initialState))
+
+	newState := getTestState(map[string]string{"weekly": "0 9 * * 1"})
+	require.NoError(t, s.syncJobs(&schedulerState{}, newState))
+
+	assert.ElementsMatch(t, []string{"weekly"}, jobNames(s))
+	assert.Len(t, s.scheduler.Jobs(), 1)
+}
+
+func TestBuildReloadedState_KeepsTMDBRateLimiter(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(reloadedConfigYAML), 0600))
+	currentConfig, err := config.LoadConfig(configPath, "")
+	require.NoError(t, err)
+	localizer, err := i18n.NewLocalizer("en")
+	require.NoError(t, err)
+	currentApp := app.InitApplicationContext(currentConfig, zap.NewNop(), localizer, clock.RealClock{})
+	s := getTestScheduler(t)
+
+	reloadedState, err := s.buildReloadedState(currentApp)
+
+	require.NoError(t, err)
+	assert.NotSame(t, currentApp, reloadedState.app)
+	assert.Same(t, currentApp.TMDBRateLimiter, reloadedState.app.TMDBRateLimiter)
+	for _, newsletterApp := range reloadedState.newsletters {
+		assert.Same(t, currentApp.TMDBRateLimiter, newsletterApp.TMDBRateLimiter)
+	}
+}
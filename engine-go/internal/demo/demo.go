@@ -0,0 +1,39 @@
+package demo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/newsletter"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+)
+
+// The demo builds a newsletter from a fictional library, without Jellyfin, TMDB or SMTP server.
+// It runs the real newsletter workflow against fixture implementations of the Jellyfin and TMDB clients,
+// so theme authors and translators get a reproducible preview of every section of the newsletter.
+
+// Clock is the clock of the demo. The newsletter is always built at the same date, so its output is reproducible.
+var Clock = clock.FixedClock{Time: time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)}
+
+// NewWorkflow returns a newsletter workflow connected to the demo library.
+func NewWorkflow() newsletter.Workflow {
+	return newsletter.Workflow{
+		JellyfinClient: newFixtureJellyfinClient(),
+		TMDBClient:     newFixtureTMDBClient(),
+	}
+}
+
+// Run builds the demo newsletter of app, configured by config.LoadDemoConfig.
+// The data of a "previous run" are saved first, so the upgraded and removed media sections are filled too.
+func Run(app *app.ApplicationContext) error {
+	if err := persistentdata.UpdateMoviesQuality(previousMoviesQuality(), app); err != nil {
+		return fmt.Errorf("failed to save the demo movies quality: %w", err)
+	}
+	if err := persistentdata.UpdateLibrarySnapshots(previousLibrarySnapshots(), app); err != nil {
+		return fmt.Errorf("failed to save the demo library snapshot: %w", err)
+	}
+	NewWorkflow().Run(app)
+	return nil
+}
@@ -0,0 +1,55 @@
+package demo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func runDemo(t *testing.T) string {
+	outputDirectory := t.TempDir()
+	conf, err := config.LoadDemoConfig(t.TempDir(), outputDirectory, "")
+	require.NoError(t, err)
+	localizer, err := i18n.NewLocalizer(conf.EmailTemplate.Language)
+	require.NoError(t, err)
+
+	require.NoError(t, Run(app.InitApplicationContext(conf, zap.NewNop(), localizer, Clock)))
+
+	emailHTML, err := os.ReadFile(filepath.Join(outputDirectory, conf.DryRun.OutputFilename))
+	require.NoError(t, err)
+	return string(emailHTML)
+}
+
+func TestRun_FillsEverySection(t *testing.T) {
+	emailHTML := runDemo(t)
+
+	for _, expected := range []string{
+		"The Clockmaker&#39;s Daughter", // New movie
+		"When the last colony ship",     // Overview completed by TMDB
+		"A night porter",                // Overview found by a TMDB search by name
+		"Tidewater",                     // Upgraded movie
+		"The Lantern Keepers",           // Brand new series
+		"Northern Line",                 // New season
+		"Winter comes early",            // Season overview completed by TMDB
+		"Harbour Street",                // Scattered episodes
+		"Closing Time",                  // Episode list of a partial season
+		"Neon Tides",                    // Music album
+		"Salt and Starlight",            // Audiobook
+		"Static Horizon",                // Removed movie
+		"Glasshouse",                    // Removed series
+	} {
+		assert.Contains(t, emailHTML, expected)
+	}
+	assert.NotContains(t, emailHTML, "The Long Winter Road", "movies added before the observed period are not new")
+}
+
+func TestRun_IsReproducible(t *testing.T) {
+	assert.Equal(t, runDemo(t), runDemo(t))
+}
@@ -0,0 +1,136 @@
+package demo
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// fixtureJellyfinAPI serves the demo library (see fixtureFolders) in place of a Jellyfin server.
+// It implements every Jellyfin API interface used by jellyfin.APIClient.
+type fixtureJellyfinAPI struct {
+	folders []fixtureFolder
+}
+
+// newFixtureJellyfinClient returns a Jellyfin client connected to the demo library.
+func newFixtureJellyfinClient() jellyfin.APIClient {
+	api := fixtureJellyfinAPI{folders: fixtureFolders()}
+	return jellyfin.APIClient{
+		SystemAPI:           api,
+		ItemsAPI:            api,
+		LibraryAPI:          api,
+		LibraryStructureAPI: api,
+		UserAPI:             api,
+	}
+}
+
+func (api fixtureJellyfinAPI) PingSystem() (string, *http.Response, error) {
+	return "Jellyfin Server", nil, nil
+}
+
+func (api fixtureJellyfinAPI) GetSystemInformation() (*jellyfin.SystemInfo, int, error) {
+	return &jellyfin.SystemInfo{APIVersion: "10.10.7", ServerName: "Demo"}, http.StatusOK, nil
+}
+
+func (api fixtureJellyfinAPI) findFolder(folderID string) (fixtureFolder, bool) {
+	for _, folder := range api.folders {
+		if folder.library.ID == folderID {
+			return folder, true
+		}
+	}
+	return fixtureFolder{}, false
+}
+
+func (api fixtureJellyfinAPI) GetMoviesItemsByFolderID(
+	folderID string,
+	_ bool,
+	_ *app.ApplicationContext,
+) (*[]jellyfinAPI.BaseItemDto, error) {
+	folder, ok := api.findFolder(folderID)
+	if !ok {
+		return nil, jellyfin.ErrItemsNotFound
+	}
+	movies := []jellyfinAPI.BaseItemDto{}
+	for _, item := range folder.items {
+		if *item.Type == jellyfinAPI.BASEITEMKIND_MOVIE {
+			movies = append(movies, item)
+		}
+	}
+	return &movies, nil
+}
+
+func (api fixtureJellyfinAPI) GetRootFolderIDByName(folderName string, _ *app.ApplicationContext) (string, error) {
+	for _, folder := range api.folders {
+		if folder.library.Name == folderName {
+			return folder.library.ID, nil
+		}
+	}
+	return "", jellyfin.ErrItemsNotFound
+}
+
+func (api fixtureJellyfinAPI) GetItemsAddedAfterByFolderID(
+	folderID string,
+	minimumAdditionDate time.Time,
+	itemTypes []jellyfinAPI.BaseItemKind,
+	_ []jellyfinAPI.ItemFields,
+	_ *app.ApplicationContext,
+) (*[]jellyfinAPI.BaseItemDto, error) {
+	folder, ok := api.findFolder(folderID)
+	if !ok {
+		return nil, jellyfin.ErrItemsNotFound
+	}
+	items := []jellyfinAPI.BaseItemDto{}
+	for _, item := range folder.items {
+		if slices.Contains(itemTypes, *item.Type) && item.DateCreated.Get().After(minimumAdditionDate) {
+			items = append(items, item)
+		}
+	}
+	return &items, nil
+}
+
+func (api fixtureJellyfinAPI) GetItemsByIDs(
+	itemIDs []string,
+	_ *app.ApplicationContext,
+) (*[]jellyfinAPI.BaseItemDto, error) {
+	items := []jellyfinAPI.BaseItemDto{}
+	for _, folder := range api.folders {
+		for _, item := range folder.items {
+			if slices.Contains(itemIDs, *item.Id) {
+				items = append(items, item)
+			}
+		}
+	}
+	return &items, nil
+}
+
+func (api fixtureJellyfinAPI) GetItemsStats(_ *app.ApplicationContext) (int32, int32, error) {
+	var moviesCount, episodesCount int32
+	for _, folder := range api.folders {
+		for _, item := range folder.items {
+			switch *item.Type {
+			case jellyfinAPI.BASEITEMKIND_MOVIE:
+				moviesCount++
+			case jellyfinAPI.BASEITEMKIND_EPISODE:
+				episodesCount++
+			default:
+			}
+		}
+	}
+	return moviesCount, episodesCount, nil
+}
+
+func (api fixtureJellyfinAPI) GetLibraries(_ *app.ApplicationContext) ([]jellyfin.Library, error) {
+	libraries := make([]jellyfin.Library, 0, len(api.folders))
+	for _, folder := range api.folders {
+		libraries = append(libraries, folder.library)
+	}
+	return libraries, nil
+}
+
+func (api fixtureJellyfinAPI) GetUsers(_ *app.ApplicationContext) ([]jellyfin.User, error) {
+	return []jellyfin.User{{ID: "4f1c2b3a5d6e7f80912a3b4c5d6e7f80", Name: "demo"}}, nil
+}
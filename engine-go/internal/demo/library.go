@@ -0,0 +1,438 @@
+package demo
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// The demo library is fictional. Its items are dated relatively to the demo clock, and cover every section
+// of the newsletter: new and upgraded movies, a brand new series, a new season, episodes scattered across
+// seasons, music albums, books and removed media.
+
+// fixtureFolder is a Jellyfin library of the demo server, with its items.
+type fixtureFolder struct {
+	library jellyfin.Library
+	items   []jellyfinAPI.BaseItemDto
+}
+
+// fixtureMovie describes a movie of the demo library, see movieItem.
+type fixtureMovie struct {
+	id              string
+	name            string
+	year            int32
+	tmdbID          string
+	addedDaysAgo    int
+	overview        string
+	genres          []string
+	runtimeMinutes  int64
+	communityRating float32
+	officialRating  string
+	width           int32
+	height          int32
+	codec           string
+	videoRange      jellyfinAPI.VideoRangeType
+	audioLanguages  []string
+	subtitles       []string
+}
+
+// addedDaysAgo returns the addition date of an item added days before the demo date.
+func addedDaysAgo(days int) time.Time {
+	return Clock.Now().AddDate(0, 0, -days)
+}
+
+func movieItem(movie fixtureMovie) jellyfinAPI.BaseItemDto {
+	mediaStreams := []jellyfinAPI.MediaStream{{
+		Type:           new(jellyfinAPI.MEDIASTREAMTYPE_VIDEO),
+		Width:          *jellyfinAPI.NewNullableInt32(new(movie.width)),
+		Height:         *jellyfinAPI.NewNullableInt32(new(movie.height)),
+		Codec:          *jellyfinAPI.NewNullableString(new(movie.codec)),
+		VideoRangeType: new(movie.videoRange),
+	}}
+	for _, language := range movie.audioLanguages {
+		mediaStreams = append(mediaStreams, jellyfinAPI.MediaStream{
+			Type:     new(jellyfinAPI.MEDIASTREAMTYPE_AUDIO),
+			Language: *jellyfinAPI.NewNullableString(new(language)),
+		})
+	}
+	for _, language := range movie.subtitles {
+		mediaStreams = append(mediaStreams, jellyfinAPI.MediaStream{
+			Type:     new(jellyfinAPI.MEDIASTREAMTYPE_SUBTITLE),
+			Language: *jellyfinAPI.NewNullableString(new(language)),
+		})
+	}
+
+	item := jellyfinAPI.BaseItemDto{
+		Id:             new(movie.id),
+		Name:           *jellyfinAPI.NewNullableString(new(movie.name)),
+		Type:           new(jellyfinAPI.BASEITEMKIND_MOVIE),
+		ProductionYear: *jellyfinAPI.NewNullableInt32(new(movie.year)),
+		DateCreated:    *jellyfinAPI.NewNullableTime(new(addedDaysAgo(movie.addedDaysAgo))),
+		Genres:         movie.genres,
+		MediaStreams:   mediaStreams,
+		Path:           *jellyfinAPI.NewNullableString(new("/media/movies/" + movie.name)),
+	}
+	if movie.tmdbID != "" {
+		item.ProviderIds = map[string]string{"Tmdb": movie.tmdbID}
+	}
+	if movie.overview != "" {
+		item.Overview = *jellyfinAPI.NewNullableString(new(movie.overview))
+	}
+	if movie.runtimeMinutes > 0 {
+		// Jellyfin ticks are 100 nanoseconds long
+		runtimeTicks := int64(time.Duration(movie.runtimeMinutes) * time.Minute / 100)
+		item.RunTimeTicks = *jellyfinAPI.NewNullableInt64(&runtimeTicks)
+	}
+	if movie.communityRating > 0 {
+		item.CommunityRating = *jellyfinAPI.NewNullableFloat32(new(movie.communityRating))
+	}
+	if movie.officialRating != "" {
+		item.OfficialRating = *jellyfinAPI.NewNullableString(new(movie.officialRating))
+	}
+	return item
+}
+
+func seriesItem(id string, name string, year int32, tmdbID string, addedDays int) jellyfinAPI.BaseItemDto {
+	return jellyfinAPI.BaseItemDto{
+		Id:             new(id),
+		Name:           *jellyfinAPI.NewNullableString(new(name)),
+		Type:           new(jellyfinAPI.BASEITEMKIND_SERIES),
+		ProductionYear: *jellyfinAPI.NewNullableInt32(new(year)),
+		DateCreated:    *jellyfinAPI.NewNullableTime(new(addedDaysAgo(addedDays))),
+		ProviderIds:    map[string]string{"Tmdb": tmdbID},
+		Path:           *jellyfinAPI.NewNullableString(new("/media/shows/" + name)),
+		LocationType:   *jellyfinAPI.NewNullableLocationType(new(jellyfinAPI.LOCATIONTYPE_FILE_SYSTEM)),
+	}
+}
+
+func seasonItem(id string, seriesID string, number int32, addedDays int) jellyfinAPI.BaseItemDto {
+	return jellyfinAPI.BaseItemDto{
+		Id:           new(id),
+		Name:         *jellyfinAPI.NewNullableString(new("Season " + strconv.Itoa(int(number)))),
+		Type:         new(jellyfinAPI.BASEITEMKIND_SEASON),
+		DateCreated:  *jellyfinAPI.NewNullableTime(new(addedDaysAgo(addedDays))),
+		SeriesId:     *jellyfinAPI.NewNullableString(new(seriesID)),
+		IndexNumber:  *jellyfinAPI.NewNullableInt32(new(number)),
+		LocationType: *jellyfinAPI.NewNullableLocationType(new(jellyfinAPI.LOCATIONTYPE_FILE_SYSTEM)),
+	}
+}
+
+func episodeItem(
+	id string,
+	seriesID string,
+	seasonID string,
+	number int32,
+	name string,
+	addedDays int,
+) jellyfinAPI.BaseItemDto {
+	return jellyfinAPI.BaseItemDto{
+		Id:           new(id),
+		Name:         *jellyfinAPI.NewNullableString(new(name)),
+		Type:         new(jellyfinAPI.BASEITEMKIND_EPISODE),
+		DateCreated:  *jellyfinAPI.NewNullableTime(new(addedDaysAgo(addedDays))),
+		SeriesId:     *jellyfinAPI.NewNullableString(new(seriesID)),
+		SeasonId:     *jellyfinAPI.NewNullableString(new(seasonID)),
+		IndexNumber:  *jellyfinAPI.NewNullableInt32(new(number)),
+		LocationType: *jellyfinAPI.NewNullableLocationType(new(jellyfinAPI.LOCATIONTYPE_FILE_SYSTEM)),
+	}
+}
+
+func musicAlbumItem(id string, name string, artist string, year int32, addedDays int) jellyfinAPI.BaseItemDto {
+	return jellyfinAPI.BaseItemDto{
+		Id:             new(id),
+		Name:           *jellyfinAPI.NewNullableString(new(name)),
+		Type:           new(jellyfinAPI.BASEITEMKIND_MUSIC_ALBUM),
+		AlbumArtist:    *jellyfinAPI.NewNullableString(new(artist)),
+		ProductionYear: *jellyfinAPI.NewNullableInt32(new(year)),
+		DateCreated:    *jellyfinAPI.NewNullableTime(new(addedDaysAgo(addedDays))),
+	}
+}
+
+func bookItem(
+	id string,
+	itemType jellyfinAPI.BaseItemKind,
+	name string,
+	author string,
+	year int32,
+	overview string,
+	addedDays int,
+) jellyfinAPI.BaseItemDto {
+	return jellyfinAPI.BaseItemDto{
+		Id:             new(id),
+		Name:           *jellyfinAPI.NewNullableString(new(name)),
+		Type:           new(itemType),
+		AlbumArtist:    *jellyfinAPI.NewNullableString(new(author)),
+		ProductionYear: *jellyfinAPI.NewNullableInt32(new(year)),
+		Overview:       *jellyfinAPI.NewNullableString(new(overview)),
+		DateCreated:    *jellyfinAPI.NewNullableTime(new(addedDaysAgo(addedDays))),
+	}
+}
+
+const upgradedMovieID = "5b0e7c1d9a2f4e6b8c3d1a0f9e8d7c6b"
+
+func moviesFolder() fixtureFolder {
+	movies := []fixtureMovie{
+		{
+			id:           "0c6f2a9e51d84b7f9a3e2d1c0b9a8f7e",
+			name:         "The Clockmaker's Daughter",
+			year:         2024,
+			tmdbID:       "900101",
+			addedDaysAgo: 2,
+			overview: "In a town where every clock stopped at the same minute, a young apprentice follows the ticking " +
+				"of the last working watch to find her missing father.",
+			genres:          []string{"Drama", "Mystery"},
+			runtimeMinutes:  118,
+			communityRating: 7.6,
+			officialRating:  "PG-13",
+			width:           1920,
+			height:          1080,
+			codec:           "h264",
+			videoRange:      jellyfinAPI.VIDEORANGETYPE_SDR,
+			audioLanguages:  []string{"eng", "fre"},
+			subtitles:       []string{"eng", "fre"},
+		},
+		{
+			// Jellyfin has no metadata for this one, TMDB completes them
+			id:             "1d7a3b0f62e94c8a0b4f3e2d1c0a9b8f",
+			name:           "Orbit of Ashes",
+			year:           2025,
+			tmdbID:         "900102",
+			addedDaysAgo:   5,
+			officialRating: "PG-13",
+			width:          3840,
+			height:         1600,
+			codec:          "hevc",
+			videoRange:     jellyfinAPI.VIDEORANGETYPE_HDR10,
+			audioLanguages: []string{"eng"},
+			subtitles:      []string{"eng", "spa"},
+		},
+		{
+			id:           "2e8b4c1a73fa4d9b1c5a4f3e2d1b0c9a",
+			name:         "Paper Lanterns",
+			year:         2023,
+			tmdbID:       "900103",
+			addedDaysAgo: 9,
+			overview: "Two siblings fold a thousand paper lanterns to guide their grandmother's spirit home, " +
+				"and wake up the old river spirit instead.",
+			genres:          []string{"Animation", "Family", "Fantasy"},
+			runtimeMinutes:  97,
+			communityRating: 8.2,
+			officialRating:  "PG",
+			width:           1920,
+			height:          1038,
+			codec:           "h264",
+			videoRange:      jellyfinAPI.VIDEORANGETYPE_SDR,
+			audioLanguages:  []string{"jpn", "eng"},
+			subtitles:       []string{"eng", "fre", "ger"},
+		},
+		{
+			// Replaced by a better version, see previousMoviesQuality
+			id:           upgradedMovieID,
+			name:         "Tidewater",
+			year:         2022,
+			tmdbID:       "900104",
+			addedDaysAgo: 3,
+			overview: "A marine biologist returns to the island she left twenty years ago, as a storm older than " +
+				"the island itself gathers offshore.",
+			genres:          []string{"Thriller", "Drama"},
+			runtimeMinutes:  126,
+			communityRating: 7.1,
+			officialRating:  "R",
+			width:           3840,
+			height:          2160,
+			codec:           "hevc",
+			videoRange:      jellyfinAPI.VIDEORANGETYPE_DOVI,
+			audioLanguages:  []string{"eng", "ita"},
+			subtitles:       []string{"eng"},
+		},
+		{
+			// Without TMDB id, TMDB is searched by name
+			id:             "3f9c5d2b84ab4eac2d6b5a4f3e2c1d0b",
+			name:           "Midnight at the Grand Atlas",
+			year:           2021,
+			addedDaysAgo:   12,
+			genres:         []string{"Comedy", "Crime"},
+			runtimeMinutes: 104,
+			officialRating: "PG-13",
+			width:          1280,
+			height:         720,
+			codec:          "h264",
+			videoRange:     jellyfinAPI.VIDEORANGETYPE_SDR,
+			audioLanguages: []string{"eng"},
+		},
+		{
+			// Added before the observed period, it is not announced
+			id:              "4a0d6e3c95bc4fbd3e7c6b5a4f3d2e1c",
+			name:            "The Long Winter Road",
+			year:            2019,
+			tmdbID:          "900105",
+			addedDaysAgo:    120,
+			overview:        "A retired postman walks the frozen road between two villages one last time.",
+			genres:          []string{"Drama"},
+			runtimeMinutes:  101,
+			communityRating: 6.9,
+			width:           1920,
+			height:          1080,
+			codec:           "h264",
+			videoRange:      jellyfinAPI.VIDEORANGETYPE_SDR,
+			audioLanguages:  []string{"eng"},
+		},
+	}
+
+	items := make([]jellyfinAPI.BaseItemDto, 0, len(movies))
+	for _, movie := range movies {
+		items = append(items, movieItem(movie))
+	}
+	return fixtureFolder{
+		library: jellyfin.Library{
+			ID:             "f137a2dd21bbc1b99aa5c0f6bf02a805",
+			Name:           "Movies",
+			CollectionType: string(jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES),
+			Locations:      []string{"/media/movies"},
+		},
+		items: items,
+	}
+}
+
+func showsFolder() fixtureFolder {
+	const (
+		newSeriesID       = "6c2f8a5eb7de4a1f5a9e8d7c6b5f4a3e"
+		newSeriesSeason1  = "6c2f8a5eb7de4a1f5a9e8d7c6b5f4a31"
+		newSeasonSeriesID = "7d3a9b6fc8ef4b2a6b0f9e8d7c6a5b4f"
+		newSeasonSeason1  = "7d3a9b6fc8ef4b2a6b0f9e8d7c6a5b41"
+		newSeasonSeason2  = "7d3a9b6fc8ef4b2a6b0f9e8d7c6a5b42"
+		scatteredSeriesID = "8e4b0c7ad9fa4c3b7c1a0f9e8d7b6c5a"
+		scatteredSeason2  = "8e4b0c7ad9fa4c3b7c1a0f9e8d7b6c52"
+		scatteredSeason3  = "8e4b0c7ad9fa4c3b7c1a0f9e8d7b6c53"
+	)
+	return fixtureFolder{
+		library: jellyfin.Library{
+			ID:             "a656b907eb3a73532e40e44b968d0225",
+			Name:           "Shows",
+			CollectionType: string(jellyfinAPI.COLLECTIONTYPEOPTIONS_TVSHOWS),
+			Locations:      []string{"/media/shows"},
+		},
+		items: []jellyfinAPI.BaseItemDto{
+			// A brand new series
+			seriesItem(newSeriesID, "The Lantern Keepers", 2026, "910201", 4),
+			seasonItem(newSeriesSeason1, newSeriesID, 1, 4),
+			episodeItem("6c2f8a5eb7de4a1f5a9e8d7c6b5f4e01", newSeriesID, newSeriesSeason1, 1, "First Light", 4),
+			episodeItem("6c2f8a5eb7de4a1f5a9e8d7c6b5f4e02", newSeriesID, newSeriesSeason1, 2, "The Oil Ledger", 4),
+			episodeItem("6c2f8a5eb7de4a1f5a9e8d7c6b5f4e03", newSeriesID, newSeriesSeason1, 3, "Fog Signal", 4),
+
+			// An old series with a new season
+			seriesItem(newSeasonSeriesID, "Northern Line", 2023, "910202", 400),
+			seasonItem(newSeasonSeason1, newSeasonSeriesID, 1, 400),
+			episodeItem("7d3a9b6fc8ef4b2a6b0f9e8d7c6a5e11", newSeasonSeriesID, newSeasonSeason1, 1, "Last Train", 400),
+			episodeItem("7d3a9b6fc8ef4b2a6b0f9e8d7c6a5e12", newSeasonSeriesID, newSeasonSeason1, 2, "Signal Failure", 400),
+			seasonItem(newSeasonSeason2, newSeasonSeriesID, 2, 6),
+			episodeItem("7d3a9b6fc8ef4b2a6b0f9e8d7c6a5e21", newSeasonSeriesID, newSeasonSeason2, 1, "Depot", 6),
+			episodeItem("7d3a9b6fc8ef4b2a6b0f9e8d7c6a5e22", newSeasonSeriesID, newSeasonSeason2, 2, "Night Shift", 6),
+			episodeItem("7d3a9b6fc8ef4b2a6b0f9e8d7c6a5e23", newSeasonSeriesID, newSeasonSeason2, 3, "Terminus", 6),
+
+			// An old series with episodes added here and there
+			seriesItem(scatteredSeriesID, "Harbour Street", 2021, "910203", 700),
+			seasonItem(scatteredSeason2, scatteredSeriesID, 2, 700),
+			episodeItem("8e4b0c7ad9fa4c3b7c1a0f9e8d7b6e21", scatteredSeriesID, scatteredSeason2, 1, "Low Tide", 700),
+			episodeItem("8e4b0c7ad9fa4c3b7c1a0f9e8d7b6e27", scatteredSeriesID, scatteredSeason2, 7, "The Auction", 10),
+			seasonItem(scatteredSeason3, scatteredSeriesID, 3, 45),
+			episodeItem("8e4b0c7ad9fa4c3b7c1a0f9e8d7b6e31", scatteredSeriesID, scatteredSeason3, 1, "New Owners", 45),
+			episodeItem("8e4b0c7ad9fa4c3b7c1a0f9e8d7b6e32", scatteredSeriesID, scatteredSeason3, 2, "Wet Paint", 45),
+			episodeItem("8e4b0c7ad9fa4c3b7c1a0f9e8d7b6e33", scatteredSeriesID, scatteredSeason3, 3, "The Lease", 8),
+			episodeItem("8e4b0c7ad9fa4c3b7c1a0f9e8d7b6e35", scatteredSeriesID, scatteredSeason3, 5, "Closing Time", 1),
+		},
+	}
+}
+
+func musicFolder() fixtureFolder {
+	return fixtureFolder{
+		library: jellyfin.Library{
+			ID:             "7e64e319657a9516ec78490da03edccb",
+			Name:           "Music",
+			CollectionType: string(jellyfinAPI.COLLECTIONTYPEOPTIONS_MUSIC),
+			Locations:      []string{"/media/music"},
+		},
+		items: []jellyfinAPI.BaseItemDto{
+			musicAlbumItem("9f5c1d8bea0b4d4c8d2b1a0f9e8c7d6b", "Neon Tides", "The Quiet Harbours", 2025, 7),
+			musicAlbumItem("9f5c1d8bea0b4d4c8d2b1a0f9e8c7d7c", "Songs for Empty Stations", "Ada Linde", 2024, 14),
+		},
+	}
+}
+
+func booksFolder() fixtureFolder {
+	return fixtureFolder{
+		library: jellyfin.Library{
+			ID:             "3d1f5a7c9e0b2d4f6a8c0e2b4d6f8a0c",
+			Name:           "Books",
+			CollectionType: string(jellyfinAPI.COLLECTIONTYPEOPTIONS_BOOKS),
+			Locations:      []string{"/media/books"},
+		},
+		items: []jellyfinAPI.BaseItemDto{
+			bookItem(
+				"b06d2e9cfb1c4e5d9e3c2b1a0f9d8e7c",
+				jellyfinAPI.BASEITEMKIND_BOOK,
+				"The Cartographer's Atlas",
+				"Mira Solberg",
+				2024,
+				"A mapmaker discovers that the blank corners of her maps fill themselves in overnight.",
+				11,
+			),
+			bookItem(
+				"c17e3f0da02d4f6e0f4d3c2b1a0e9f8d",
+				jellyfinAPI.BASEITEMKIND_AUDIO_BOOK,
+				"Salt and Starlight",
+				"Elias Brandt",
+				2023,
+				"Letters from a lighthouse, read by the author.",
+				3,
+			),
+		},
+	}
+}
+
+// fixtureFolders returns the libraries of the demo Jellyfin server.
+func fixtureFolders() []fixtureFolder {
+	return []fixtureFolder{moviesFolder(), showsFolder(), musicFolder(), booksFolder()}
+}
+
+// previousMoviesQuality is the movies quality saved by the "previous run": the upgraded movie was in 1080p.
+func previousMoviesQuality() map[string]map[string]persistentdata.MovieQualityRecord {
+	return map[string]map[string]persistentdata.MovieQualityRecord{
+		"": {
+			upgradedMovieID: {
+				Name:       "Tidewater",
+				TMDBId:     "900104",
+				Resolution: "1080p",
+				VideoRange: string(jellyfinAPI.VIDEORANGETYPE_SDR),
+				Codec:      "H264",
+			},
+		},
+	}
+}
+
+// previousLibrarySnapshots is the library snapshot saved by the "previous run", with a movie and a series
+// that are no longer in the demo library.
+func previousLibrarySnapshots() map[string]persistentdata.LibrarySnapshot {
+	return map[string]persistentdata.LibrarySnapshot{
+		"": {
+			Folders: []string{"Movies", "Shows"},
+			Items: map[string]persistentdata.LibrarySnapshotItem{
+				"d28f4a1eb13e4a7f0a5e4d3c2b1f0a9e": {
+					Name:           "Static Horizon",
+					ItemType:       string(jellyfinAPI.BASEITEMKIND_MOVIE),
+					ProductionYear: 2018,
+					Folder:         "Movies",
+				},
+				"e39a5b2fc24f4b8a1b6f5e4d3c2a1b0f": {
+					Name:           "Glasshouse",
+					ItemType:       string(jellyfinAPI.BASEITEMKIND_SERIES),
+					ProductionYear: 2020,
+					Folder:         "Shows",
+				},
+			},
+		},
+	}
+}
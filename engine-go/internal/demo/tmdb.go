@@ -0,0 +1,130 @@
+package demo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/tmdb"
+)
+
+var errTMDBMediaNotFound = errors.New("media not found in the demo TMDB data")
+
+// fixtureTMDBAPI serves the TMDB details of the demo library in place of the TMDB API.
+// Posters are left empty, so the newsletter shows the default poster and the demo works offline.
+type fixtureTMDBAPI struct {
+	mediaByID   map[string]tmdb.GetMediaHTTPResponse  // By media type and TMDB id, see tmdbKey
+	mediaByName map[string]tmdb.GetMediaHTTPResponse  // By media type and lower case name, see tmdbKey
+	seasons     map[string]tmdb.GetSeasonHTTPResponse // By series TMDB id and season number, see seasonKey
+}
+
+func tmdbKey(mediaType tmdb.MediaType, idOrName string) string {
+	return mediaType.ToString() + "/" + strings.ToLower(idOrName)
+}
+
+func seasonKey(seriesID string, seasonNumber int) string {
+	return seriesID + "/" + strconv.Itoa(seasonNumber)
+}
+
+func newFixtureTMDBClient() tmdb.APIInterface {
+	return fixtureTMDBAPI{
+		mediaByID: map[string]tmdb.GetMediaHTTPResponse{
+			tmdbKey(tmdb.MediaTypeMovie, "900101"): {
+				Overview:    "A young apprentice follows the ticking of the last working watch to find her missing father.",
+				VoteAverage: 7.6,
+				Genres:      []tmdb.GenreHTTPResponse{{Name: "Drama"}, {Name: "Mystery"}},
+				Runtime:     118,
+			},
+			tmdbKey(tmdb.MediaTypeMovie, "900102"): {
+				Overview: "When the last colony ship loses contact with Earth, its engineer must choose between " +
+					"the crew and the cargo that could save humanity.",
+				VoteAverage: 7.9,
+				Genres:      []tmdb.GenreHTTPResponse{{Name: "Science Fiction"}, {Name: "Drama"}},
+				Runtime:     142,
+			},
+			tmdbKey(tmdb.MediaTypeMovie, "900103"): {
+				Overview:    "Two siblings fold a thousand paper lanterns and wake up the old river spirit.",
+				VoteAverage: 8.2,
+				Genres:      []tmdb.GenreHTTPResponse{{Name: "Animation"}, {Name: "Family"}},
+				Runtime:     97,
+			},
+			tmdbKey(tmdb.MediaTypeMovie, "900104"): {
+				Overview:    "A marine biologist returns to the island she left twenty years ago.",
+				VoteAverage: 7.1,
+				Genres:      []tmdb.GenreHTTPResponse{{Name: "Thriller"}},
+				Runtime:     126,
+			},
+			tmdbKey(tmdb.MediaTypeSeries, "910201"): {
+				Overview: "The keepers of a remote lighthouse start receiving signals from ships that sank " +
+					"a century ago.",
+				VoteAverage: 8.0,
+			},
+			tmdbKey(tmdb.MediaTypeSeries, "910202"): {
+				Overview:    "The night staff of a northern railway line keep the trains, and each other, running.",
+				VoteAverage: 7.4,
+			},
+			tmdbKey(tmdb.MediaTypeSeries, "910203"): {
+				Overview:    "The neighbours of a small harbour street share their shops, their secrets and their storms.",
+				VoteAverage: 7.8,
+			},
+		},
+		mediaByName: map[string]tmdb.GetMediaHTTPResponse{
+			tmdbKey(tmdb.MediaTypeMovie, "Midnight at the Grand Atlas"): {
+				Title:       "Midnight at the Grand Atlas",
+				ReleaseDate: "2021-11-05",
+				Overview: "A night porter and a jewel thief are locked in the most famous hotel of the city " +
+					"until sunrise.",
+				Popularity:  42.5,
+				VoteAverage: 6.8,
+			},
+		},
+		seasons: map[string]tmdb.GetSeasonHTTPResponse{
+			seasonKey("910202", 2): {
+				SeasonNumber: 2,
+				Name:         "Season 2",
+				Overview:     "Winter comes early and the line is threatened with closure.",
+				Episodes: []tmdb.EpisodeHTTPResponse{
+					{EpisodeNumber: 1, Name: "Depot", AirDate: "2025-01-10"},
+					{EpisodeNumber: 2, Name: "Night Shift", AirDate: "2025-01-17"},
+					{EpisodeNumber: 3, Name: "Terminus", AirDate: "2025-01-24"},
+				},
+			},
+		},
+	}
+}
+
+func (api fixtureTMDBAPI) GetMediaByID(id string, mediaType tmdb.MediaType) (*tmdb.GetMediaHTTPResponse, error) {
+	media, ok := api.mediaByID[tmdbKey(mediaType, id)]
+	if !ok {
+		return nil, errTMDBMediaNotFound
+	}
+	return &media, nil
+}
+
+func (api fixtureTMDBAPI) SearchMediaByName(
+	name string,
+	_ int,
+	mediaType tmdb.MediaType,
+) (*tmdb.SearchMediaHTTPResponse, error) {
+	results := []tmdb.GetMediaHTTPResponse{}
+	if media, ok := api.mediaByName[tmdbKey(mediaType, name)]; ok {
+		results = append(results, media)
+	}
+	return &tmdb.SearchMediaHTTPResponse{Results: results}, nil
+}
+
+// FindMediaByExternalID matches no media: the demo items without TMDB id have no IMDb or TVDB id either.
+func (api fixtureTMDBAPI) FindMediaByExternalID(
+	_ string,
+	_ tmdb.ExternalSource,
+) (*tmdb.FindMediaHTTPResponse, error) {
+	return &tmdb.FindMediaHTTPResponse{}, nil
+}
+
+func (api fixtureTMDBAPI) GetSeasonByNumber(seriesID string, seasonNumber int) (*tmdb.GetSeasonHTTPResponse, error) {
+	season, ok := api.seasons[seasonKey(seriesID, seasonNumber)]
+	if !ok {
+		return nil, errTMDBMediaNotFound
+	}
+	return &season, nil
+}
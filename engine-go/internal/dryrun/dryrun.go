@@ -22,13 +22,30 @@ type metadataJSON struct {
 	SMTPTestResult    string
 	NewDetectedMovies []jellyfin.MovieItem
 	NewDetectedSeries []jellyfin.NewlyAddedSeriesItem
+	NewDetectedMusic  []jellyfin.MusicAlbumItem
+	NewDetectedBooks  []jellyfin.BookItem
+	UpgradedMovies    []jellyfin.MovieItem
+	RemovedMedia      []jellyfin.RemovedItem
 }
 
+// fillFilenameTemplate fills the {{.Datetime}}, {{.Profile}} and {{.User}} placeholders of the output filename.
+// When a profile (or a Jellyfin user newsletter) is used and the filename doesn't contain {{.Profile}}
+// (or {{.User}}), the profile (or user) name is added as prefix, so the newsletters don't override each other's output.
 func fillFilenameTemplate(filename string, app *app.ApplicationContext) string {
 	templateData := struct {
 		Datetime string
+		Profile  string
+		User     string
 	}{
 		Datetime: app.Clock.Now().Format("2006-01-02T15:04:05Z07:00"),
+		Profile:  app.Config.ProfileName,
+		User:     app.Config.Jellyfin.User,
+	}
+	if templateData.User != "" && !strings.Contains(filename, ".User") {
+		filename = "{{.User}}_" + filename
+	}
+	if templateData.Profile != "" && !strings.Contains(filename, ".Profile") {
+		filename = "{{.Profile}}_" + filename
 	}
 	tmpl, err := template.New("filename").Option("missingkey=zero").Parse(filename)
 	if err != nil {
@@ -67,26 +84,38 @@ func marshalNewItems(items any) string {
 }
 
 func addMetadataToHTML(emailHTML string, newJellyfinMovies *[]jellyfin.MovieItem,
-	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem, smtpTestResult string, clock clock.Interface) string {
+	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem, newJellyfinMusicAlbums *[]jellyfin.MusicAlbumItem,
+	newJellyfinBooks *[]jellyfin.BookItem, upgradedJellyfinMovies *[]jellyfin.MovieItem,
+	removedJellyfinItems *[]jellyfin.RemovedItem, smtpTestResult string, clock clock.Interface) string {
 	if smtpTestResult == "" {
 		smtpTestResult = "Not tested"
 	}
 
 	metadata := fmt.Sprintf(
-		"<!--\nJellyfin-newsletter dry run\nGenerated at: %s\nSMTP test result:%s\nNew movies detected: %s\nNew series detected: %s\n-->\n\n",
+		"<!--\nJellyfin-newsletter dry run\nGenerated at: %s\nSMTP test result:%s\nNew movies detected: %s\nNew series detected: %s\nNew music detected: %s\nNew books detected: %s\nUpgraded movies detected: %s\nRemoved media detected: %s\n-->\n\n",
 		clock.Now().Format("2006-01-02T15:04:05Z07:00"),
 		smtpTestResult,
 		marshalNewItems(newJellyfinMovies),
 		marshalNewItems(newJellyfinSeries),
+		marshalNewItems(newJellyfinMusicAlbums),
+		marshalNewItems(newJellyfinBooks),
+		marshalNewItems(upgradedJellyfinMovies),
+		marshalNewItems(removedJellyfinItems),
 	)
 	return metadata + emailHTML
 }
 
 func saveMetadataAsJSONFile(outputDirectory, outputFilename string, newJellyfinMovies *[]jellyfin.MovieItem,
-	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem, smtpTestResult string, clock clock.Interface) error {
+	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem, newJellyfinMusicAlbums *[]jellyfin.MusicAlbumItem,
+	newJellyfinBooks *[]jellyfin.BookItem, upgradedJellyfinMovies *[]jellyfin.MovieItem,
+	removedJellyfinItems *[]jellyfin.RemovedItem, smtpTestResult string, clock clock.Interface) error {
 	metadata := metadataJSON{
 		NewDetectedMovies: *newJellyfinMovies,
 		NewDetectedSeries: *newJellyfinSeries,
+		NewDetectedMusic:  *newJellyfinMusicAlbums,
+		NewDetectedBooks:  *newJellyfinBooks,
+		UpgradedMovies:    *upgradedJellyfinMovies,
+		RemovedMedia:      *removedJellyfinItems,
 		Datetime:          clock.Now().Format("2006-01-02T15:04:05Z07:00"),
 		SMTPTestResult:    smtpTestResult,
 	}
@@ -107,7 +136,9 @@ func saveHTMLFile(outputDirectory, outputFilename, emailHTML string) error {
 }
 
 func SaveDryRunEmail(emailHTML string, newJellyfinMovies *[]jellyfin.MovieItem,
-	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem, app *app.ApplicationContext) {
+	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem, newJellyfinMusicAlbums *[]jellyfin.MusicAlbumItem,
+	newJellyfinBooks *[]jellyfin.BookItem, upgradedJellyfinMovies *[]jellyfin.MovieItem,
+	removedJellyfinItems *[]jellyfin.RemovedItem, app *app.ApplicationContext) {
 	outputFilename := fillFilenameTemplate(app.Config.DryRun.OutputFilename, app)
 	smtpTestResult := "SMTP connection not tested."
 	if app.Config.DryRun.TestSMTPConnection {
@@ -123,7 +154,17 @@ func SaveDryRunEmail(emailHTML string, newJellyfinMovies *[]jellyfin.MovieItem,
 	}
 
 	if app.Config.DryRun.IncludeMetadata {
-		emailHTML = addMetadataToHTML(emailHTML, newJellyfinMovies, newJellyfinSeries, smtpTestResult, app.Clock)
+		emailHTML = addMetadataToHTML(
+			emailHTML,
+			newJellyfinMovies,
+			newJellyfinSeries,
+			newJellyfinMusicAlbums,
+			newJellyfinBooks,
+			upgradedJellyfinMovies,
+			removedJellyfinItems,
+			smtpTestResult,
+			app.Clock,
+		)
 	}
 
 	if app.Config.DryRun.SaveEmailData {
@@ -133,6 +174,10 @@ func SaveDryRunEmail(emailHTML string, newJellyfinMovies *[]jellyfin.MovieItem,
 			filename,
 			newJellyfinMovies,
 			newJellyfinSeries,
+			newJellyfinMusicAlbums,
+			newJellyfinBooks,
+			upgradedJellyfinMovies,
+			removedJellyfinItems,
 			smtpTestResult,
 			app.Clock,
 		)
@@ -0,0 +1,198 @@
+package jellyfin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"go.uber.org/zap"
+)
+
+// Library rescans and server migrations reset the creation date of the items, so the date detection announces
+// the whole library again. The snapshot detection (see config.NewItemDetectionSnapshot) saves instead the
+// announcement key of every item seen, and announces the items whose key has never been seen.
+// Keys are built from the TMDB id when Jellyfin knows it, so they survive a re-import of the library,
+// and from the Jellyfin item ID otherwise. Seasons and episodes are identified by their number within the series.
+
+// newItemPredicate reports whether an item is new, from its announcement key or its addition date.
+type newItemPredicate func(announcementKey string, additionDate time.Time) bool
+
+// addedAfter returns the predicate of the date detection: the items added after minimumAdditionDate are new.
+func addedAfter(minimumAdditionDate time.Time) newItemPredicate {
+	return func(_ string, additionDate time.Time) bool {
+		return additionDate.After(minimumAdditionDate)
+	}
+}
+
+// notAnnounced returns the predicate of the snapshot detection: the items whose key is not announced are new.
+func notAnnounced(announcedKeys map[string]bool) newItemPredicate {
+	return func(announcementKey string, _ time.Time) bool {
+		return !announcedKeys[announcementKey]
+	}
+}
+
+// providerAnnouncementKey returns the key of a movie or a series, e.g. "movie/tmdb/603".
+func providerAnnouncementKey(itemType string, itemID string, tmdbID string) string {
+	if tmdbID != "" {
+		return itemType + "/tmdb/" + tmdbID
+	}
+	return itemType + "/jellyfin/" + normalizeJellyfinID(itemID)
+}
+
+func movieAnnouncementKey(movie MovieItem) string {
+	return providerAnnouncementKey("movie", movie.ID, movie.TMDBId)
+}
+
+func seriesAnnouncementKey(seriesID string, tmdbID string) string {
+	return providerAnnouncementKey("series", seriesID, tmdbID)
+}
+
+// seasonAnnouncementKey returns the key of a season, e.g. "series/tmdb/1399/S1".
+func seasonAnnouncementKey(seriesKey string, seasonNumber int32) string {
+	return fmt.Sprintf("%s/S%d", seriesKey, seasonNumber)
+}
+
+// episodeAnnouncementKey returns the key of an episode, e.g. "series/tmdb/1399/S1E3".
+// Episodes without number are identified by their Jellyfin item ID.
+func episodeAnnouncementKey(seasonKey string, episodeID string, episodeNumber int32) string {
+	if episodeNumber == 0 {
+		return seasonKey + "/jellyfin/" + normalizeJellyfinID(episodeID)
+	}
+	return fmt.Sprintf("%sE%d", seasonKey, episodeNumber)
+}
+
+func musicAlbumAnnouncementKey(album MusicAlbumItem) string {
+	return "album/jellyfin/" + normalizeJellyfinID(album.ID)
+}
+
+func bookAnnouncementKey(book BookItem) string {
+	return "book/jellyfin/" + normalizeJellyfinID(book.ID)
+}
+
+// GetMoviesAnnouncementKeys returns the announcement keys of the movies.
+func GetMoviesAnnouncementKeys(movies *[]MovieItem) []string {
+	keys := []string{}
+	for _, movie := range *movies {
+		keys = append(keys, movieAnnouncementKey(movie))
+	}
+	return keys
+}
+
+// FilterUnannouncedMovies returns the movies whose announcement key is not in announcedKeys.
+func FilterUnannouncedMovies(movies *[]MovieItem, announcedKeys map[string]bool) *[]MovieItem {
+	items := []MovieItem{}
+	for _, movie := range *movies {
+		if !announcedKeys[movieAnnouncementKey(movie)] {
+			items = append(items, movie)
+		}
+	}
+	return &items
+}
+
+// getSeriesAnnouncementKeys returns the announcement keys of the series, with their seasons and episodes.
+func getSeriesAnnouncementKeys(seriesItems map[string]seriesItem) []string {
+	keys := []string{}
+	for seriesID, series := range seriesItems {
+		seriesKey := seriesAnnouncementKey(seriesID, series.TMDBId)
+		keys = append(keys, seriesKey)
+		for _, season := range series.Seasons {
+			seasonKey := seasonAnnouncementKey(seriesKey, season.SeasonNumber)
+			keys = append(keys, seasonKey)
+			for episodeID, episode := range season.Episodes {
+				keys = append(keys, episodeAnnouncementKey(seasonKey, episodeID, episode.EpisodeNumber))
+			}
+		}
+	}
+	return keys
+}
+
+// GetUnannouncedSeries returns the series, seasons and episodes of all watched series folders
+// (see `getWatchedFolders`) whose announcement key is not in announcedKeys,
+// and the announcement keys of all the series, seasons and episodes seen.
+// Folders that can't be read are ignored.
+func (client *APIClient) GetUnannouncedSeries(
+	announcedKeys map[string]bool,
+	app *app.ApplicationContext,
+) (*[]NewlyAddedSeriesItem, []string) {
+	app.Logger.Debug("Searching for unannounced series ...", zap.Int("announced items", len(announcedKeys)))
+	newSeries := []NewlyAddedSeriesItem{}
+	seenKeys := []string{}
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_TVSHOWS,
+		app.Config.Jellyfin.WatchedSeriesFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
+		// Every item is added after the zero time, so the whole folder is listed
+		seriesItems, err := client.fetchAndParseSeries(folderName, time.Time{}, app)
+		if err != nil {
+			continue
+		}
+		seenKeys = append(seenKeys, getSeriesAnnouncementKeys(seriesItems)...)
+		newSeries = append(newSeries, client.buildNewlyAddedSeriesList(seriesItems, notAnnounced(announcedKeys))...)
+	}
+	return &newSeries, seenKeys
+}
+
+// GetUnannouncedMusicAlbums returns the music albums of all watched music folders (see `getWatchedFolders`)
+// whose announcement key is not in announcedKeys, and the announcement keys of all the albums seen.
+// Folders that can't be read are ignored.
+func (client *APIClient) GetUnannouncedMusicAlbums(
+	announcedKeys map[string]bool,
+	app *app.ApplicationContext,
+) (*[]MusicAlbumItem, []string) {
+	app.Logger.Debug("Searching for unannounced music ...", zap.Int("announced items", len(announcedKeys)))
+	albumItems := []MusicAlbumItem{}
+	seenKeys := []string{}
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_MUSIC,
+		app.Config.Jellyfin.WatchedMusicFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
+		items, err := client.getRecentlyAddedMusicAlbumsByFolder(time.Time{}, folderName, app)
+		if err != nil {
+			continue
+		}
+		for _, album := range items {
+			key := musicAlbumAnnouncementKey(album)
+			seenKeys = append(seenKeys, key)
+			if !announcedKeys[key] {
+				albumItems = append(albumItems, album)
+			}
+		}
+	}
+	return &albumItems, seenKeys
+}
+
+// GetUnannouncedBooks returns the books and audiobooks of all watched book folders (see `getWatchedFolders`)
+// whose announcement key is not in announcedKeys, and the announcement keys of all the books seen.
+// Folders that can't be read are ignored.
+func (client *APIClient) GetUnannouncedBooks(
+	announcedKeys map[string]bool,
+	app *app.ApplicationContext,
+) (*[]BookItem, []string) {
+	app.Logger.Debug("Searching for unannounced books ...", zap.Int("announced items", len(announcedKeys)))
+	bookItems := []BookItem{}
+	seenKeys := []string{}
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_BOOKS,
+		app.Config.Jellyfin.WatchedBookFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
+		items, err := client.getRecentlyAddedBooksByFolder(time.Time{}, folderName, app)
+		if err != nil {
+			continue
+		}
+		for _, book := range items {
+			key := bookAnnouncementKey(book)
+			seenKeys = append(seenKeys, key)
+			if !announcedKeys[key] {
+				bookItems = append(bookItems, book)
+			}
+		}
+	}
+	return &bookItems, seenKeys
+}
@@ -0,0 +1,135 @@
+package jellyfin
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnouncementKeys(t *testing.T) {
+	assert.Equal(t, "movie/tmdb/603", movieAnnouncementKey(MovieItem{ID: "id", TMDBId: "603"}))
+	assert.Equal(
+		t,
+		"movie/jellyfin/8b54388aca994d4fb867944d3150a7e0",
+		movieAnnouncementKey(MovieItem{ID: "8B54388A-CA99-4D4F-B867-944D3150A7E0"}),
+	)
+	seriesKey := seriesAnnouncementKey("id", "1399")
+	assert.Equal(t, "series/tmdb/1399", seriesKey)
+	assert.Equal(t, "series/tmdb/1399/S1", seasonAnnouncementKey(seriesKey, 1))
+	assert.Equal(t, "series/tmdb/1399/S1E3", episodeAnnouncementKey("series/tmdb/1399/S1", "episode", 3))
+	assert.Equal(t, "series/tmdb/1399/S1/jellyfin/episode", episodeAnnouncementKey("series/tmdb/1399/S1", "episode", 0))
+}
+
+func TestFilterUnannouncedMovies(t *testing.T) {
+	movies := []MovieItem{
+		{ID: "announced", TMDBId: "603"},
+		// Re-imported: new Jellyfin item ID, same TMDB id
+		{ID: "re-imported", TMDBId: "604"},
+		{ID: "new", TMDBId: "605"},
+		{ID: "withouttmdb"},
+	}
+	announcedKeys := map[string]bool{"movie/tmdb/603": true, "movie/tmdb/604": true}
+
+	unannouncedMovies := FilterUnannouncedMovies(&movies, announcedKeys)
+
+	assert.Equal(t, []MovieItem{{ID: "new", TMDBId: "605"}, {ID: "withouttmdb"}}, *unannouncedMovies)
+	assert.Equal(
+		t,
+		[]string{"movie/tmdb/603", "movie/tmdb/604", "movie/tmdb/605", "movie/jellyfin/withouttmdb"},
+		GetMoviesAnnouncementKeys(&movies),
+	)
+}
+
+func TestGetUnannouncedSeries(t *testing.T) {
+	app, _ := initApp()
+	app.Config.Jellyfin.WatchedSeriesFolders = []string{"Shows"}
+	// Creation dates are reset by a rescan, they are not used
+	additionDate := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+	episode := func(id string, number int32) jellyfinAPI.BaseItemDto {
+		return jellyfinAPI.BaseItemDto{
+			Id:           new(id),
+			Name:         *jellyfinAPI.NewNullableString(new(id)),
+			Type:         new(jellyfinAPI.BASEITEMKIND_EPISODE),
+			DateCreated:  *jellyfinAPI.NewNullableTime(&additionDate),
+			LocationType: *jellyfinAPI.NewNullableLocationType(new(jellyfinAPI.LOCATIONTYPE_FILE_SYSTEM)),
+			SeriesId:     *jellyfinAPI.NewNullableString(new("series")),
+			SeasonId:     *jellyfinAPI.NewNullableString(new("season")),
+			IndexNumber:  *jellyfinAPI.NewNullableInt32(new(number)),
+		}
+	}
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(minimumAdditionDate time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			assert.True(t, minimumAdditionDate.IsZero())
+			return &[]jellyfinAPI.BaseItemDto{
+				{
+					Id:          new("series"),
+					Name:        *jellyfinAPI.NewNullableString(new("Game of Thrones")),
+					Type:        new(jellyfinAPI.BASEITEMKIND_SERIES),
+					DateCreated: *jellyfinAPI.NewNullableTime(&additionDate),
+					ProviderIds: map[string]string{"Tmdb": "1399"},
+				},
+				{
+					Id:          new("season"),
+					Name:        *jellyfinAPI.NewNullableString(new("Season 1")),
+					Type:        new(jellyfinAPI.BASEITEMKIND_SEASON),
+					DateCreated: *jellyfinAPI.NewNullableTime(&additionDate),
+					SeriesId:    *jellyfinAPI.NewNullableString(new("series")),
+					IndexNumber: *jellyfinAPI.NewNullableInt32(new(int32(1))),
+				},
+				episode("episode 1", 1),
+				episode("episode 2", 2),
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{ItemsAPI: mockItemsAPI}
+	announcedKeys := map[string]bool{
+		"series/tmdb/1399":      true,
+		"series/tmdb/1399/S1":   true,
+		"series/tmdb/1399/S1E1": true,
+	}
+
+	series, seenKeys := client.GetUnannouncedSeries(announcedKeys, app)
+
+	require.Len(t, *series, 1)
+	assert.False(t, (*series)[0].IsSeriesNew)
+	require.Contains(t, (*series)[0].NewSeasons, "season")
+	assert.False(t, (*series)[0].NewSeasons["season"].IsSeasonNew)
+	assert.Equal(t, []string{"episode 2"}, slices.Collect(maps.Keys((*series)[0].NewSeasons["season"].Episodes)))
+	assert.ElementsMatch(
+		t,
+		[]string{"series/tmdb/1399", "series/tmdb/1399/S1", "series/tmdb/1399/S1E1", "series/tmdb/1399/S1E2"},
+		seenKeys,
+	)
+}
+
+func TestGetUnannouncedBooks(t *testing.T) {
+	app, _ := initApp()
+	app.Config.Jellyfin.WatchedBookFolders = []string{"Books"}
+	additionDate := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return &[]jellyfinAPI.BaseItemDto{
+				{Id: new("announced"), DateCreated: *jellyfinAPI.NewNullableTime(&additionDate)},
+				{Id: new("new"), DateCreated: *jellyfinAPI.NewNullableTime(&additionDate)},
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{ItemsAPI: mockItemsAPI}
+
+	books, seenKeys := client.GetUnannouncedBooks(map[string]bool{"book/jellyfin/announced": true}, app)
+
+	require.Len(t, *books, 1)
+	assert.Equal(t, "new", (*books)[0].ID)
+	assert.Equal(t, []string{"book/jellyfin/announced", "book/jellyfin/new"}, seenKeys)
+}
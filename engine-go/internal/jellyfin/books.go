@@ -0,0 +1,107 @@
+package jellyfin
+
+import (
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"go.uber.org/zap"
+)
+
+// BookItem is a book or an audiobook. TMDB has no books, so all the data comes from Jellyfin metadata.
+type BookItem struct {
+	ID             string
+	Name           string
+	Author         string
+	IsAudioBook    bool
+	AdditionDate   time.Time
+	ProductionYear int32
+	Overview       string
+	ImageURL       string // Jellyfin primary image. Empty if the book has no cover
+	// ServerURL is the public URL of the Jellyfin server the item is linked to. Empty for the default link URL.
+	ServerURL string
+}
+
+// GetRecentlyAddedBooks aggregates the books and audiobooks added during
+// the observed period in all watched book folders (see `getWatchedFolders`).
+// Folders that can't be read are logged and ignored.
+func (client *APIClient) GetRecentlyAddedBooks(app *app.ApplicationContext) *[]BookItem {
+	minimumAdditionDate := getMinimumAdditionDate(app)
+	app.Logger.Debug("Searching for newly added books ...", zap.Time("minimum addition date", minimumAdditionDate))
+	bookItems := []BookItem{}
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_BOOKS,
+		app.Config.Jellyfin.WatchedBookFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
+		if items, err := client.getRecentlyAddedBooksByFolder(minimumAdditionDate, folderName, app); err == nil {
+			bookItems = append(bookItems, items...)
+		}
+	}
+	return &bookItems
+}
+
+// getRecentlyAddedBooksByFolder retrieves the books and audiobooks added
+// after `minimumAdditionDate` in a single Jellyfin folder.
+func (client *APIClient) getRecentlyAddedBooksByFolder(
+	minimumAdditionDate time.Time,
+	folderName string,
+	app *app.ApplicationContext,
+) ([]BookItem, error) {
+	app.Logger.Debug(
+		"Searching for recently added books.",
+		zap.String("FolderName", folderName),
+		zap.String("StartAdditionDate", minimumAdditionDate.String()),
+	)
+	folderID, err := client.getFolderID(folderName, app)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := client.ItemsAPI.GetItemsAddedAfterByFolderID(
+		folderID,
+		minimumAdditionDate,
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_BOOK, jellyfinAPI.BASEITEMKIND_AUDIO_BOOK},
+		[]jellyfinAPI.ItemFields{"DateCreated", "Id", "Name", "ProductionYear", "Overview", "People"},
+		app,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []BookItem{}
+	for _, book := range *books {
+		items = append(items, BookItem{
+			ID:             *book.Id,
+			Name:           OrDefault(book.Name, ""),
+			Author:         getBookAuthor(book),
+			IsAudioBook:    book.Type != nil && *book.Type == jellyfinAPI.BASEITEMKIND_AUDIO_BOOK,
+			AdditionDate:   OrDefault(book.DateCreated, time.Time{}),
+			ProductionYear: OrDefault(book.ProductionYear, 0),
+			Overview:       OrDefault(book.Overview, ""),
+			ImageURL:       getPrimaryImageURL(&book, app),
+			ServerURL:      app.Config.Jellyfin.PublicURL,
+		})
+	}
+	return items, nil
+}
+
+// getBookAuthor returns the first author of the book. Jellyfin stores the author of ebooks in the people list,
+// and the author of audiobooks (read from the audio tags) in the album artist or the artists.
+func getBookAuthor(book jellyfinAPI.BaseItemDto) string {
+	for _, person := range book.People {
+		if person.Type != nil && *person.Type == jellyfinAPI.PERSONKIND_AUTHOR {
+			if name := OrDefault(person.Name, ""); name != "" {
+				return name
+			}
+		}
+	}
+	if albumArtist := OrDefault(book.AlbumArtist, ""); albumArtist != "" {
+		return albumArtist
+	}
+	if len(book.Artists) > 0 {
+		return book.Artists[0]
+	}
+	return ""
+}
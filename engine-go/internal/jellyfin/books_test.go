@@ -0,0 +1,117 @@
+package jellyfin
+
+import (
+	"testing"
+	"time"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecentlyAddedBooksByFolder(t *testing.T) {
+	app, recordedLogs := initApp()
+	app.Config.EmailTemplate.JellyfinURL = "https://jellyfin.example.com"
+	additionDate := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return &[]jellyfinAPI.BaseItemDto{
+				{
+					Id:             new("9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b"),
+					Name:           *jellyfinAPI.NewNullableString(new("The Hobbit")),
+					Type:           new(jellyfinAPI.BASEITEMKIND_BOOK),
+					ProductionYear: *jellyfinAPI.NewNullableInt32(new(int32(1937))),
+					DateCreated:    *jellyfinAPI.NewNullableTime(&additionDate),
+					Overview:       *jellyfinAPI.NewNullableString(new("Bilbo Baggins is swept into a quest.")),
+					ImageTags:      map[string]string{"Primary": "d4"},
+					People: []jellyfinAPI.BaseItemPerson{
+						{
+							Name: *jellyfinAPI.NewNullableString(new("Alan Lee")),
+							Type: new(jellyfinAPI.PERSONKIND_ILLUSTRATOR),
+						},
+						{
+							Name: *jellyfinAPI.NewNullableString(new("J. R. R. Tolkien")),
+							Type: new(jellyfinAPI.PERSONKIND_AUTHOR),
+						},
+					},
+				},
+				{
+					// Audiobook author read from the audio tags, no cover, no description
+					Id:          new("1f2e3d4c5b6a79881f2e3d4c5b6a7988"),
+					Name:        *jellyfinAPI.NewNullableString(new("Dune")),
+					Type:        new(jellyfinAPI.BASEITEMKIND_AUDIO_BOOK),
+					AlbumArtist: *jellyfinAPI.NewNullableString(new("Frank Herbert")),
+					DateCreated: *jellyfinAPI.NewNullableTime(&additionDate),
+				},
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{
+		ItemsAPI: mockItemsAPI,
+	}
+
+	books, err := client.getRecentlyAddedBooksByFolder(additionDate.AddDate(0, 0, -30), "Books", app)
+
+	require.NoError(t, err)
+	assert.Empty(t, recordedLogs)
+	assert.Equal(t, []BookItem{
+		{
+			ID:             "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b",
+			Name:           "The Hobbit",
+			Author:         "J. R. R. Tolkien",
+			AdditionDate:   additionDate,
+			ProductionYear: 1937,
+			Overview:       "Bilbo Baggins is swept into a quest.",
+			ImageURL:       "https://jellyfin.example.com/Items/9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b/Images/Primary?maxWidth=400&tag=d4",
+		},
+		{
+			ID:           "1f2e3d4c5b6a79881f2e3d4c5b6a7988",
+			Name:         "Dune",
+			Author:       "Frank Herbert",
+			IsAudioBook:  true,
+			AdditionDate: additionDate,
+		},
+	}, books)
+}
+
+func TestGetBookAuthor(t *testing.T) {
+	tests := []struct {
+		name           string
+		book           jellyfinAPI.BaseItemDto
+		expectedAuthor string
+	}{
+		{
+			name: "Author from people",
+			book: jellyfinAPI.BaseItemDto{
+				People: []jellyfinAPI.BaseItemPerson{
+					{Name: *jellyfinAPI.NewNullableString(new("Author")), Type: new(jellyfinAPI.PERSONKIND_AUTHOR)},
+				},
+				AlbumArtist: *jellyfinAPI.NewNullableString(new("Album artist")),
+			},
+			expectedAuthor: "Author",
+		},
+		{
+			name:           "Author from album artist",
+			book:           jellyfinAPI.BaseItemDto{AlbumArtist: *jellyfinAPI.NewNullableString(new("Album artist"))},
+			expectedAuthor: "Album artist",
+		},
+		{
+			name:           "Author from artists",
+			book:           jellyfinAPI.BaseItemDto{Artists: []string{"Artist 1", "Artist 2"}},
+			expectedAuthor: "Artist 1",
+		},
+		{
+			name:           "Unknown author",
+			book:           jellyfinAPI.BaseItemDto{},
+			expectedAuthor: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedAuthor, getBookAuthor(test.book))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package jellyfin
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	jellyfinAPI "github.com/sj14/jellyfin-go/api"
@@ -23,16 +24,32 @@ type ItemsAPIInterface interface {
 		app *app.ApplicationContext,
 	) (*[]jellyfinAPI.BaseItemDto, error)
 	GetRootFolderIDByName(folderName string, app *app.ApplicationContext) (string, error)
-	GetAllItemsByFolderID(
+	GetItemsAddedAfterByFolderID(
 		folderID string,
+		minimumAdditionDate time.Time,
+		itemTypes []jellyfinAPI.BaseItemKind,
+		fields []jellyfinAPI.ItemFields,
 		app *app.ApplicationContext,
 	) (*[]jellyfinAPI.BaseItemDto, error)
+	GetItemsByIDs(itemIDs []string, app *app.ApplicationContext) (*[]jellyfinAPI.BaseItemDto, error)
+}
+
+type LibraryStructureAPIInterface interface {
+	GetLibraries(app *app.ApplicationContext) ([]Library, error)
+}
+
+type UserAPIInterface interface {
+	GetUsers(app *app.ApplicationContext) ([]User, error)
 }
 
 type APIClient struct {
-	SystemAPI  SystemAPIInterface
-	ItemsAPI   ItemsAPIInterface
-	LibraryAPI LibraryAPIInterface
+	SystemAPI           SystemAPIInterface
+	ItemsAPI            ItemsAPIInterface
+	LibraryAPI          LibraryAPIInterface
+	LibraryStructureAPI LibraryStructureAPIInterface
+	UserAPI             UserAPIInterface
+	// libraries caches the Jellyfin libraries, so they are fetched at most once per workflow run.
+	libraries []Library
 }
 
 func NewJellyfinAPIClient(httpClient *http.Client, app *app.ApplicationContext) APIClient {
@@ -53,5 +70,11 @@ func NewJellyfinAPIClient(httpClient *http.Client, app *app.ApplicationContext)
 		LibraryAPI: libraryItemAPI{
 			client.LibraryAPI,
 		},
+		LibraryStructureAPI: jellyfinLibraryStructureAPI{
+			client.LibraryStructureAPI,
+		},
+		UserAPI: jellyfinUserAPI{
+			client.UserAPI,
+		},
 	}
 }
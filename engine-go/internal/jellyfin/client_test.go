@@ -1,14 +1,17 @@
 package jellyfin
 
 import (
+	"time"
+
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	jellyfinAPI "github.com/sj14/jellyfin-go/api"
 )
 
 type MockJellyfinItemsAPI struct {
-	ExecuteGetMoviesItemsByFolderID func() (*[]jellyfinAPI.BaseItemDto, error)
-	ExecuteGetRootFolderIDByName    func() (string, error)
-	ExecuteGetAllItemsByFolderID    func() (*[]jellyfinAPI.BaseItemDto, error)
+	ExecuteGetMoviesItemsByFolderID     func() (*[]jellyfinAPI.BaseItemDto, error)
+	ExecuteGetRootFolderIDByName        func() (string, error)
+	ExecuteGetItemsAddedAfterByFolderID func(minimumAdditionDate time.Time) (*[]jellyfinAPI.BaseItemDto, error)
+	ExecuteGetItemsByIDs                func(itemIDs []string) (*[]jellyfinAPI.BaseItemDto, error)
 }
 
 func (m MockJellyfinItemsAPI) GetMoviesItemsByFolderID(
@@ -19,13 +22,39 @@ func (m MockJellyfinItemsAPI) GetMoviesItemsByFolderID(
 	return m.ExecuteGetMoviesItemsByFolderID()
 }
 
-func (m MockJellyfinItemsAPI) GetAllItemsByFolderID(
+func (m MockJellyfinItemsAPI) GetItemsAddedAfterByFolderID(
 	_ string,
+	minimumAdditionDate time.Time,
+	_ []jellyfinAPI.BaseItemKind,
+	_ []jellyfinAPI.ItemFields,
 	_ *app.ApplicationContext,
 ) (*[]jellyfinAPI.BaseItemDto, error) {
-	return m.ExecuteGetAllItemsByFolderID()
+	return m.ExecuteGetItemsAddedAfterByFolderID(minimumAdditionDate)
+}
+
+func (m MockJellyfinItemsAPI) GetItemsByIDs(
+	itemIDs []string,
+	_ *app.ApplicationContext,
+) (*[]jellyfinAPI.BaseItemDto, error) {
+	return m.ExecuteGetItemsByIDs(itemIDs)
 }
 
 func (m MockJellyfinItemsAPI) GetRootFolderIDByName(_ string, _ *app.ApplicationContext) (string, error) {
 	return m.ExecuteGetRootFolderIDByName()
 }
+
+type MockJellyfinLibraryStructureAPI struct {
+	ExecuteGetLibraries func() ([]Library, error)
+}
+
+func (m MockJellyfinLibraryStructureAPI) GetLibraries(_ *app.ApplicationContext) ([]Library, error) {
+	return m.ExecuteGetLibraries()
+}
+
+type MockJellyfinUserAPI struct {
+	ExecuteGetUsers func() ([]User, error)
+}
+
+func (m MockJellyfinUserAPI) GetUsers(_ *app.ApplicationContext) ([]User, error) {
+	return m.ExecuteGetUsers()
+}
@@ -5,8 +5,10 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
 	"go.uber.org/zap"
 )
 
@@ -78,3 +80,22 @@ func checkHTTPRequest(ctx string, resp *http.Response, httpErr error, logger *za
 
 	return nil
 }
+
+// getMinimumAdditionDate returns the date after which items are considered newly added:
+// the start of the observed period, or the last newsletter datetime if
+// IgnoreItemsAddedAfterLastNewsletter is enabled and the last newsletter is more recent.
+func getMinimumAdditionDate(app *app.ApplicationContext) time.Time {
+	minimumAdditionDate := app.Clock.Now().AddDate(0, 0, app.Config.Jellyfin.ObservedPeriodDays*-1-1)
+	if app.Config.Jellyfin.IgnoreItemsAddedAfterLastNewsletter {
+		lastNewsletterDatetime, err := persistentdata.GetLastNewsletterDatetime(app)
+		if err != nil {
+			app.Logger.Warn(
+				"An error occured while reading the last newsletter datetime. This can cause items to be sent in 2 consecutive newsletters.",
+				zap.Error(err),
+			)
+		} else if lastNewsletterDatetime.After(minimumAdditionDate) {
+			minimumAdditionDate = *lastNewsletterDatetime
+		}
+	}
+	return minimumAdditionDate
+}
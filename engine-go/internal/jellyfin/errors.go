@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrItemsNotFound = errors.New("item not found")
+	ErrUserNotFound  = errors.New("jellyfin user not found")
 )
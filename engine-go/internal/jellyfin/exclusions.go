@@ -0,0 +1,131 @@
+package jellyfin
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"go.uber.org/zap"
+)
+
+// excludableItem holds the properties of a movie or a series the exclusion rules are matched on.
+type excludableItem struct {
+	ID             string
+	TMDBId         string
+	Tags           []string
+	Genres         []string
+	Path           string
+	OfficialRating string
+}
+
+// containsFold reports whether values contains value, without case.
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(candidate string) bool { return strings.EqualFold(candidate, value) })
+}
+
+// matchesPathGlob reports whether the glob pattern matches the item path or one of its parent folders.
+// Windows paths are compared with forward slashes.
+func matchesPathGlob(pattern string, itemPath string) bool {
+	pattern = strings.ReplaceAll(pattern, `\`, "/")
+	for candidate := strings.ReplaceAll(itemPath, `\`, "/"); candidate != "" && candidate != "."; {
+		if matched, _ := path.Match(pattern, candidate); matched {
+			return true
+		}
+		parent := path.Dir(candidate)
+		if parent == candidate {
+			break
+		}
+		candidate = parent
+	}
+	return false
+}
+
+// matchExclusionRule returns the first rule excluding the item, e.g. `tag "no-newsletter"`,
+// or an empty string if the item is not excluded.
+func matchExclusionRule(item excludableItem, rules config.ExclusionRules) string {
+	if slices.ContainsFunc(rules.ItemIDs, func(id string) bool {
+		return normalizeJellyfinID(id) == normalizeJellyfinID(item.ID)
+	}) {
+		return "item ID " + item.ID
+	}
+	if item.TMDBId != "" && slices.Contains(rules.TMDBIds, item.TMDBId) {
+		return "TMDB ID " + item.TMDBId
+	}
+	for _, tag := range item.Tags {
+		if containsFold(rules.Tags, tag) {
+			return `tag "` + tag + `"`
+		}
+	}
+	for _, genre := range item.Genres {
+		if containsFold(rules.Genres, genre) {
+			return `genre "` + genre + `"`
+		}
+	}
+	if item.OfficialRating != "" && containsFold(rules.OfficialRatings, item.OfficialRating) {
+		return `official rating "` + item.OfficialRating + `"`
+	}
+	if item.Path != "" {
+		for _, pattern := range rules.PathGlobs {
+			if matchesPathGlob(pattern, item.Path) {
+				return `path "` + pattern + `"`
+			}
+		}
+	}
+	return ""
+}
+
+// isExcluded reports whether an exclusion rule matches the item, and logs the matching rule.
+func isExcluded(item excludableItem, name string, app *app.ApplicationContext) bool {
+	rule := matchExclusionRule(item, app.Config.Jellyfin.Exclusions)
+	if rule == "" {
+		return false
+	}
+	app.Logger.Debug(
+		"Item excluded from the newsletter.",
+		zap.String("ItemID", item.ID),
+		zap.String("Name", name),
+		zap.String("rule", rule),
+	)
+	return true
+}
+
+// FilterExcludedMovies returns the movies no exclusion rule matches (see config.ExclusionRules).
+func FilterExcludedMovies(movies *[]MovieItem, app *app.ApplicationContext) *[]MovieItem {
+	items := []MovieItem{}
+	for _, movie := range *movies {
+		excludable := excludableItem{
+			ID:             movie.ID,
+			TMDBId:         movie.TMDBId,
+			Tags:           movie.Tags,
+			Genres:         movie.Genres,
+			Path:           movie.Path,
+			OfficialRating: movie.OfficialRating,
+		}
+		if !isExcluded(excludable, movie.Name, app) {
+			items = append(items, movie)
+		}
+	}
+	return &items
+}
+
+// FilterExcludedSeries returns the series no exclusion rule matches (see config.ExclusionRules).
+// Rules are matched on the series, so its new seasons and episodes are excluded with it.
+func FilterExcludedSeries(series *[]NewlyAddedSeriesItem, app *app.ApplicationContext) *[]NewlyAddedSeriesItem {
+	items := []NewlyAddedSeriesItem{}
+	for _, seriesItem := range *series {
+		excludable := excludableItem{
+			ID:             seriesItem.SeriesID,
+			TMDBId:         seriesItem.TMDBId,
+			Tags:           seriesItem.Tags,
+			Genres:         seriesItem.Genres,
+			Path:           seriesItem.Path,
+			OfficialRating: seriesItem.OfficialRating,
+		}
+		if !isExcluded(excludable, seriesItem.SeriesName, app) {
+			items = append(items, seriesItem)
+		}
+	}
+	return &items
+}
@@ -0,0 +1,96 @@
+package jellyfin
+
+import (
+	"testing"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMatchExclusionRule(t *testing.T) {
+	rules := config.ExclusionRules{
+		Tags:            []string{"no-newsletter"},
+		Genres:          []string{"Adult"},
+		PathGlobs:       []string{"/media/home-videos", "/media/*/private/*"},
+		OfficialRatings: []string{"XXX"},
+		ItemIDs:         []string{"8b54388a-ca99-4d4f-b867-944d3150a7e0"},
+		TMDBIds:         []string{"2876"},
+	}
+	tests := []struct {
+		name         string
+		item         excludableItem
+		expectedRule string
+	}{
+		{
+			"item ID in another format",
+			excludableItem{ID: "8b54388aca994d4fb867944d3150a7e0"},
+			"item ID 8b54388aca994d4fb867944d3150a7e0",
+		},
+		{"TMDB ID", excludableItem{ID: "id", TMDBId: "2876"}, "TMDB ID 2876"},
+		{"tag without case", excludableItem{ID: "id", Tags: []string{"4K", "No-Newsletter"}}, `tag "No-Newsletter"`},
+		{"genre", excludableItem{ID: "id", Genres: []string{"Drama", "adult"}}, `genre "adult"`},
+		{"official rating", excludableItem{ID: "id", OfficialRating: "xxx"}, `official rating "xxx"`},
+		{
+			"file in an excluded folder",
+			excludableItem{ID: "id", Path: "/media/home-videos/2024/Holidays.mp4"},
+			`path "/media/home-videos"`,
+		},
+		{
+			"windows path matching a glob",
+			excludableItem{ID: "id", Path: `\media\movies\private\Movie.mkv`},
+			`path "/media/*/private/*"`,
+		},
+		{
+			"no rule matching",
+			excludableItem{ID: "id", TMDBId: "1", Tags: []string{"4K"}, Genres: []string{"Drama"}, Path: "/media/movies/a.mkv"},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedRule, matchExclusionRule(test.item, rules))
+		})
+	}
+}
+
+func TestMatchExclusionRuleWithoutRules(t *testing.T) {
+	item := excludableItem{ID: "id", TMDBId: "2876", Tags: []string{"no-newsletter"}, Path: "/media/a.mkv"}
+
+	assert.Empty(t, matchExclusionRule(item, config.ExclusionRules{}))
+}
+
+func TestFilterExcludedMovies(t *testing.T) {
+	app, _ := initApp()
+	loggerCore, recordedLogs := observer.New(zap.DebugLevel)
+	app.Logger = zap.New(loggerCore)
+	app.Config.Jellyfin.Exclusions = config.ExclusionRules{Tags: []string{"no-newsletter"}}
+	movies := []MovieItem{
+		{ID: "1", Name: "Holidays 2024", Tags: []string{"no-newsletter"}},
+		{ID: "2", Name: "Dune"},
+	}
+
+	filteredMovies := FilterExcludedMovies(&movies, app)
+
+	assert.Equal(t, []MovieItem{movies[1]}, *filteredMovies)
+	exclusionLogs := recordedLogs.FilterMessage("Item excluded from the newsletter.").AllUntimed()
+	if assert.Len(t, exclusionLogs, 1) {
+		assert.Equal(t, "Holidays 2024", exclusionLogs[0].ContextMap()["Name"])
+		assert.Equal(t, `tag "no-newsletter"`, exclusionLogs[0].ContextMap()["rule"])
+	}
+}
+
+func TestFilterExcludedSeries(t *testing.T) {
+	app, _ := initApp()
+	app.Config.Jellyfin.Exclusions = config.ExclusionRules{OfficialRatings: []string{"TV-MA"}}
+	series := []NewlyAddedSeriesItem{
+		{SeriesID: "1", SeriesName: "Severance", OfficialRating: "TV-MA"},
+		{SeriesID: "2", SeriesName: "Bluey", OfficialRating: "TV-Y"},
+	}
+
+	filteredSeries := FilterExcludedSeries(&series, app)
+
+	assert.Equal(t, []NewlyAddedSeriesItem{series[1]}, *filteredSeries)
+}
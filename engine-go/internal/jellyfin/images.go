@@ -0,0 +1,46 @@
+package jellyfin
+
+import (
+	"net/url"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// Images are displayed by the email client of the recipients, so their URL is built from a public URL:
+// jellyfin.public_image_url, or the public URL of the server (jellyfin.public_url, then email_template.jellyfin_url)
+// when it's not set.
+// Jellyfin serves images without authentication.
+
+const primaryImageType = "Primary"
+
+// getPrimaryImageURL returns the URL of the primary image (poster, cover ...) of the item,
+// or an empty string if the item has no primary image.
+func getPrimaryImageURL(item *jellyfinAPI.BaseItemDto, app *app.ApplicationContext) string {
+	imageTag, ok := item.ImageTags[primaryImageType]
+	if !ok || item.Id == nil {
+		return ""
+	}
+
+	baseURL := app.Config.Jellyfin.PublicImageURL
+	if baseURL == "" {
+		baseURL = app.Config.Jellyfin.PublicURL
+	}
+	if baseURL == "" {
+		baseURL = app.Config.EmailTemplate.JellyfinURL
+	}
+	if baseURL == "" {
+		baseURL = app.Config.Jellyfin.URL
+	}
+	parsedBaseURL, err := url.Parse(baseURL)
+	if err != nil || parsedBaseURL.Host == "" {
+		return ""
+	}
+
+	imageURL := parsedBaseURL.JoinPath("Items", *item.Id, "Images", primaryImageType)
+	query := url.Values{}
+	query.Set("tag", imageTag)
+	query.Set("maxWidth", "400")
+	imageURL.RawQuery = query.Encode()
+	return imageURL.String()
+}
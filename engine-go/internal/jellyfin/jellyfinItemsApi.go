@@ -2,10 +2,18 @@ package jellyfin
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	jellyfinAPI "github.com/sj14/jellyfin-go/api"
-	"go.uber.org/zap"
+)
+
+const (
+	// itemsPageSize is the number of items requested per page when fetching large folders.
+	itemsPageSize = 500
+	// itemIDsBatchSize is the number of IDs requested at once when fetching items by IDs.
+	itemIDsBatchSize = 100
 )
 
 type jellyfinItemsAPI struct {
@@ -17,17 +25,31 @@ type Nullable[T any] interface {
 	Get() *T
 }
 
+// newItemsRequest returns a request on the Items endpoint. For the newsletter of a Jellyfin user,
+// items are requested on behalf of this user, so Jellyfin applies the user library access
+// and parental rating restrictions.
+func (itemsAPI jellyfinItemsAPI) newItemsRequest(app *app.ApplicationContext) jellyfinAPI.ApiGetItemsRequest {
+	request := itemsAPI.GetItems(context.Background())
+	if app.Config.Jellyfin.UserID != "" {
+		request = request.UserId(app.Config.Jellyfin.UserID)
+	}
+	return request
+}
+
 func (itemsAPI jellyfinItemsAPI) GetMoviesItemsByFolderID(
 	folderID string,
 	recursive bool,
 	app *app.ApplicationContext,
 ) (*[]jellyfinAPI.BaseItemDto, error) {
-	movies, getMoviesHTTPResponse, httpErr := itemsAPI.GetItems(context.Background()).
+	movies, getMoviesHTTPResponse, httpErr := itemsAPI.newItemsRequest(app).
 		Recursive(recursive).
 		ParentId(folderID).
 		LocationTypes([]jellyfinAPI.LocationType{jellyfinAPI.LOCATIONTYPE_FILE_SYSTEM}).
 		IsMovie(true).
-		Fields([]jellyfinAPI.ItemFields{"DateCreated", "ProviderIds", "Id", "Name", "ProductionYear"}).
+		Fields([]jellyfinAPI.ItemFields{
+			"DateCreated", "ProviderIds", "Id", "Name", "ProductionYear", "Overview", "MediaStreams", "Genres", "Tags",
+			"Path",
+		}).
 		Execute()
 
 	err := checkHTTPRequest("GetMoviesItemsByFolderID", getMoviesHTTPResponse, httpErr, app.Logger)
@@ -38,28 +60,92 @@ func (itemsAPI jellyfinItemsAPI) GetMoviesItemsByFolderID(
 	return &movies.Items, nil
 }
 
-func (itemsAPI jellyfinItemsAPI) GetAllItemsByFolderID(
+// seriesItemFields are the fields needed to rebuild the series, seasons and episodes tree,
+// to describe the series and to match the exclusion rules.
+func seriesItemFields() []jellyfinAPI.ItemFields {
+	return []jellyfinAPI.ItemFields{
+		"DateCreated", "ProviderIds", "Id", "Name", "ProductionYear", "IndexNumber", "SeriesId", "Type", "SeasonId", "Overview",
+		"Genres", "Tags", "Path",
+	}
+}
+
+// GetItemsAddedAfterByFolderID returns the items of the given types added in the folder after minimumAdditionDate,
+// with the requested fields.
+// The Items endpoint has no filter on the creation date. Instead, items are sorted by creation date (newest first)
+// and fetched page by page, until a page reaches minimumAdditionDate. This way, only the recent part of
+// the library is downloaded.
+func (itemsAPI jellyfinItemsAPI) GetItemsAddedAfterByFolderID(
 	folderID string,
+	minimumAdditionDate time.Time,
+	itemTypes []jellyfinAPI.BaseItemKind,
+	fields []jellyfinAPI.ItemFields,
 	app *app.ApplicationContext,
 ) (*[]jellyfinAPI.BaseItemDto, error) {
-	items, httpResponse, httpErr := itemsAPI.GetItems(context.Background()).
-		Recursive(true).
-		ParentId(folderID).
-		Fields([]jellyfinAPI.ItemFields{"DateCreated", "ProviderIds", "Id", "Name", "ProductionYear", "IndexNumber", "SeriesId", "Type", "SeasonId"}).
-		Execute()
-
-	err := checkHTTPRequest("GetAllItemsByFolderID", httpResponse, httpErr, app.Logger)
-	if err != nil {
-		return nil, err
+	items := []jellyfinAPI.BaseItemDto{}
+	for startIndex := int32(0); ; startIndex += itemsPageSize {
+		page, httpResponse, httpErr := itemsAPI.newItemsRequest(app).
+			Recursive(true).
+			ParentId(folderID).
+			IncludeItemTypes(itemTypes).
+			SortBy([]jellyfinAPI.ItemSortBy{jellyfinAPI.ITEMSORTBY_DATE_CREATED}).
+			SortOrder([]jellyfinAPI.SortOrder{jellyfinAPI.SORTORDER_DESCENDING}).
+			StartIndex(startIndex).
+			Limit(itemsPageSize).
+			EnableTotalRecordCount(false).
+			Fields(fields).
+			Execute()
+
+		err := checkHTTPRequest("GetItemsAddedAfterByFolderID", httpResponse, httpErr, app.Logger)
+		if err != nil {
+			return nil, err
+		}
+		httpResponse.Body.Close()
+
+		reachedMinimumAdditionDate := false
+		for _, item := range page.Items {
+			if item.DateCreated.Get() == nil {
+				// Without creation date, the item can't be placed relatively to minimumAdditionDate. It is
+				// skipped, the items after it are still sorted by creation date.
+				continue
+			}
+			if !item.DateCreated.Get().After(minimumAdditionDate) {
+				reachedMinimumAdditionDate = true
+				break
+			}
+			items = append(items, item)
+		}
+		if reachedMinimumAdditionDate || len(page.Items) < itemsPageSize {
+			return &items, nil
+		}
 	}
+}
 
-	defer httpResponse.Body.Close()
+// GetItemsByIDs returns the items with the given IDs. IDs are requested by batches to keep URLs short.
+func (itemsAPI jellyfinItemsAPI) GetItemsByIDs(
+	itemIDs []string,
+	app *app.ApplicationContext,
+) (*[]jellyfinAPI.BaseItemDto, error) {
+	items := []jellyfinAPI.BaseItemDto{}
+	for batch := range slices.Chunk(itemIDs, itemIDsBatchSize) {
+		batchItems, httpResponse, httpErr := itemsAPI.newItemsRequest(app).
+			Ids(batch).
+			EnableTotalRecordCount(false).
+			Fields(seriesItemFields()).
+			Execute()
+
+		err := checkHTTPRequest("GetItemsByIDs", httpResponse, httpErr, app.Logger)
+		if err != nil {
+			return nil, err
+		}
+		httpResponse.Body.Close()
 
-	return &items.Items, nil
+		items = append(items, batchItems.Items...)
+	}
+	return &items, nil
 }
 
 func (itemsAPI jellyfinItemsAPI) GetRootFolderIDByName(folderName string, app *app.ApplicationContext) (string, error) {
-	foldersItems, httpResponse, httpErr := itemsAPI.GetItems(context.Background()).
+	foldersItems, httpResponse, httpErr := itemsAPI.newItemsRequest(app).
 		Recursive(false).
 		LocationTypes([]jellyfinAPI.LocationType{jellyfinAPI.LOCATIONTYPE_FILE_SYSTEM}).
 		Execute()
@@ -78,11 +164,10 @@ func (itemsAPI jellyfinItemsAPI) GetRootFolderIDByName(folderName string, app *a
 		return "", ErrItemsNotFound
 	}
 	for _, item := range foldersItems.GetItems() {
-		if *item.Name.Get() == folderName {
+		if OrDefault(item.Name, "") == folderName {
 			return *item.Id, nil
 		}
 	}
-	app.Logger.Warn("Folder not found. Will ignore it.", zap.String("folderName", folderName))
 	return "", ErrItemsNotFound
 }
 
@@ -99,3 +184,15 @@ func getTMDBIDIfExist(item *jellyfinAPI.BaseItemDto) string {
 	}
 	return ""
 }
+
+// getIMDbIDIfExist returns the IMDb id of the item, empty when unknown.
+// It is used to find the item on TMDB when it has no TMDB id.
+func getIMDbIDIfExist(item *jellyfinAPI.BaseItemDto) string {
+	return item.ProviderIds["Imdb"]
+}
+
+// getTVDBIDIfExist returns the TVDB id of the item, empty when unknown.
+// It is used to find the item on TMDB when it has no TMDB id.
+func getTVDBIDIfExist(item *jellyfinAPI.BaseItemDto) string {
+	return item.ProviderIds["Tvdb"]
+}
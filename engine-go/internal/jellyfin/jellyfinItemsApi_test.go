@@ -0,0 +1,152 @@
+package jellyfin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startItemsServer serves `itemsCount` episodes, the newest first, one hour apart from `newestAdditionDate`.
+// The episodes at `undatedIndexes` have no creation date.
+// It returns the itemsAPI and a pointer to the list of requested start indexes.
+func startItemsServer(
+	t *testing.T,
+	itemsCount int,
+	newestAdditionDate time.Time,
+	undatedIndexes ...int,
+) (jellyfinItemsAPI, *[]int) {
+	requestedStartIndexes := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startIndex, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		requestedStartIndexes = append(requestedStartIndexes, startIndex)
+		assert.Equal(t, "DateCreated", r.URL.Query().Get("sortBy"))
+		assert.Equal(t, "Descending", r.URL.Query().Get("sortOrder"))
+
+		items := []jellyfinAPI.BaseItemDto{}
+		for i := startIndex; i < min(startIndex+limit, itemsCount); i++ {
+			item := jellyfinAPI.BaseItemDto{
+				Id:          new(strconv.Itoa(i)),
+				Type:        new(jellyfinAPI.BASEITEMKIND_EPISODE),
+				DateCreated: *jellyfinAPI.NewNullableTime(new(newestAdditionDate.Add(time.Duration(-i) * time.Hour))),
+			}
+			if slices.Contains(undatedIndexes, i) {
+				item.DateCreated = *jellyfinAPI.NewNullableTime(nil)
+			}
+			items = append(items, item)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jellyfinAPI.BaseItemDtoQueryResult{Items: items})
+	}))
+	t.Cleanup(server.Close)
+
+	client := jellyfinAPI.NewAPIClient(&jellyfinAPI.Configuration{
+		Servers:    jellyfinAPI.ServerConfigurations{{URL: server.URL}},
+		HTTPClient: server.Client(),
+	})
+	return jellyfinItemsAPI{client.ItemsAPI}, &requestedStartIndexes
+}
+
+func TestGetItemsAddedAfterByFolderIDStopsAtMinimumAdditionDate(t *testing.T) {
+	app, _ := initApp()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	itemsAPI, requestedStartIndexes := startItemsServer(t, 10000, now)
+
+	// 600 items are more recent than the minimum addition date: the second page is the last one requested
+	items, err := itemsAPI.GetItemsAddedAfterByFolderID(
+		"folderID",
+		now.Add(-600*time.Hour),
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_EPISODE},
+		seriesItemFields(),
+		app,
+	)
+
+	require.NoError(t, err)
+	assert.Len(t, *items, 600)
+	assert.Equal(t, []int{0, itemsPageSize}, *requestedStartIndexes)
+}
+
+func TestGetItemsAddedAfterByFolderIDStopsAtLastPage(t *testing.T) {
+	app, _ := initApp()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	itemsAPI, requestedStartIndexes := startItemsServer(t, itemsPageSize+20, now)
+
+	items, err := itemsAPI.GetItemsAddedAfterByFolderID(
+		"folderID",
+		now.AddDate(-10, 0, 0),
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_EPISODE},
+		seriesItemFields(),
+		app,
+	)
+
+	require.NoError(t, err)
+	assert.Len(t, *items, itemsPageSize+20)
+	assert.Equal(t, []int{0, itemsPageSize}, *requestedStartIndexes)
+}
+
+func TestGetItemsAddedAfterByFolderIDSkipsUndatedItems(t *testing.T) {
+	app, _ := initApp()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	itemsAPI, requestedStartIndexes := startItemsServer(t, 10000, now, 250)
+
+	items, err := itemsAPI.GetItemsAddedAfterByFolderID(
+		"folderID",
+		now.Add(-600*time.Hour),
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_EPISODE},
+		seriesItemFields(),
+		app,
+	)
+
+	require.NoError(t, err)
+	assert.Len(t, *items, 599, "the items after the undated one are kept")
+	for _, item := range *items {
+		assert.NotEqual(t, "250", *item.Id)
+	}
+	assert.Equal(t, []int{0, itemsPageSize}, *requestedStartIndexes)
+}
+
+func TestItemsAreRequestedForTheJellyfinUser(t *testing.T) {
+	requestedUserIDs := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedUserIDs = append(requestedUserIDs, r.URL.Query().Get("userId"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jellyfinAPI.BaseItemDtoQueryResult{Items: []jellyfinAPI.BaseItemDto{}})
+	}))
+	t.Cleanup(server.Close)
+	client := jellyfinAPI.NewAPIClient(&jellyfinAPI.Configuration{
+		Servers:    jellyfinAPI.ServerConfigurations{{URL: server.URL}},
+		HTTPClient: server.Client(),
+	})
+	itemsAPI := jellyfinItemsAPI{client.ItemsAPI}
+	app, _ := initApp()
+
+	// Global newsletter: items are requested with the API key permissions
+	_, err := itemsAPI.GetItemsByIDs([]string{"id"}, app)
+	require.NoError(t, err)
+
+	app.Config.Jellyfin.UserID = "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c"
+	_, err = itemsAPI.GetItemsByIDs([]string{"id"}, app)
+	require.NoError(t, err)
+	_, err = itemsAPI.GetItemsAddedAfterByFolderID(
+		"folderID",
+		time.Now(),
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_MOVIE},
+		[]jellyfinAPI.ItemFields{"DateCreated"},
+		app,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]string{"", "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c", "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c"},
+		requestedUserIDs,
+	)
+}
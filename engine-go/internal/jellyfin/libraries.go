@@ -0,0 +1,157 @@
+package jellyfin
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"go.uber.org/zap"
+)
+
+// A watched folder (or an include/exclude entry) selects a Jellyfin library by its name, its ID or one of its
+// paths on the Jellyfin server. Names can be renamed in the Jellyfin UI, IDs and paths are stable.
+
+// matches reports whether selector designates the library. IDs are compared with normalizeJellyfinID.
+func (library Library) matches(selector string) bool {
+	if selector == "" {
+		return false
+	}
+	if selector == library.Name || normalizeJellyfinID(selector) == normalizeJellyfinID(library.ID) {
+		return true
+	}
+	for _, location := range library.Locations {
+		if trimTrailingSeparator(selector) == trimTrailingSeparator(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeJellyfinID returns the ID without dashes and in lower case. Jellyfin displays IDs in both formats.
+func normalizeJellyfinID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+func trimTrailingSeparator(path string) string {
+	if len(path) <= 1 {
+		return path
+	}
+	return strings.TrimRight(path, `/\`)
+}
+
+func findLibrary(libraries []Library, selector string) (Library, bool) {
+	for _, library := range libraries {
+		if library.matches(selector) {
+			return library, true
+		}
+	}
+	return Library{}, false
+}
+
+func matchesAnyLibrarySelector(library Library, selectors []string) bool {
+	for _, selector := range selectors {
+		if library.matches(selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// getLibraries returns the Jellyfin libraries. They are fetched once, then served from the client cache.
+func (client *APIClient) getLibraries(app *app.ApplicationContext) ([]Library, error) {
+	if client.libraries != nil {
+		return client.libraries, nil
+	}
+	if client.LibraryStructureAPI == nil {
+		return nil, ErrItemsNotFound
+	}
+	libraries, err := client.LibraryStructureAPI.GetLibraries(app)
+	if err != nil {
+		return nil, err
+	}
+	client.libraries = libraries
+	return libraries, nil
+}
+
+// getWatchedFolders returns the folders to scan for the collection type (movies or tvshows).
+// By default, these are the configured folders. With library auto-discovery enabled, these are the IDs of
+// all the libraries of this collection type, filtered by the include and exclude lists.
+func (client *APIClient) getWatchedFolders(
+	collectionType jellyfinAPI.CollectionTypeOptions,
+	configuredFolders []string,
+	app *app.ApplicationContext,
+) []string {
+	jellyfinConfig := app.Config.Jellyfin
+	if !jellyfinConfig.AutoDiscoverLibraries {
+		return configuredFolders
+	}
+
+	libraries, err := client.getLibraries(app)
+	if err != nil {
+		app.Logger.Error(
+			"Impossible to list Jellyfin libraries. No library will be scanned.",
+			zap.String("collection type", string(collectionType)),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	folderIDs := []string{}
+	discoveredNames := []string{}
+	for _, library := range libraries {
+		if library.CollectionType != string(collectionType) {
+			continue
+		}
+		if len(jellyfinConfig.IncludedLibraries) > 0 &&
+			!matchesAnyLibrarySelector(library, jellyfinConfig.IncludedLibraries) {
+			continue
+		}
+		if matchesAnyLibrarySelector(library, jellyfinConfig.ExcludedLibraries) {
+			continue
+		}
+		folderIDs = append(folderIDs, library.ID)
+		discoveredNames = append(discoveredNames, library.Name)
+	}
+	app.Logger.Info(
+		"Libraries discovered.",
+		zap.String("collection type", string(collectionType)),
+		zap.Strings("libraries", discoveredNames),
+	)
+	return folderIDs
+}
+
+// getFolderID resolves a watched folder into the ID of the Jellyfin item to scan.
+// The folder is first looked up by name among Jellyfin root folders, then among the libraries by name, ID or path.
+func (client *APIClient) getFolderID(selector string, app *app.ApplicationContext) (string, error) {
+	if library, found := findLibrary(client.libraries, selector); found {
+		return library.ID, nil
+	}
+
+	folderID, err := client.ItemsAPI.GetRootFolderIDByName(selector, app)
+	if !errors.Is(err, ErrItemsNotFound) {
+		return folderID, err
+	}
+
+	libraries, librariesErr := client.getLibraries(app)
+	if library, found := findLibrary(libraries, selector); found {
+		return library.ID, nil
+	}
+
+	availableLibraries := make([]string, 0, len(libraries))
+	for _, library := range libraries {
+		availableLibraries = append(availableLibraries, library.Name+" ("+library.ID+")")
+	}
+	fields := []zap.Field{
+		zap.String("folder", selector),
+		zap.Strings("available libraries", availableLibraries),
+	}
+	if librariesErr != nil && !errors.Is(librariesErr, ErrItemsNotFound) {
+		fields = append(fields, zap.NamedError("libraries_error", librariesErr))
+	}
+	app.Logger.Error(
+		"Watched folder not found. It is ignored. The folder must be the name, the ID or the path of a Jellyfin library.",
+		fields...,
+	)
+	return "", ErrItemsNotFound
+}
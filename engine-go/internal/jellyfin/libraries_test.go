@@ -0,0 +1,250 @@
+package jellyfin
+
+import (
+	"errors"
+	"testing"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func mockedLibraries() []Library {
+	return []Library{
+		{
+			ID:             "f137a2dd21bbc1b99aa5c0f6bf02a805",
+			Name:           "Movies",
+			CollectionType: "movies",
+			Locations:      []string{"/media/movies"},
+		},
+		{
+			ID:             "a656b907eb3a73532e40e44b968d0225",
+			Name:           "Kids movies",
+			CollectionType: "movies",
+			Locations:      []string{"/media/kids/movies", "/media/kids/animation"},
+		},
+		{
+			ID:             "767bffe4f11c93ef34b805451a696a4e",
+			Name:           "Shows",
+			CollectionType: "tvshows",
+			Locations:      []string{"/media/shows"},
+		},
+		{
+			ID:             "7e64e319657a9516ec78490da03edccb",
+			Name:           "Music",
+			CollectionType: "music",
+			Locations:      []string{"/media/music"},
+		},
+	}
+}
+
+func TestLibraryMatches(t *testing.T) {
+	library := mockedLibraries()[1]
+	tests := []struct {
+		selector string
+		expected bool
+	}{
+		{selector: "Kids movies", expected: true},
+		{selector: "kids movies", expected: false},
+		{selector: "a656b907eb3a73532e40e44b968d0225", expected: true},
+		{selector: "A656B907-EB3A-7353-2E40-E44B968D0225", expected: true},
+		{selector: "/media/kids/animation", expected: true},
+		{selector: "/media/kids/animation/", expected: true},
+		{selector: "/media/kids", expected: false},
+		{selector: "", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			assert.Equal(t, test.expected, library.matches(test.selector))
+		})
+	}
+}
+
+func TestGetWatchedFoldersReturnsConfiguredFoldersByDefault(t *testing.T) {
+	app, _ := initApp()
+	client := APIClient{
+		LibraryStructureAPI: MockJellyfinLibraryStructureAPI{
+			ExecuteGetLibraries: func() ([]Library, error) {
+				t.Fatal("libraries must not be fetched when auto-discovery is disabled")
+				return nil, nil
+			},
+		},
+	}
+
+	folders := client.getWatchedFolders(jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES, []string{"Films"}, app)
+
+	assert.Equal(t, []string{"Films"}, folders)
+}
+
+func TestGetWatchedFoldersWithAutoDiscovery(t *testing.T) {
+	tests := []struct {
+		name              string
+		collectionType    jellyfinAPI.CollectionTypeOptions
+		includedLibraries []string
+		excludedLibraries []string
+		expected          []string
+	}{
+		{
+			name:           "All movies libraries",
+			collectionType: jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES,
+			expected:       []string{"f137a2dd21bbc1b99aa5c0f6bf02a805", "a656b907eb3a73532e40e44b968d0225"},
+		},
+		{
+			name:           "All tvshows libraries",
+			collectionType: jellyfinAPI.COLLECTIONTYPEOPTIONS_TVSHOWS,
+			expected:       []string{"767bffe4f11c93ef34b805451a696a4e"},
+		},
+		{
+			name:              "Excluded by path",
+			collectionType:    jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES,
+			excludedLibraries: []string{"/media/kids/movies"},
+			expected:          []string{"f137a2dd21bbc1b99aa5c0f6bf02a805"},
+		},
+		{
+			name:              "Included by name and ID",
+			collectionType:    jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES,
+			includedLibraries: []string{"Kids movies", "767bffe4f11c93ef34b805451a696a4e"},
+			expected:          []string{"a656b907eb3a73532e40e44b968d0225"},
+		},
+		{
+			name:              "Exclusion wins over inclusion",
+			collectionType:    jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES,
+			includedLibraries: []string{"Movies"},
+			excludedLibraries: []string{"Movies"},
+			expected:          []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app, _ := initApp()
+			app.Config.Jellyfin.AutoDiscoverLibraries = true
+			app.Config.Jellyfin.IncludedLibraries = test.includedLibraries
+			app.Config.Jellyfin.ExcludedLibraries = test.excludedLibraries
+			client := APIClient{
+				LibraryStructureAPI: MockJellyfinLibraryStructureAPI{
+					ExecuteGetLibraries: func() ([]Library, error) {
+						return mockedLibraries(), nil
+					},
+				},
+			}
+
+			folders := client.getWatchedFolders(test.collectionType, []string{"Ignored"}, app)
+
+			assert.Equal(t, test.expected, folders)
+		})
+	}
+}
+
+func TestGetWatchedFoldersWithAutoDiscoveryError(t *testing.T) {
+	app, recordedLogs := initApp()
+	app.Config.Jellyfin.AutoDiscoverLibraries = true
+	client := APIClient{
+		LibraryStructureAPI: MockJellyfinLibraryStructureAPI{
+			ExecuteGetLibraries: func() ([]Library, error) {
+				return nil, errors.New("error")
+			},
+		},
+	}
+
+	folders := client.getWatchedFolders(jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES, nil, app)
+
+	assert.Empty(t, folders)
+	require.Equal(t, 1, recordedLogs.Len())
+	assert.Equal(t, zapcore.ErrorLevel, recordedLogs.All()[0].Level)
+}
+
+func TestGetFolderIDByRootFolderName(t *testing.T) {
+	app, _ := initApp()
+	client := APIClient{
+		ItemsAPI: MockJellyfinItemsAPI{
+			ExecuteGetRootFolderIDByName: func() (string, error) {
+				return "root-folder-id", nil
+			},
+		},
+		LibraryStructureAPI: MockJellyfinLibraryStructureAPI{
+			ExecuteGetLibraries: func() ([]Library, error) {
+				t.Fatal("libraries must not be fetched when the root folder is found")
+				return nil, nil
+			},
+		},
+	}
+
+	folderID, err := client.getFolderID("Films", app)
+
+	require.NoError(t, err)
+	assert.Equal(t, "root-folder-id", folderID)
+}
+
+func TestGetFolderIDFallsBackOnLibraries(t *testing.T) {
+	app, recordedLogs := initApp()
+	librariesCalls := 0
+	client := APIClient{
+		ItemsAPI: MockJellyfinItemsAPI{
+			ExecuteGetRootFolderIDByName: func() (string, error) {
+				return "", ErrItemsNotFound
+			},
+		},
+		LibraryStructureAPI: MockJellyfinLibraryStructureAPI{
+			ExecuteGetLibraries: func() ([]Library, error) {
+				librariesCalls++
+				return mockedLibraries(), nil
+			},
+		},
+	}
+
+	folderID, err := client.getFolderID("/media/shows", app)
+	require.NoError(t, err)
+	assert.Equal(t, "767bffe4f11c93ef34b805451a696a4e", folderID)
+
+	folderID, err = client.getFolderID("Kids movies", app)
+	require.NoError(t, err)
+	assert.Equal(t, "a656b907eb3a73532e40e44b968d0225", folderID)
+
+	assert.Equal(t, 1, librariesCalls)
+	assert.Equal(t, 0, recordedLogs.Len())
+}
+
+func TestGetFolderIDNotFound(t *testing.T) {
+	app, recordedLogs := initApp()
+	client := APIClient{
+		ItemsAPI: MockJellyfinItemsAPI{
+			ExecuteGetRootFolderIDByName: func() (string, error) {
+				return "", ErrItemsNotFound
+			},
+		},
+		LibraryStructureAPI: MockJellyfinLibraryStructureAPI{
+			ExecuteGetLibraries: func() ([]Library, error) {
+				return mockedLibraries()[:1], nil
+			},
+		},
+	}
+
+	_, err := client.getFolderID("Films", app)
+
+	require.ErrorIs(t, err, ErrItemsNotFound)
+	require.Equal(t, 1, recordedLogs.Len())
+	logEntry := recordedLogs.All()[0]
+	assert.Equal(t, zapcore.ErrorLevel, logEntry.Level)
+	assert.Equal(t, "Films", logEntry.ContextMap()["folder"])
+	assert.Equal(
+		t,
+		[]any{"Movies (f137a2dd21bbc1b99aa5c0f6bf02a805)"},
+		logEntry.ContextMap()["available libraries"],
+	)
+}
+
+func TestGetFolderIDRootFolderError(t *testing.T) {
+	app, _ := initApp()
+	client := APIClient{
+		ItemsAPI: MockJellyfinItemsAPI{
+			ExecuteGetRootFolderIDByName: func() (string, error) {
+				return "", errors.New("error")
+			},
+		},
+	}
+
+	_, err := client.getFolderID("Films", app)
+
+	require.EqualError(t, err, "error")
+}
@@ -12,7 +12,12 @@ type libraryItemAPI struct {
 }
 
 func (libraryAPI libraryItemAPI) GetItemsStats(app *app.ApplicationContext) (int32, int32, error) {
-	itemsCounts, httpResponse, httpErr := libraryAPI.GetItemCounts(context.Background()).Execute()
+	request := libraryAPI.GetItemCounts(context.Background())
+	if app.Config.Jellyfin.UserID != "" {
+		// Only count the items the user can see
+		request = request.UserId(app.Config.Jellyfin.UserID)
+	}
+	itemsCounts, httpResponse, httpErr := request.Execute()
 
 	err := checkHTTPRequest("GetItemsStats", httpResponse, httpErr, app.Logger)
 	if err != nil {
@@ -0,0 +1,44 @@
+package jellyfin
+
+import (
+	"context"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// Library is a Jellyfin library (called "virtual folder" by the API).
+//   - ID is the ID of the library root item. It can be used as a parent ID to list the library items
+//   - Locations are the paths of the library on the Jellyfin server filesystem
+type Library struct {
+	ID             string
+	Name           string
+	CollectionType string
+	Locations      []string
+}
+
+type jellyfinLibraryStructureAPI struct {
+	jellyfinAPI.LibraryStructureAPI
+}
+
+func (libraryStructureAPI jellyfinLibraryStructureAPI) GetLibraries(app *app.ApplicationContext) ([]Library, error) {
+	virtualFolders, httpResponse, httpErr := libraryStructureAPI.GetVirtualFolders(context.Background()).Execute()
+
+	err := checkHTTPRequest("GetLibraries", httpResponse, httpErr, app.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResponse.Body.Close()
+
+	libraries := make([]Library, 0, len(virtualFolders))
+	for _, virtualFolder := range virtualFolders {
+		libraries = append(libraries, Library{
+			ID:             virtualFolder.GetItemId(),
+			Name:           virtualFolder.GetName(),
+			CollectionType: string(virtualFolder.GetCollectionType()),
+			Locations:      virtualFolder.GetLocations(),
+		})
+	}
+	return libraries, nil
+}
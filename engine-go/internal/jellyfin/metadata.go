@@ -0,0 +1,34 @@
+package jellyfin
+
+import (
+	"slices"
+	"time"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// undeterminedLanguage is the ISO 639-2 code used by Jellyfin for streams without language.
+const undeterminedLanguage = "und"
+
+// getRuntimeMinutes converts the Jellyfin run time, in ticks of 100 nanoseconds, to minutes.
+func getRuntimeMinutes(item *jellyfinAPI.BaseItemDto) int {
+	return int(time.Duration(OrDefault(item.RunTimeTicks, 0) * 100).Minutes())
+}
+
+// getStreamLanguages returns the languages of the streams of the given type (audio, subtitle ...), as reported
+// by Jellyfin (usually ISO 639-2 codes, e.g. "fre"). Duplicates and streams without language are ignored.
+// The MediaStreams field must be requested.
+func getStreamLanguages(item *jellyfinAPI.BaseItemDto, streamType jellyfinAPI.MediaStreamType) []string {
+	languages := []string{}
+	for _, stream := range item.MediaStreams {
+		if stream.Type == nil || *stream.Type != streamType {
+			continue
+		}
+		language := OrDefault(stream.Language, "")
+		if language == "" || language == undeterminedLanguage || slices.Contains(languages, language) {
+			continue
+		}
+		languages = append(languages, language)
+	}
+	return languages
+}
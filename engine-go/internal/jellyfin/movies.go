@@ -1,10 +1,11 @@
 package jellyfin
 
 import (
+	"slices"
 	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
-	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
 	"go.uber.org/zap"
 )
 
@@ -13,54 +14,100 @@ type MovieItem struct {
 	Name           string
 	AdditionDate   *time.Time
 	TMDBId         string
+	IMDbId         string // Used to find the movie on TMDB when TMDBId is unknown
+	TVDBId         string // Used to find the movie on TMDB when TMDBId is unknown
 	ProductionYear int32
-	Overview       string // Will be populated with tmdb
-	PosterURL      string // Will be populated with tmdb
+	Overview       string   // From Jellyfin, completed with tmdb when missing
+	PosterURL      string   // From Jellyfin, completed with tmdb when missing
+	Genres         []string // From Jellyfin, completed with tmdb when missing
+	RuntimeMinutes int      // From Jellyfin, completed with tmdb when missing
+	// CommunityRating is out of 10, from Jellyfin, completed with tmdb when missing. 0 if unknown.
+	CommunityRating float32
+	// CriticRating is out of 100 (e.g. Rotten Tomatoes score). 0 if unknown.
+	CriticRating      float32
+	OfficialRating    string   // Parental rating, e.g. "PG-13"
+	AudioLanguages    []string // Language codes of the audio streams, e.g. "eng"
+	SubtitleLanguages []string // Language codes of the subtitles, including external ones
+	Tags              []string
+	Path              string // Path of the file on the Jellyfin server
+	Quality           MediaQuality
+	// PreviousQuality is the quality of the replaced file, for upgraded movies only.
+	PreviousQuality MediaQuality
+	// ServerURL is the public URL of the Jellyfin server the item is linked to. Empty for the default link URL.
+	ServerURL string
 }
 
-// GetRecentlyAddedMovies aggregates recently added movies from all
-// configured watched film folders. It queries each folder via
-// `getRecentlyAddedMoviesByFolder` and returns a slice of
-// `MovieItem` for movies added within the configured observed period.
-func (client *APIClient) GetRecentlyAddedMovies(app *app.ApplicationContext) *[]MovieItem {
-	minimumAdditionDate := app.Clock.Now().AddDate(0, 0, app.Config.Jellyfin.ObservedPeriodDays*-1-1)
-	if app.Config.Jellyfin.IgnoreItemsAddedAfterLastNewsletter {
-		lastNewsletterDatetime, err := persistentdata.GetLastNewsletterDatetime(app)
-		if err != nil {
-			app.Logger.Warn(
-				"An error occured while reading the last newsletter datetime. This can cause items to be sent in 2 consecutive newsletters.",
-				zap.Error(err),
-			)
-		} else if lastNewsletterDatetime.After(minimumAdditionDate) {
-			minimumAdditionDate = *lastNewsletterDatetime
-		}
-	}
-	app.Logger.Debug("Searching for newly added movies ...", zap.Time("minimum addition date", minimumAdditionDate))
+// GetMovies returns the movies of all watched film folders (see `getWatchedFolders`),
+// whatever their addition date. Each folder is queried via `getMoviesByFolder`.
+// The whole list is needed to detect upgraded movies, see `FilterRecentlyAddedMovies`
+// for the movies added within the observed period.
+func (client *APIClient) GetMovies(app *app.ApplicationContext) *[]MovieItem {
+	app.Logger.Debug("Searching for movies ...")
 	var movieItems = []MovieItem{}
-	for _, folderName := range app.Config.Jellyfin.WatchedFilmFolders {
-		if items, err := client.getRecentlyAddedMoviesByFolder(minimumAdditionDate, folderName, app); err == nil {
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES,
+		app.Config.Jellyfin.WatchedFilmFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
+		if items, err := client.getMoviesByFolder(folderName, app); err == nil {
 			movieItems = append(movieItems, items...)
 		}
 	}
 	return &movieItems
 }
 
-// getRecentlyAddedMoviesByFolder retrieves movies for a single
-// Jellyfin folder, filters them by their addition/creation date
-// against the configured observed period, and returns only those
-// movies added after the computed cutoff. Movies without a
-// creation date are logged and ignored.
-func (client *APIClient) getRecentlyAddedMoviesByFolder(
-	minimumAdditionDate time.Time,
+// FilterRecentlyAddedMovies returns the movies added within the configured observed period.
+func FilterRecentlyAddedMovies(movies *[]MovieItem, app *app.ApplicationContext) *[]MovieItem {
+	minimumAdditionDate := getMinimumAdditionDate(app)
+	app.Logger.Debug("Searching for newly added movies ...", zap.Time("minimum addition date", minimumAdditionDate))
+	recentlyAddedMovies := filterMoviesAddedAfter(*movies, minimumAdditionDate)
+	return &recentlyAddedMovies
+}
+
+// filterMoviesAddedAfter returns the movies added after minimumAdditionDate.
+func filterMoviesAddedAfter(movies []MovieItem, minimumAdditionDate time.Time) []MovieItem {
+	var items = []MovieItem{}
+	for _, movie := range movies {
+		if movie.AdditionDate.After(minimumAdditionDate) {
+			items = append(items, movie)
+		}
+	}
+	return items
+}
+
+// SelectMoviesByIDs returns the movies whose ID is in movieIDs, e.g. the movies queued by the webhook listener.
+// IDs of items not in movies (other item types, removed or not visible items) are ignored.
+func SelectMoviesByIDs(movies *[]MovieItem, movieIDs []string) *[]MovieItem {
+	var items = []MovieItem{}
+	for _, movie := range *movies {
+		if slices.Contains(movieIDs, movie.ID) {
+			items = append(items, movie)
+		}
+	}
+	return &items
+}
+
+// MergeMovies returns the movies of firstMovies, followed by the movies of otherMovies that are not already listed.
+func MergeMovies(firstMovies *[]MovieItem, otherMovies *[]MovieItem) *[]MovieItem {
+	items := slices.Clone(*firstMovies)
+	for _, movie := range *otherMovies {
+		if !slices.ContainsFunc(items, func(item MovieItem) bool { return item.ID == movie.ID }) {
+			items = append(items, movie)
+		}
+	}
+	return &items
+}
+
+// getMoviesByFolder retrieves the movies of a single Jellyfin folder,
+// with their metadata, the quality of their video stream and their audio and subtitle languages.
+// Movies without a creation date are logged and ignored.
+func (client *APIClient) getMoviesByFolder(
 	folderName string,
 	app *app.ApplicationContext,
 ) ([]MovieItem, error) {
-	app.Logger.Debug(
-		"Searching for recently added movies.",
-		zap.String("FolderName", folderName),
-		zap.String("StartAdditionDate", minimumAdditionDate.String()),
-	)
-	folderID, err := client.ItemsAPI.GetRootFolderIDByName(folderName, app)
+	app.Logger.Debug("Searching for movies.", zap.String("FolderName", folderName))
+	folderID, err := client.getFolderID(folderName, app)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +121,6 @@ func (client *APIClient) getRecentlyAddedMoviesByFolder(
 	var items = []MovieItem{}
 	for _, movie := range *movies {
 		name := OrDefault(movie.Name, "")
-		productionYear := OrDefault(movie.ProductionYear, 0)
 
 		if !movie.DateCreated.IsSet() || movie.DateCreated.Get() == nil {
 			app.Logger.Warn(
@@ -85,16 +131,28 @@ func (client *APIClient) getRecentlyAddedMoviesByFolder(
 			continue
 		}
 
-		tmdbID := getTMDBIDIfExist(&movie)
-		if movie.DateCreated.Get().After(minimumAdditionDate) {
-			items = append(items, MovieItem{
-				ID:             *movie.Id,
-				AdditionDate:   movie.DateCreated.Get(),
-				Name:           name,
-				TMDBId:         tmdbID,
-				ProductionYear: productionYear,
-			})
-		}
+		items = append(items, MovieItem{
+			ID:                *movie.Id,
+			AdditionDate:      movie.DateCreated.Get(),
+			Name:              name,
+			TMDBId:            getTMDBIDIfExist(&movie),
+			IMDbId:            getIMDbIDIfExist(&movie),
+			TVDBId:            getTVDBIDIfExist(&movie),
+			ProductionYear:    OrDefault(movie.ProductionYear, 0),
+			Overview:          OrDefault(movie.Overview, ""),
+			PosterURL:         getPrimaryImageURL(&movie, app),
+			ServerURL:         app.Config.Jellyfin.PublicURL,
+			Genres:            movie.Genres,
+			RuntimeMinutes:    getRuntimeMinutes(&movie),
+			CommunityRating:   OrDefault(movie.CommunityRating, 0),
+			CriticRating:      OrDefault(movie.CriticRating, 0),
+			OfficialRating:    OrDefault(movie.OfficialRating, ""),
+			AudioLanguages:    getStreamLanguages(&movie, jellyfinAPI.MEDIASTREAMTYPE_AUDIO),
+			SubtitleLanguages: getStreamLanguages(&movie, jellyfinAPI.MEDIASTREAMTYPE_SUBTITLE),
+			Quality:           getMediaQuality(&movie),
+			Tags:              movie.Tags,
+			Path:              OrDefault(movie.Path, ""),
+		})
 	}
 	return items, nil
 }
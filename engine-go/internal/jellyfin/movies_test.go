@@ -30,6 +30,19 @@ func initApp() (*app.ApplicationContext, *observer.ObservedLogs) {
 	}, recordedLogs
 }
 
+// getRecentlyAddedMoviesByFolder returns the movies of the folder added after minimumAdditionDate.
+func (client *APIClient) getRecentlyAddedMoviesByFolder(
+	minimumAdditionDate time.Time,
+	folderName string,
+	app *app.ApplicationContext,
+) ([]MovieItem, error) {
+	movies, err := client.getMoviesByFolder(folderName, app)
+	if err != nil {
+		return nil, err
+	}
+	return filterMoviesAddedAfter(movies, minimumAdditionDate), nil
+}
+
 func TestGetRecentlyAddedMoviesByFolder(t *testing.T) {
 	mockedApp, recordedLogs := initApp()
 	mockedRecentlyAddedItems := []jellyfinAPI.BaseItemDto{
@@ -153,6 +166,7 @@ func TestGetRecentlyAddedMoviesByFolder(t *testing.T) {
 					expectedMovie.ProviderIds["Tmdb"],
 					movie.TMDBId,
 				)
+				assert.Equal(t, expectedMovie.ProviderIds["Imdb"], movie.IMDbId, "Movie ID %s", movie.ID)
 				break
 			}
 		}
@@ -375,3 +389,107 @@ func TestGetRecentlyAddedMoviesByFolderWithErrorWhileRetrievingMovies(t *testing
 
 	require.Error(t, err)
 }
+
+func TestGetRecentlyAddedMoviesByFolderWithJellyfinMetadata(t *testing.T) {
+	app, recordedLogs := initApp()
+	app.Config.Jellyfin.URL = "http://jellyfin:8096"
+	app.Config.Jellyfin.PublicImageURL = "https://jellyfin.example.com"
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetMoviesItemsByFolderID: func() (*[]jellyfinAPI.BaseItemDto, error) {
+			items := baseMovie()
+			items[0].Overview = *jellyfinAPI.NewNullableString(new("Overview of Movie 1"))
+			items[0].ImageTags = map[string]string{"Primary": "p1"}
+			return &items, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{
+		ItemsAPI: mockItemsAPI,
+	}
+	minimumAdditionDate := time.Now().AddDate(0, 0, app.Config.Jellyfin.ObservedPeriodDays*-1-1)
+	newlyAddedMovies, err := client.getRecentlyAddedMoviesByFolder(minimumAdditionDate, "folderName", app)
+
+	require.NoError(t, err)
+	assert.Empty(t, recordedLogs)
+	require.Len(t, newlyAddedMovies, 2)
+	assert.Equal(t, "Overview of Movie 1", newlyAddedMovies[0].Overview)
+	assert.Equal(
+		t,
+		"https://jellyfin.example.com/Items/8b54388aca994d4fb867944d3150a7e0/Images/Primary?maxWidth=400&tag=p1",
+		newlyAddedMovies[0].PosterURL,
+	)
+	// Without metadata in Jellyfin, they are left empty for TMDB
+	assert.Empty(t, newlyAddedMovies[1].Overview)
+	assert.Empty(t, newlyAddedMovies[1].PosterURL)
+}
+
+func TestGetRecentlyAddedMoviesByFolderWithRichMetadata(t *testing.T) {
+	app, recordedLogs := initApp()
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetMoviesItemsByFolderID: func() (*[]jellyfinAPI.BaseItemDto, error) {
+			items := baseMovie()
+			items[0].Genres = []string{"Science Fiction", "Drama"}
+			items[0].RunTimeTicks = *jellyfinAPI.NewNullableInt64(new(int64(101_400_000_000))) // 2h49
+			items[0].CommunityRating = *jellyfinAPI.NewNullableFloat32(new(float32(8.4)))
+			items[0].CriticRating = *jellyfinAPI.NewNullableFloat32(new(float32(73)))
+			items[0].OfficialRating = *jellyfinAPI.NewNullableString(new("PG-13"))
+			items[0].Tags = []string{"no-newsletter"}
+			items[0].Path = *jellyfinAPI.NewNullableString(new("/media/movies/Oppenheimer (2023)/Oppenheimer.mkv"))
+			items[0].MediaStreams = []jellyfinAPI.MediaStream{
+				{Type: new(jellyfinAPI.MEDIASTREAMTYPE_AUDIO), Language: *jellyfinAPI.NewNullableString(new("eng"))},
+				{Type: new(jellyfinAPI.MEDIASTREAMTYPE_AUDIO), Language: *jellyfinAPI.NewNullableString(new("fre"))},
+				{Type: new(jellyfinAPI.MEDIASTREAMTYPE_AUDIO), Language: *jellyfinAPI.NewNullableString(new("eng"))},
+				{Type: new(jellyfinAPI.MEDIASTREAMTYPE_SUBTITLE), Language: *jellyfinAPI.NewNullableString(new("fre"))},
+				{Type: new(jellyfinAPI.MEDIASTREAMTYPE_SUBTITLE), Language: *jellyfinAPI.NewNullableString(new("und"))},
+				{Type: new(jellyfinAPI.MEDIASTREAMTYPE_SUBTITLE)},
+			}
+			return &items, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{
+		ItemsAPI: mockItemsAPI,
+	}
+	minimumAdditionDate := time.Now().AddDate(0, 0, app.Config.Jellyfin.ObservedPeriodDays*-1-1)
+	newlyAddedMovies, err := client.getRecentlyAddedMoviesByFolder(minimumAdditionDate, "folderName", app)
+
+	require.NoError(t, err)
+	assert.Empty(t, recordedLogs)
+	require.Len(t, newlyAddedMovies, 2)
+	assert.Equal(t, []string{"Science Fiction", "Drama"}, newlyAddedMovies[0].Genres)
+	assert.Equal(t, 169, newlyAddedMovies[0].RuntimeMinutes)
+	assert.InDelta(t, 8.4, newlyAddedMovies[0].CommunityRating, 0.001)
+	assert.InDelta(t, 73, newlyAddedMovies[0].CriticRating, 0.001)
+	assert.Equal(t, "PG-13", newlyAddedMovies[0].OfficialRating)
+	assert.Equal(t, []string{"eng", "fre"}, newlyAddedMovies[0].AudioLanguages)
+	assert.Equal(t, []string{"fre"}, newlyAddedMovies[0].SubtitleLanguages)
+	assert.Equal(t, []string{"no-newsletter"}, newlyAddedMovies[0].Tags)
+	assert.Equal(t, "/media/movies/Oppenheimer (2023)/Oppenheimer.mkv", newlyAddedMovies[0].Path)
+	// Without metadata in Jellyfin, they are left empty
+	assert.Empty(t, newlyAddedMovies[1].Genres)
+	assert.Zero(t, newlyAddedMovies[1].RuntimeMinutes)
+	assert.Empty(t, newlyAddedMovies[1].AudioLanguages)
+}
+
+func TestSelectMoviesByIDs(t *testing.T) {
+	movies := []MovieItem{{ID: "1", Name: "Movie 1"}, {ID: "2", Name: "Movie 2"}, {ID: "3", Name: "Movie 3"}}
+
+	selectedMovies := SelectMoviesByIDs(&movies, []string{"3", "unknown", "1"})
+
+	assert.Equal(t, []MovieItem{movies[0], movies[2]}, *selectedMovies)
+	assert.Empty(t, *SelectMoviesByIDs(&movies, []string{}))
+}
+
+func TestMergeMovies(t *testing.T) {
+	recentMovies := []MovieItem{{ID: "1", Name: "Movie 1"}, {ID: "2", Name: "Movie 2"}}
+	queuedMovies := []MovieItem{{ID: "2", Name: "Movie 2"}, {ID: "3", Name: "Movie 3"}}
+
+	mergedMovies := MergeMovies(&recentMovies, &queuedMovies)
+
+	assert.Equal(t, []MovieItem{recentMovies[0], recentMovies[1], queuedMovies[1]}, *mergedMovies)
+	assert.Len(t, recentMovies, 2)
+}
@@ -0,0 +1,88 @@
+package jellyfin
+
+import (
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"go.uber.org/zap"
+)
+
+type MusicAlbumItem struct {
+	ID             string
+	Name           string
+	AlbumArtist    string
+	AdditionDate   time.Time
+	ProductionYear int32
+	ImageURL       string // Jellyfin primary image. Empty if the album has no cover
+	// ServerURL is the public URL of the Jellyfin server the item is linked to. Empty for the default link URL.
+	ServerURL string
+}
+
+// GetRecentlyAddedMusicAlbums aggregates the music albums added during
+// the observed period in all watched music folders (see `getWatchedFolders`).
+// Folders that can't be read are logged and ignored.
+func (client *APIClient) GetRecentlyAddedMusicAlbums(app *app.ApplicationContext) *[]MusicAlbumItem {
+	minimumAdditionDate := getMinimumAdditionDate(app)
+	app.Logger.Debug("Searching for newly added music ...", zap.Time("minimum addition date", minimumAdditionDate))
+	albumItems := []MusicAlbumItem{}
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_MUSIC,
+		app.Config.Jellyfin.WatchedMusicFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
+		if items, err := client.getRecentlyAddedMusicAlbumsByFolder(minimumAdditionDate, folderName, app); err == nil {
+			albumItems = append(albumItems, items...)
+		}
+	}
+	return &albumItems
+}
+
+// getRecentlyAddedMusicAlbumsByFolder retrieves the music albums added
+// after `minimumAdditionDate` in a single Jellyfin folder. The album
+// artist falls back on the first artist when Jellyfin has no album artist.
+func (client *APIClient) getRecentlyAddedMusicAlbumsByFolder(
+	minimumAdditionDate time.Time,
+	folderName string,
+	app *app.ApplicationContext,
+) ([]MusicAlbumItem, error) {
+	app.Logger.Debug(
+		"Searching for recently added music albums.",
+		zap.String("FolderName", folderName),
+		zap.String("StartAdditionDate", minimumAdditionDate.String()),
+	)
+	folderID, err := client.getFolderID(folderName, app)
+	if err != nil {
+		return nil, err
+	}
+
+	albums, err := client.ItemsAPI.GetItemsAddedAfterByFolderID(
+		folderID,
+		minimumAdditionDate,
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_MUSIC_ALBUM},
+		[]jellyfinAPI.ItemFields{"DateCreated", "Id", "Name", "ProductionYear"},
+		app,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []MusicAlbumItem{}
+	for _, album := range *albums {
+		albumArtist := OrDefault(album.AlbumArtist, "")
+		if albumArtist == "" && len(album.Artists) > 0 {
+			albumArtist = album.Artists[0]
+		}
+		items = append(items, MusicAlbumItem{
+			ID:             *album.Id,
+			Name:           OrDefault(album.Name, ""),
+			AlbumArtist:    albumArtist,
+			AdditionDate:   OrDefault(album.DateCreated, time.Time{}),
+			ProductionYear: OrDefault(album.ProductionYear, 0),
+			ImageURL:       getPrimaryImageURL(&album, app),
+			ServerURL:      app.Config.Jellyfin.PublicURL,
+		})
+	}
+	return items, nil
+}
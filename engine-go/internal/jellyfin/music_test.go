@@ -0,0 +1,140 @@
+package jellyfin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecentlyAddedMusicAlbumsByFolder(t *testing.T) {
+	app, recordedLogs := initApp()
+	app.Config.Jellyfin.URL = "http://jellyfin:8096"
+	app.Config.EmailTemplate.JellyfinURL = "https://jellyfin.example.com"
+	additionDate := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return &[]jellyfinAPI.BaseItemDto{
+				{
+					Id:             new("0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e"),
+					Name:           *jellyfinAPI.NewNullableString(new("Abbey Road")),
+					AlbumArtist:    *jellyfinAPI.NewNullableString(new("The Beatles")),
+					Artists:        []string{"John Lennon", "Paul McCartney"},
+					ProductionYear: *jellyfinAPI.NewNullableInt32(new(int32(1969))),
+					DateCreated:    *jellyfinAPI.NewNullableTime(&additionDate),
+					ImageTags:      map[string]string{"Primary": "a1"},
+				},
+				{
+					// No album artist, no cover
+					Id:          new("5b1f3e0c8a2d4b6e9f7a1c3d5e7f9a1b"),
+					Name:        *jellyfinAPI.NewNullableString(new("Random Access Memories")),
+					Artists:     []string{"Daft Punk"},
+					DateCreated: *jellyfinAPI.NewNullableTime(&additionDate),
+				},
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{
+		ItemsAPI: mockItemsAPI,
+	}
+
+	albums, err := client.getRecentlyAddedMusicAlbumsByFolder(additionDate.AddDate(0, 0, -30), "Music", app)
+
+	require.NoError(t, err)
+	assert.Empty(t, recordedLogs)
+	assert.Equal(t, []MusicAlbumItem{
+		{
+			ID:             "0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e",
+			Name:           "Abbey Road",
+			AlbumArtist:    "The Beatles",
+			AdditionDate:   additionDate,
+			ProductionYear: 1969,
+			ImageURL:       "https://jellyfin.example.com/Items/0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e/Images/Primary?maxWidth=400&tag=a1",
+		},
+		{
+			ID:           "5b1f3e0c8a2d4b6e9f7a1c3d5e7f9a1b",
+			Name:         "Random Access Memories",
+			AlbumArtist:  "Daft Punk",
+			AdditionDate: additionDate,
+		},
+	}, albums)
+}
+
+func TestGetRecentlyAddedMusicAlbumsIgnoresFailingFolders(t *testing.T) {
+	app, _ := initApp()
+	app.Config.Jellyfin.WatchedMusicFolders = []string{"Music", "Broken"}
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return &[]jellyfinAPI.BaseItemDto{
+				{
+					Id:   new("0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e"),
+					Name: *jellyfinAPI.NewNullableString(new("Abbey Road")),
+				},
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "", errors.New("connection refused")
+		},
+	}
+	client := APIClient{
+		ItemsAPI: mockItemsAPI,
+	}
+
+	albums := client.GetRecentlyAddedMusicAlbums(app)
+
+	assert.Empty(t, *albums)
+}
+
+func TestGetPrimaryImageURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		jellyfinURL      string
+		emailJellyfinURL string
+		publicImageURL   string
+		item             jellyfinAPI.BaseItemDto
+		expectedURL      string
+	}{
+		{
+			name:             "Public image URL is preferred",
+			jellyfinURL:      "http://jellyfin:8096",
+			emailJellyfinURL: "https://jellyfin.example.com/",
+			publicImageURL:   "https://images.example.com/jellyfin",
+			item:             jellyfinAPI.BaseItemDto{Id: new("abc"), ImageTags: map[string]string{"Primary": "tag"}},
+			expectedURL:      "https://images.example.com/jellyfin/Items/abc/Images/Primary?maxWidth=400&tag=tag",
+		},
+		{
+			name:             "Public URL is used when set",
+			jellyfinURL:      "http://jellyfin:8096",
+			emailJellyfinURL: "https://jellyfin.example.com/",
+			item:             jellyfinAPI.BaseItemDto{Id: new("abc"), ImageTags: map[string]string{"Primary": "tag"}},
+			expectedURL:      "https://jellyfin.example.com/Items/abc/Images/Primary?maxWidth=400&tag=tag",
+		},
+		{
+			name:        "Jellyfin URL is used as fallback",
+			jellyfinURL: "http://jellyfin:8096/jellyfin",
+			item:        jellyfinAPI.BaseItemDto{Id: new("abc"), ImageTags: map[string]string{"Primary": "tag"}},
+			expectedURL: "http://jellyfin:8096/jellyfin/Items/abc/Images/Primary?maxWidth=400&tag=tag",
+		},
+		{
+			name:        "No primary image",
+			jellyfinURL: "http://jellyfin:8096",
+			item:        jellyfinAPI.BaseItemDto{Id: new("abc"), ImageTags: map[string]string{"Backdrop": "tag"}},
+			expectedURL: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app, _ := initApp()
+			app.Config.Jellyfin.URL = test.jellyfinURL
+			app.Config.EmailTemplate.JellyfinURL = test.emailJellyfinURL
+			app.Config.Jellyfin.PublicImageURL = test.publicImageURL
+			assert.Equal(t, test.expectedURL, getPrimaryImageURL(&test.item, app))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package jellyfin
+
+import (
+	"slices"
+	"strings"
+
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// MediaQuality describes the main video stream of a media file.
+// It's used to detect files replaced by a better version (see SplitUpgradedMovies).
+type MediaQuality struct {
+	Resolution string // 4K, 1080p, 720p ...
+	VideoRange string // SDR, HDR10, DOVI ... as reported by Jellyfin
+	Codec      string // HEVC, H264, AV1 ...
+}
+
+// IsKnown reports whether Jellyfin provided the video stream of the media.
+func (quality MediaQuality) IsKnown() bool {
+	return quality.Resolution != "" || quality.Codec != ""
+}
+
+// resolutionRanks, videoRangeRanks and codecRanks order the qualities, from the worst to the best.
+// Unknown values have no rank, so they are never better than a known one.
+var (
+	resolutionRanks = map[string]int{"SD": 1, "720p": 2, "1080p": 3, "1440p": 4, "4K": 5}
+	videoRangeRanks = map[string]int{
+		string(jellyfinAPI.VIDEORANGETYPE_SDR):        1,
+		string(jellyfinAPI.VIDEORANGETYPE_HLG):        2,
+		string(jellyfinAPI.VIDEORANGETYPE_HDR10):      3,
+		string(jellyfinAPI.VIDEORANGETYPE_HDR10_PLUS): 4,
+		// Every Dolby Vision variant is the best video range
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI):                   5,
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI_WITH_HDR10):        5,
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI_WITH_HLG):          5,
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI_WITH_SDR):          5,
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI_WITH_EL):           5,
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI_WITH_HDR10_PLUS):   5,
+		string(jellyfinAPI.VIDEORANGETYPE_DOVI_WITH_ELHDR10_PLUS): 5,
+	}
+	codecRanks = map[string]int{"MPEG2VIDEO": 1, "VC1": 1, "MPEG4": 1, "H264": 2, "VP9": 3, "HEVC": 3, "AV1": 4}
+)
+
+// IsBetterThan reports whether quality is higher than other: the resolution is compared first,
+// then the video range, then the codec.
+func (quality MediaQuality) IsBetterThan(other MediaQuality) bool {
+	ranks := func(quality MediaQuality) []int {
+		return []int{
+			resolutionRanks[quality.Resolution],
+			videoRangeRanks[quality.VideoRange],
+			codecRanks[quality.Codec],
+		}
+	}
+	return slices.Compare(ranks(quality), ranks(other)) > 0
+}
+
+// String returns a short label of the quality, for example "4K HDR10 HEVC". SDR is implied, so it's omitted.
+func (quality MediaQuality) String() string {
+	parts := []string{}
+	for _, part := range []string{quality.Resolution, quality.VideoRange, quality.Codec} {
+		if part != "" && part != string(jellyfinAPI.VIDEORANGETYPE_SDR) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// getMediaQuality returns the quality of the first video stream of the item.
+// The MediaStreams field must be requested. Returns an empty quality if the item has no video stream.
+func getMediaQuality(item *jellyfinAPI.BaseItemDto) MediaQuality {
+	for _, stream := range item.MediaStreams {
+		if stream.Type == nil || *stream.Type != jellyfinAPI.MEDIASTREAMTYPE_VIDEO {
+			continue
+		}
+		quality := MediaQuality{
+			Resolution: getResolutionLabel(OrDefault(stream.Width, 0), OrDefault(stream.Height, 0)),
+			Codec:      strings.ToUpper(OrDefault(stream.Codec, "")),
+		}
+		if stream.VideoRangeType != nil && *stream.VideoRangeType != jellyfinAPI.VIDEORANGETYPE_UNKNOWN {
+			quality.VideoRange = string(*stream.VideoRangeType)
+		}
+		return quality
+	}
+	return MediaQuality{}
+}
+
+// getResolutionLabel returns the usual name of a video resolution. The width is checked first,
+// because movies with a large aspect ratio have a smaller height (e.g. 1920x800 is 1080p).
+func getResolutionLabel(width int32, height int32) string {
+	switch {
+	case width >= 3800 || height >= 2100:
+		return "4K"
+	case width >= 2500 || height >= 1400:
+		return "1440p"
+	case width >= 1900 || height >= 1000:
+		return "1080p"
+	case width >= 1200 || height >= 700:
+		return "720p"
+	case width > 0 || height > 0:
+		return "SD"
+	default:
+		return ""
+	}
+}
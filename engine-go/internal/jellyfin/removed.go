@@ -0,0 +1,190 @@
+package jellyfin
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"go.uber.org/zap"
+)
+
+// Jellyfin keeps no trace of deleted items. To detect them, the movies and series of the checked folders
+// are saved at each run (see GetLibrarySnapshot), and compared with the ones of the next run (see FindRemovedItems).
+
+type RemovedItem struct {
+	Name           string
+	ItemType       string // Jellyfin item type, "Movie" or "Series"
+	ProductionYear int32
+	TMDBId         string
+}
+
+// getRemovedMediaFolders returns the folders checked for removed media: the configured ones,
+// or by default the watched film and series folders (see `getWatchedFolders`).
+func (client *APIClient) getRemovedMediaFolders(app *app.ApplicationContext) []string {
+	if len(app.Config.Jellyfin.RemovedMediaFolders) > 0 {
+		return app.Config.Jellyfin.RemovedMediaFolders
+	}
+	return slices.Concat(
+		client.getWatchedFolders(
+			jellyfinAPI.COLLECTIONTYPEOPTIONS_MOVIES,
+			app.Config.Jellyfin.WatchedFilmFolders,
+			app,
+		),
+		client.getWatchedFolders(
+			jellyfinAPI.COLLECTIONTYPEOPTIONS_TVSHOWS,
+			app.Config.Jellyfin.WatchedSeriesFolders,
+			app,
+		),
+	)
+}
+
+// GetLibrarySnapshot returns the movies and series of the folders checked for removed media.
+// The items of a folder that cannot be scanned are copied from previousSnapshot,
+// so they are not reported as removed because of a temporary error.
+func (client *APIClient) GetLibrarySnapshot(
+	previousSnapshot persistentdata.LibrarySnapshot,
+	app *app.ApplicationContext,
+) persistentdata.LibrarySnapshot {
+	app.Logger.Debug("Taking a snapshot of the library to detect removed media ...")
+	snapshot := persistentdata.LibrarySnapshot{
+		Folders: client.getRemovedMediaFolders(app),
+		Items:   map[string]persistentdata.LibrarySnapshotItem{},
+	}
+	for _, folderName := range snapshot.Folders {
+		items, err := client.getLibrarySnapshotItemsByFolder(folderName, app)
+		if err != nil {
+			app.Logger.Warn(
+				"Impossible to check the folder for removed media. Its items of the previous run are kept.",
+				zap.String("folder", folderName),
+				zap.Error(err),
+			)
+			for itemID, item := range previousSnapshot.Items {
+				if item.Folder == folderName {
+					snapshot.Items[itemID] = item
+				}
+			}
+			continue
+		}
+		for itemID, item := range items {
+			snapshot.Items[itemID] = item
+		}
+	}
+	return snapshot
+}
+
+// getLibrarySnapshotItemsByFolder returns the movies and series of a single folder, by Jellyfin item ID.
+func (client *APIClient) getLibrarySnapshotItemsByFolder(
+	folderName string,
+	app *app.ApplicationContext,
+) (map[string]persistentdata.LibrarySnapshotItem, error) {
+	folderID, err := client.getFolderID(folderName, app)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every item is added after the zero time, so the whole folder is listed
+	jellyfinItems, err := client.ItemsAPI.GetItemsAddedAfterByFolderID(
+		folderID,
+		time.Time{},
+		[]jellyfinAPI.BaseItemKind{jellyfinAPI.BASEITEMKIND_MOVIE, jellyfinAPI.BASEITEMKIND_SERIES},
+		[]jellyfinAPI.ItemFields{"DateCreated", "ProviderIds", "Id", "Name", "ProductionYear", "Genres", "Tags", "Path"},
+		app,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := map[string]persistentdata.LibrarySnapshotItem{}
+	for _, jellyfinItem := range *jellyfinItems {
+		if jellyfinItem.Id == nil || jellyfinItem.Type == nil {
+			continue
+		}
+		excludable := excludableItem{
+			ID:             *jellyfinItem.Id,
+			TMDBId:         getTMDBIDIfExist(&jellyfinItem),
+			Tags:           jellyfinItem.Tags,
+			Genres:         jellyfinItem.Genres,
+			Path:           OrDefault(jellyfinItem.Path, ""),
+			OfficialRating: OrDefault(jellyfinItem.OfficialRating, ""),
+		}
+		items[*jellyfinItem.Id] = persistentdata.LibrarySnapshotItem{
+			Name:           OrDefault(jellyfinItem.Name, ""),
+			ItemType:       string(*jellyfinItem.Type),
+			ProductionYear: OrDefault(jellyfinItem.ProductionYear, 0),
+			TMDBId:         excludable.TMDBId,
+			Folder:         folderName,
+			Excluded:       matchExclusionRule(excludable, app.Config.Jellyfin.Exclusions) != "",
+		}
+	}
+	return items, nil
+}
+
+// libraryContent tells if a movie or a series is in library snapshots, whatever its Jellyfin item ID,
+// because the item ID changes when a file is renamed or replaced, or on another Jellyfin server.
+type libraryContent struct {
+	tmdbIds map[string]bool // By item type and TMDB id, since movies and series have their own TMDB ids
+	names   map[string]bool // By item type and lower-cased name
+}
+
+func newLibraryContent(snapshots ...persistentdata.LibrarySnapshot) libraryContent {
+	content := libraryContent{tmdbIds: map[string]bool{}, names: map[string]bool{}}
+	for _, snapshot := range snapshots {
+		for _, item := range snapshot.Items {
+			if item.TMDBId != "" {
+				content.tmdbIds[item.ItemType+"/"+item.TMDBId] = true
+			}
+			content.names[item.ItemType+"/"+strings.ToLower(item.Name)] = true
+		}
+	}
+	return content
+}
+
+// contains reports whether an item of the same type has the TMDB id or the name of item.
+func (content libraryContent) contains(item RemovedItem) bool {
+	if item.TMDBId != "" && content.tmdbIds[item.ItemType+"/"+item.TMDBId] {
+		return true
+	}
+	return content.names[item.ItemType+"/"+strings.ToLower(item.Name)]
+}
+
+// FindRemovedItems returns the items of previousSnapshot missing from snapshot, sorted by name.
+// Only the folders checked by both runs are compared, and excluded items are ignored.
+// An item is not missing if an item of the same type and TMDB id or name is still there, e.g. a movie whose file
+// was renamed or replaced, because its Jellyfin item ID changes.
+func FindRemovedItems(
+	previousSnapshot persistentdata.LibrarySnapshot,
+	snapshot persistentdata.LibrarySnapshot,
+) *[]RemovedItem {
+	remainingItems := newLibraryContent(snapshot)
+
+	removedItems := []RemovedItem{}
+	for itemID, item := range previousSnapshot.Items {
+		if _, exists := snapshot.Items[itemID]; exists || item.Excluded {
+			continue
+		}
+		if !slices.Contains(snapshot.Folders, item.Folder) {
+			continue
+		}
+		removedItem := RemovedItem{
+			Name:           item.Name,
+			ItemType:       item.ItemType,
+			ProductionYear: item.ProductionYear,
+			TMDBId:         item.TMDBId,
+		}
+		if remainingItems.contains(removedItem) {
+			continue
+		}
+		removedItems = append(removedItems, removedItem)
+	}
+	slices.SortFunc(removedItems, func(a, b RemovedItem) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)),
+			cmp.Compare(a.ProductionYear, b.ProductionYear),
+		)
+	})
+	return &removedItems
+}
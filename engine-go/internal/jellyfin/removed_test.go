@@ -0,0 +1,180 @@
+package jellyfin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLibrarySnapshot(t *testing.T) {
+	app, recordedLogs := initApp()
+	app.Config.Jellyfin.WatchedFilmFolders = []string{"Movies"}
+	app.Config.Jellyfin.WatchedSeriesFolders = []string{"Shows"}
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(minimumAdditionDate time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			assert.True(t, minimumAdditionDate.IsZero())
+			return &[]jellyfinAPI.BaseItemDto{
+				{
+					Id:             new("8b54388aca994d4fb867944d3150a7e0"),
+					Name:           *jellyfinAPI.NewNullableString(new("Dune")),
+					Type:           new(jellyfinAPI.BASEITEMKIND_MOVIE),
+					ProductionYear: *jellyfinAPI.NewNullableInt32(new(int32(2021))),
+				},
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{ItemsAPI: mockItemsAPI}
+
+	snapshot := client.GetLibrarySnapshot(persistentdata.LibrarySnapshot{}, app)
+
+	assert.Empty(t, recordedLogs)
+	assert.Equal(t, persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies", "Shows"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			// Found in both folders by the mock, the last folder wins
+			"8b54388aca994d4fb867944d3150a7e0": {
+				Name:           "Dune",
+				ItemType:       "Movie",
+				ProductionYear: 2021,
+				Folder:         "Shows",
+			},
+		},
+	}, snapshot)
+}
+
+func TestGetLibrarySnapshotWithConfiguredFolders(t *testing.T) {
+	app, _ := initApp()
+	app.Config.Jellyfin.WatchedFilmFolders = []string{"Movies"}
+	app.Config.Jellyfin.RemovedMediaFolders = []string{"Kids"}
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return &[]jellyfinAPI.BaseItemDto{}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{ItemsAPI: mockItemsAPI}
+
+	snapshot := client.GetLibrarySnapshot(persistentdata.LibrarySnapshot{}, app)
+
+	assert.Equal(t, []string{"Kids"}, snapshot.Folders)
+	assert.Empty(t, snapshot.Items)
+}
+
+func TestGetLibrarySnapshotKeepsPreviousItemsOfFailedFolder(t *testing.T) {
+	app, recordedLogs := initApp()
+	app.Config.Jellyfin.RemovedMediaFolders = []string{"Movies"}
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return nil, errors.New("jellyfin unavailable")
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{ItemsAPI: mockItemsAPI}
+	previousSnapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies", "Shows"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"movie": {Name: "Dune", ItemType: "Movie", Folder: "Movies"},
+			"show":  {Name: "Severance", ItemType: "Series", Folder: "Shows"},
+		},
+	}
+
+	snapshot := client.GetLibrarySnapshot(previousSnapshot, app)
+
+	assert.Equal(t, persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"movie": {Name: "Dune", ItemType: "Movie", Folder: "Movies"},
+		},
+	}, snapshot)
+	assert.Equal(t, 1, recordedLogs.FilterMessageSnippet("Impossible to check the folder for removed media").Len())
+	assert.Empty(t, *FindRemovedItems(previousSnapshot, snapshot))
+}
+
+func TestGetLibrarySnapshotMarksExcludedItems(t *testing.T) {
+	app, _ := initApp()
+	app.Config.Jellyfin.RemovedMediaFolders = []string{"Movies"}
+	app.Config.Jellyfin.Exclusions = config.ExclusionRules{PathGlobs: []string{"/media/home-videos"}}
+	mockItemsAPI := MockJellyfinItemsAPI{
+		ExecuteGetItemsAddedAfterByFolderID: func(_ time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return &[]jellyfinAPI.BaseItemDto{
+				{
+					Id:   new("home video"),
+					Name: *jellyfinAPI.NewNullableString(new("Holidays 2024")),
+					Type: new(jellyfinAPI.BASEITEMKIND_MOVIE),
+					Path: *jellyfinAPI.NewNullableString(new("/media/home-videos/Holidays 2024.mp4")),
+				},
+				{
+					Id:   new("movie"),
+					Name: *jellyfinAPI.NewNullableString(new("Dune")),
+					Type: new(jellyfinAPI.BASEITEMKIND_MOVIE),
+					Path: *jellyfinAPI.NewNullableString(new("/media/movies/Dune.mkv")),
+				},
+			}, nil
+		},
+		ExecuteGetRootFolderIDByName: func() (string, error) {
+			return "id", nil
+		},
+	}
+	client := APIClient{ItemsAPI: mockItemsAPI}
+
+	snapshot := client.GetLibrarySnapshot(persistentdata.LibrarySnapshot{}, app)
+
+	assert.True(t, snapshot.Items["home video"].Excluded)
+	assert.False(t, snapshot.Items["movie"].Excluded)
+}
+
+func TestFindRemovedItems(t *testing.T) {
+	previousSnapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies", "Shows", "Kids"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"still there":   {Name: "Dune", ItemType: "Movie", ProductionYear: 2021, Folder: "Movies"},
+			"removed movie": {Name: "Oppenheimer", ItemType: "Movie", ProductionYear: 2023, Folder: "Movies"},
+			"removed show":  {Name: "Fargo", ItemType: "Series", ProductionYear: 2014, Folder: "Shows"},
+			"renamed file":  {Name: "Arrival", ItemType: "Movie", ProductionYear: 2016, Folder: "Movies"},
+			"retitled":      {Name: "Blade Runner", ItemType: "Movie", TMDBId: "78", Folder: "Movies"},
+			// Kids is no longer checked, so its items are not reported
+			"not checked": {Name: "Up", ItemType: "Movie", Folder: "Kids"},
+			"excluded":    {Name: "Holidays 2024", ItemType: "Movie", Folder: "Movies", Excluded: true},
+		},
+	}
+	snapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies", "Shows"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"still there": {Name: "Dune", ItemType: "Movie", ProductionYear: 2021, Folder: "Movies"},
+			"new file ID": {Name: "Arrival", ItemType: "Movie", ProductionYear: 2016, Folder: "Movies"},
+			"new title":   {Name: "Blade Runner: The Final Cut", ItemType: "Movie", TMDBId: "78", Folder: "Movies"},
+			// A movie with the name of the removed series doesn't hide it
+			"same name movie": {Name: "Fargo", ItemType: "Movie", ProductionYear: 1996, Folder: "Movies"},
+		},
+	}
+
+	removedItems := FindRemovedItems(previousSnapshot, snapshot)
+
+	assert.Equal(t, []RemovedItem{
+		{Name: "Fargo", ItemType: "Series", ProductionYear: 2014},
+		{Name: "Oppenheimer", ItemType: "Movie", ProductionYear: 2023},
+	}, *removedItems)
+}
+
+func TestFindRemovedItemsWithoutPreviousRun(t *testing.T) {
+	snapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"movie": {Name: "Dune", ItemType: "Movie", Folder: "Movies"},
+		},
+	}
+
+	assert.Empty(t, *FindRemovedItems(persistentdata.LibrarySnapshot{}, snapshot))
+}
@@ -4,15 +4,15 @@ import (
 	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
-	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
 	jellyfinAPI "github.com/sj14/jellyfin-go/api"
 	"go.uber.org/zap"
 )
 
 type EpisodeItem struct {
-	Name          string
+	Name          string // From Jellyfin, completed with tmdb when missing
 	AdditionDate  time.Time
 	EpisodeNumber int32
+	AirDate       time.Time // From tmdb. Zero if unknown
 }
 
 type SeasonItem struct {
@@ -21,6 +21,8 @@ type SeasonItem struct {
 	AdditionDate time.Time
 	Episodes     map[string]EpisodeItem
 	IsSeasonNew  bool
+	Overview     string // From Jellyfin, completed with tmdb when missing
+	PosterURL    string // From Jellyfin, completed with tmdb when missing
 }
 
 type seriesItem struct {
@@ -29,6 +31,15 @@ type seriesItem struct {
 	ProductionYear int32
 	Seasons        map[string]SeasonItem
 	TMDBId         string
+	IMDbId         string
+	TVDBId         string
+	Overview       string
+	PosterURL      string
+	Genres         []string
+	Tags           []string
+	Path           string
+	OfficialRating string
+	ServerURL      string
 }
 
 type NewlyAddedSeriesItem struct {
@@ -37,16 +48,24 @@ type NewlyAddedSeriesItem struct {
 	IsSeriesNew    bool
 	NewSeasons     map[string]SeasonItem
 	TMDBId         string
+	IMDbId         string // Used to find the series on TMDB when TMDBId is unknown
+	TVDBId         string // Used to find the series on TMDB when TMDBId is unknown
 	ProductionYear int
 	AdditionDate   time.Time
-	Overview       string // Will be populated with tmdb
-	PosterURL      string // Will be populated with tmdb
+	Overview       string // From Jellyfin, completed with tmdb when missing
+	PosterURL      string // From Jellyfin, completed with tmdb when missing
+	Genres         []string
+	Tags           []string
+	Path           string // Path of the series folder on the Jellyfin server
+	OfficialRating string // Parental rating, e.g. "TV-MA"
+	// ServerURL is the public URL of the Jellyfin server the item is linked to. Empty for the default link URL.
+	ServerURL string
 }
 
 // parseSeriesItems scans a slice of Jellyfin BaseItemDto and extracts
 // all items of type SERIES into a map keyed by the series ID.
 // For each series it builds a `seriesItem` with name, addition date,
-// production year, an empty seasons map and the TMDB id when present.
+// production year, overview, poster, an empty seasons map and the TMDB id when present.
 // Missing addition dates are logged as warnings because they affect
 // newly-added detection.
 func parseSeriesItems(app *app.ApplicationContext, jellyfinItems *[]jellyfinAPI.BaseItemDto) map[string]seriesItem {
@@ -59,6 +78,15 @@ func parseSeriesItems(app *app.ApplicationContext, jellyfinItems *[]jellyfinAPI.
 				ProductionYear: OrDefault(item.ProductionYear, 0),
 				Seasons:        map[string]SeasonItem{},
 				TMDBId:         getTMDBIDIfExist(&item),
+				IMDbId:         getIMDbIDIfExist(&item),
+				TVDBId:         getTVDBIDIfExist(&item),
+				Overview:       OrDefault(item.Overview, ""),
+				PosterURL:      getPrimaryImageURL(&item, app),
+				Genres:         item.Genres,
+				Tags:           item.Tags,
+				Path:           OrDefault(item.Path, ""),
+				OfficialRating: OrDefault(item.OfficialRating, ""),
+				ServerURL:      app.Config.Jellyfin.PublicURL,
 			}
 			if seriesItems[*item.Id].AdditionDate.Equal(time.Date(1970, 01, 01, 00, 00, 00, 00, time.UTC)) {
 				app.Logger.Warn(
@@ -95,6 +123,8 @@ func updateSeriesWithSeasons(
 				AdditionDate: OrDefault(item.DateCreated, time.Date(1970, 01, 01, 00, 00, 00, 00, time.UTC)),
 				SeasonNumber: OrDefault(item.IndexNumber, 0),
 				Episodes:     map[string]EpisodeItem{},
+				Overview:     OrDefault(item.Overview, ""),
+				PosterURL:    getPrimaryImageURL(&item, app),
 			}
 			if _, ok := seriesItem[*item.SeriesId.Get()]; !ok {
 				app.Logger.Warn(
@@ -204,54 +234,112 @@ func (client *APIClient) getNewlyAddedSeriesByFolder(
 		zap.String("StartAdditionDate", minimumAdditionDate.String()),
 	)
 
-	seriesItem, err := client.fetchAndParseSeries(folderName, app)
+	seriesItem, err := client.fetchAndParseSeries(folderName, minimumAdditionDate, app)
 	if err != nil {
 		return nil, err
 	}
 
-	newlyAddedSeries := client.buildNewlyAddedSeriesList(seriesItem, minimumAdditionDate)
+	newlyAddedSeries := client.buildNewlyAddedSeriesList(seriesItem, addedAfter(minimumAdditionDate))
 	return &newlyAddedSeries, nil
 }
 
-// fetchAndParseSeries resolves the folder ID by name, retrieves all
-// items for that folder from the Items API, and builds a structured
-// map of `seriesItem` populated with seasons and episodes.
+// fetchAndParseSeries resolves the folder ID (see `getFolderID`), retrieves
+// the series, seasons and episodes added after `minimumAdditionDate`, then
+// their parent series and seasons, and builds a structured map of
+// `seriesItem` populated with seasons and episodes.
+// Older items without recent children are never downloaded.
 // Returns the resulting map or an error encountered while calling
 // the Items API.
 func (client *APIClient) fetchAndParseSeries(
 	folderName string,
+	minimumAdditionDate time.Time,
 	app *app.ApplicationContext,
 ) (map[string]seriesItem, error) {
-	folderID, err := client.ItemsAPI.GetRootFolderIDByName(folderName, app)
+	folderID, err := client.getFolderID(folderName, app)
 	if err != nil {
 		return nil, err
 	}
 
-	jellyfinItems, err := client.ItemsAPI.GetAllItemsByFolderID(folderID, app)
+	recentItems, err := client.ItemsAPI.GetItemsAddedAfterByFolderID(
+		folderID,
+		minimumAdditionDate,
+		[]jellyfinAPI.BaseItemKind{
+			jellyfinAPI.BASEITEMKIND_SERIES,
+			jellyfinAPI.BASEITEMKIND_SEASON,
+			jellyfinAPI.BASEITEMKIND_EPISODE,
+		},
+		seriesItemFields(),
+		app,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	seriesItem := parseSeriesItems(app, jellyfinItems)
-	updateSeriesWithSeasons(jellyfinItems, seriesItem, app)
-	updateSeriesWithEpisode(jellyfinItems, seriesItem, app)
+	jellyfinItems := []jellyfinAPI.BaseItemDto{}
+	if parentIDs := missingParentIDs(recentItems); len(parentIDs) > 0 {
+		parentItems, parentErr := client.ItemsAPI.GetItemsByIDs(parentIDs, app)
+		if parentErr != nil {
+			return nil, parentErr
+		}
+		jellyfinItems = append(jellyfinItems, *parentItems...)
+	}
+	jellyfinItems = append(jellyfinItems, *recentItems...)
+
+	seriesItem := parseSeriesItems(app, &jellyfinItems)
+	updateSeriesWithSeasons(&jellyfinItems, seriesItem, app)
+	updateSeriesWithEpisode(&jellyfinItems, seriesItem, app)
 
 	return seriesItem, nil
 }
 
+// missingParentIDs returns the IDs of the series and seasons referenced by
+// the given seasons and episodes, but absent from the items themselves.
+// IDs are deduplicated and returned in order of appearance.
+func missingParentIDs(jellyfinItems *[]jellyfinAPI.BaseItemDto) []string {
+	knownIDs := map[string]bool{}
+	for _, item := range *jellyfinItems {
+		if item.Id != nil {
+			knownIDs[*item.Id] = true
+		}
+	}
+
+	parentIDs := []string{}
+	addParentID := func(parentID string) {
+		if parentID == "" || knownIDs[parentID] {
+			return
+		}
+		knownIDs[parentID] = true
+		parentIDs = append(parentIDs, parentID)
+	}
+	for _, item := range *jellyfinItems {
+		if item.Type == nil {
+			continue
+		}
+		switch *item.Type {
+		case jellyfinAPI.BASEITEMKIND_SEASON:
+			addParentID(OrDefault(item.SeriesId, ""))
+		case jellyfinAPI.BASEITEMKIND_EPISODE:
+			addParentID(OrDefault(item.SeriesId, ""))
+			addParentID(OrDefault(item.SeasonId, ""))
+		default:
+		}
+	}
+	return parentIDs
+}
+
 // buildNewlyAddedSeriesList walks the parsed series map and converts
 // each `seriesItem` into a `NewlyAddedSeriesItem` using
 // `createNewlyAddedSeriesItem`. Only series that are entirely new or
-// contain new seasons/episodes (after `minimumAdditionDate`) are
+// contain new seasons/episodes (according to `isNew`) are
 // included in the returned slice.
 func (client *APIClient) buildNewlyAddedSeriesList(
 	seriesItem map[string]seriesItem,
-	minimumAdditionDate time.Time,
+	isNew newItemPredicate,
 ) []NewlyAddedSeriesItem {
 	var newlyAddedSeries []NewlyAddedSeriesItem
 
 	for seriesID, series := range seriesItem {
-		newSeries := client.createNewlyAddedSeriesItem(seriesID, series, minimumAdditionDate)
+		newSeries := client.createNewlyAddedSeriesItem(seriesID, series, isNew)
 
 		if newSeries.IsSeriesNew || newSeries.NewSeasons != nil {
 			newlyAddedSeries = append(newlyAddedSeries, newSeries)
@@ -262,45 +350,55 @@ func (client *APIClient) buildNewlyAddedSeriesList(
 }
 
 // createNewlyAddedSeriesItem builds a `NewlyAddedSeriesItem` from a
-// `seriesItem`. If the series addition date is after the cutoff the
-// series is marked as new. Otherwise the function scans seasons to
-// detect newly added seasons or episodes and populates `NewSeasons`.
+// `seriesItem`. If the series itself is new the series is marked as
+// new. Otherwise the function scans seasons to detect newly added
+// seasons or episodes and populates `NewSeasons`.
 func (client *APIClient) createNewlyAddedSeriesItem(
 	seriesID string,
 	series seriesItem,
-	minimumAdditionDate time.Time,
+	isNew newItemPredicate,
 ) NewlyAddedSeriesItem {
 	newSeries := NewlyAddedSeriesItem{
 		SeriesName:     series.Name,
 		SeriesID:       seriesID,
 		TMDBId:         series.TMDBId,
+		IMDbId:         series.IMDbId,
+		TVDBId:         series.TVDBId,
 		ProductionYear: int(series.ProductionYear),
 		AdditionDate:   series.AdditionDate,
+		Overview:       series.Overview,
+		PosterURL:      series.PosterURL,
+		Genres:         series.Genres,
+		Tags:           series.Tags,
+		Path:           series.Path,
+		OfficialRating: series.OfficialRating,
+		ServerURL:      series.ServerURL,
 	}
 
-	if series.AdditionDate.After(minimumAdditionDate) {
+	seriesKey := seriesAnnouncementKey(seriesID, series.TMDBId)
+	if isNew(seriesKey, series.AdditionDate) {
 		newSeries.IsSeriesNew = true
 		return newSeries
 	}
 
 	newSeries.IsSeriesNew = false
-	newSeries.NewSeasons = client.findNewSeasons(series.Seasons, minimumAdditionDate)
+	newSeries.NewSeasons = client.findNewSeasons(seriesKey, series.Seasons, isNew)
 
 	return newSeries
 }
 
 // findNewSeasons iterates over seasons and returns a map of seasons
-// that are newly added or contain newly added episodes relative to
-// `minimumAdditionDate`. The returned map is nil when no new seasons
-// are found.
+// that are newly added or contain newly added episodes according to
+// `isNew`. The returned map is nil when no new seasons are found.
 func (client *APIClient) findNewSeasons(
+	seriesKey string,
 	seasons map[string]SeasonItem,
-	minimumAdditionDate time.Time,
+	isNew newItemPredicate,
 ) map[string]SeasonItem {
 	var newSeasons map[string]SeasonItem
 
 	for seasonID, season := range seasons {
-		newSeason := client.processSeasonForNewContent(season, minimumAdditionDate)
+		newSeason := client.processSeasonForNewContent(seriesKey, season, isNew)
 
 		if newSeason.IsSeasonNew || newSeason.Episodes != nil {
 			if newSeasons == nil {
@@ -314,42 +412,47 @@ func (client *APIClient) findNewSeasons(
 }
 
 // processSeasonForNewContent returns a `SeasonItem` describing whether
-// the season itself is new (based on addition date) or contains newly
-// added episodes. If the season is new it is marked accordingly and
+// the season itself is new or contains newly added episodes.
+// If the season is new it is marked accordingly and
 // returned without episode details; otherwise the episode map is
 // scanned and returned when new episodes are present.
 func (client *APIClient) processSeasonForNewContent(
+	seriesKey string,
 	season SeasonItem,
-	minimumAdditionDate time.Time,
+	isNew newItemPredicate,
 ) SeasonItem {
 	newSeason := SeasonItem{
 		SeasonNumber: season.SeasonNumber,
 		Name:         season.Name,
 		AdditionDate: season.AdditionDate,
+		Overview:     season.Overview,
+		PosterURL:    season.PosterURL,
 	}
 
-	if season.AdditionDate.After(minimumAdditionDate) {
+	seasonKey := seasonAnnouncementKey(seriesKey, season.SeasonNumber)
+	if isNew(seasonKey, season.AdditionDate) {
 		newSeason.IsSeasonNew = true
 		return newSeason
 	}
 
 	newSeason.IsSeasonNew = false
-	newSeason.Episodes = client.findNewEpisodes(season.Episodes, minimumAdditionDate)
+	newSeason.Episodes = client.findNewEpisodes(seasonKey, season.Episodes, isNew)
 
 	return newSeason
 }
 
 // findNewEpisodes filters episodes and returns a map containing only
-// those episodes whose addition date is after `minimumAdditionDate`.
+// the new episodes according to `isNew`.
 // Returns nil when no new episodes are found.
 func (client *APIClient) findNewEpisodes(
+	seasonKey string,
 	episodes map[string]EpisodeItem,
-	minimumAdditionDate time.Time,
+	isNew newItemPredicate,
 ) map[string]EpisodeItem {
 	var newEpisodes map[string]EpisodeItem
 
 	for episodeID, episode := range episodes {
-		if episode.AdditionDate.After(minimumAdditionDate) {
+		if isNew(episodeAnnouncementKey(seasonKey, episodeID, episode.EpisodeNumber), episode.AdditionDate) {
 			if newEpisodes == nil {
 				newEpisodes = map[string]EpisodeItem{}
 			}
@@ -361,26 +464,20 @@ func (client *APIClient) findNewEpisodes(
 }
 
 // GetNewlyAddedSeries collects newly added series information for all
-// configured `WatchedSeriesFolders`. For each configured folder it
+// watched series folders (see `getWatchedFolders`). For each folder it
 // calls `getNewlyAddedSeriesByFolder` and aggregates the results.
 func (client *APIClient) GetNewlyAddedSeries(
 	app *app.ApplicationContext,
 ) *[]NewlyAddedSeriesItem {
 	var seriesItems = []NewlyAddedSeriesItem{}
-	minimumAdditionDate := app.Clock.Now().AddDate(0, 0, app.Config.Jellyfin.ObservedPeriodDays*-1-1)
-	if app.Config.Jellyfin.IgnoreItemsAddedAfterLastNewsletter {
-		lastNewsletterDatetime, err := persistentdata.GetLastNewsletterDatetime(app)
-		if err != nil {
-			app.Logger.Warn(
-				"An error occured while reading the last newsletter datetime. This can cause items to be sent in 2 consecutive newsletters.",
-				zap.Error(err),
-			)
-		} else if lastNewsletterDatetime.After(minimumAdditionDate) {
-			minimumAdditionDate = *lastNewsletterDatetime
-		}
-	}
+	minimumAdditionDate := getMinimumAdditionDate(app)
 	app.Logger.Debug("Searching for newly added series ...", zap.Time("minimum addition date", minimumAdditionDate))
-	for _, folderName := range app.Config.Jellyfin.WatchedSeriesFolders {
+	watchedFolders := client.getWatchedFolders(
+		jellyfinAPI.COLLECTIONTYPEOPTIONS_TVSHOWS,
+		app.Config.Jellyfin.WatchedSeriesFolders,
+		app,
+	)
+	for _, folderName := range watchedFolders {
 		if items, err := client.getNewlyAddedSeriesByFolder(minimumAdditionDate, folderName, app); err == nil {
 			seriesItems = append(seriesItems, *items...)
 		}
@@ -48,6 +48,7 @@ func getExpectedResultFromBaseItem() []NewlyAddedSeriesItem {
 			IsSeriesNew:    true,
 			NewSeasons:     nil,
 			TMDBId:         "1027",
+			IMDbId:         "2276",
 			ProductionYear: 2023,
 			AdditionDate:   time.Now().AddDate(0, 0, -7),
 		},
@@ -433,6 +434,7 @@ func testReturnedSeriesIsCorrect(t *testing.T, expected *NewlyAddedSeriesItem, r
 	assert.InDelta(t, expected.AdditionDate.Unix(), returned.AdditionDate.Unix(), 10, "Series ID %s", expected.SeriesID)
 	require.Equal(t, expected.IsSeriesNew, returned.IsSeriesNew, "Series ID %s", expected.SeriesID)
 	assert.Equal(t, expected.TMDBId, returned.TMDBId, "Series ID %s", expected.SeriesID)
+	assert.Equal(t, expected.IMDbId, returned.IMDbId, "Series ID %s", expected.SeriesID)
 	assert.Equal(t, expected.ProductionYear, returned.ProductionYear, "Series ID %s", expected.SeriesID)
 }
 
@@ -507,8 +509,11 @@ func runGetNewlyAddedSeriesTest(t *testing.T, tt TableTests) {
 	expectedResult := tt.getExpectedResultFromBaseItem()
 
 	mockItemsAPI := MockJellyfinItemsAPI{
-		ExecuteGetAllItemsByFolderID: func() (*[]jellyfinAPI.BaseItemDto, error) {
-			return &mockedJellyfinBaseItem, nil
+		ExecuteGetItemsAddedAfterByFolderID: func(minimumAdditionDate time.Time) (*[]jellyfinAPI.BaseItemDto, error) {
+			return filterItemsAddedAfter(mockedJellyfinBaseItem, minimumAdditionDate), nil
+		},
+		ExecuteGetItemsByIDs: func(itemIDs []string) (*[]jellyfinAPI.BaseItemDto, error) {
+			return filterItemsByIDs(mockedJellyfinBaseItem, itemIDs), nil
 		},
 		ExecuteGetRootFolderIDByName: func() (string, error) {
 			return "id", nil
@@ -549,6 +554,30 @@ func runGetNewlyAddedSeriesTest(t *testing.T, tt TableTests) {
 	}
 }
 
+// filterItemsAddedAfter mimics the Jellyfin API, which only returns the items added after the date.
+func filterItemsAddedAfter(
+	items []jellyfinAPI.BaseItemDto,
+	minimumAdditionDate time.Time,
+) *[]jellyfinAPI.BaseItemDto {
+	filteredItems := []jellyfinAPI.BaseItemDto{}
+	for _, item := range items {
+		if OrDefault(item.DateCreated, time.Time{}).After(minimumAdditionDate) {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+	return &filteredItems
+}
+
+func filterItemsByIDs(items []jellyfinAPI.BaseItemDto, itemIDs []string) *[]jellyfinAPI.BaseItemDto {
+	filteredItems := []jellyfinAPI.BaseItemDto{}
+	for _, item := range items {
+		if slices.Contains(itemIDs, *item.Id) {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+	return &filteredItems
+}
+
 func getBaseItemIndexByID(id string) int {
 	baseItems := getSeriesBaseItems()
 	for i, item := range baseItems {
@@ -689,22 +718,8 @@ func TestGetNewlyAddedSeries(t *testing.T) {
 		},
 		{
 			name: "episode dateCreated is null",
-			loggedMessages: []observer.LoggedEntry{
-				{
-					Entry: zapcore.Entry{
-						Level:   zapcore.WarnLevel,
-						Message: "Found an episode with no addition date. This can lead to inaccuracy when detecting newly added media.",
-					},
-					Context: []zapcore.Field{
-						zap.String("Episode ID", "cc3333-s1-e5"),
-						zap.String("Episode Name", "Episode 5"),
-						zap.String("Season Name", "Season 1"),
-						zap.String("Season ID", "cc3333-s1"),
-						zap.String("Series Name", "Very Old Series"),
-						zap.String("Series ID", "cc3333"),
-					},
-				},
-			},
+			// An episode without addition date is never returned among the recently added items
+			loggedMessages: []observer.LoggedEntry{},
 			getSeriesBaseItems: func() []jellyfinAPI.BaseItemDto {
 				baseItems := getSeriesBaseItems()
 				baseItems[getBaseItemIndexByID("cc3333-s1-e5")].DateCreated = *jellyfinAPI.NewNullableTime(nil)
@@ -756,31 +771,21 @@ func TestGetNewlyAddedSeries(t *testing.T) {
 			getSeriesBaseItems: func() []jellyfinAPI.BaseItemDto {
 				baseItems := getSeriesBaseItems()
 				baseItems[getBaseItemIndexByID("1813f4b17e9d4a799641c09319b5ffcc")].ProviderIds = map[string]string{
-					"imdb": "1726",
+					"Imdb": "1726",
 				}
 				return baseItems
 			},
 			getExpectedResultFromBaseItem: func() []NewlyAddedSeriesItem {
 				expected := getExpectedResultFromBaseItem()
 				expected[getExpectedSeriesItemIndexByID("1813f4b17e9d4a799641c09319b5ffcc")].TMDBId = ""
+				expected[getExpectedSeriesItemIndexByID("1813f4b17e9d4a799641c09319b5ffcc")].IMDbId = "1726"
 				return expected
 			},
 		},
 		{
 			name: "Orphelin season",
+			// Season 1 is older than the observed period, so it isn't fetched
 			loggedMessages: []observer.LoggedEntry{
-				{
-					Entry: zapcore.Entry{
-						Level:   zapcore.WarnLevel,
-						Message: "A season item is ignored because it belongs to a Series that doesn't exist in Jellyfin's API response.",
-					},
-					Context: []zapcore.Field{
-						zap.String("Season ID", "bb2222-s1"),
-						zap.String("Season Name", "Season 1"),
-						zap.String("Not found Series Name", "Old Series 1"),
-						zap.String("Not found Series ID", "bb2222"),
-					},
-				},
 				{
 					Entry: zapcore.Entry{
 						Level:   zapcore.WarnLevel,
@@ -917,3 +922,56 @@ func TestGetNewlyAddedSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingParentIDs(t *testing.T) {
+	items := []jellyfinAPI.BaseItemDto{
+		{
+			Id:   new("new-series"),
+			Type: new(jellyfinAPI.BASEITEMKIND_SERIES),
+		},
+		{
+			Id:       new("new-series-s1"),
+			Type:     new(jellyfinAPI.BASEITEMKIND_SEASON),
+			SeriesId: *jellyfinAPI.NewNullableString(new("new-series")),
+		},
+		{
+			Id:       new("old-series-s2-e1"),
+			Type:     new(jellyfinAPI.BASEITEMKIND_EPISODE),
+			SeriesId: *jellyfinAPI.NewNullableString(new("old-series")),
+			SeasonId: *jellyfinAPI.NewNullableString(new("old-series-s2")),
+		},
+		{
+			Id:       new("old-series-s2-e2"),
+			Type:     new(jellyfinAPI.BASEITEMKIND_EPISODE),
+			SeriesId: *jellyfinAPI.NewNullableString(new("old-series")),
+			SeasonId: *jellyfinAPI.NewNullableString(new("old-series-s2")),
+		},
+		{
+			Id:   new("episode-without-parents"),
+			Type: new(jellyfinAPI.BASEITEMKIND_EPISODE),
+		},
+	}
+
+	assert.Equal(t, []string{"old-series", "old-series-s2"}, missingParentIDs(&items))
+}
+
+func TestParseSeriesItemsWithJellyfinMetadata(t *testing.T) {
+	app, _ := testSeriesInitApp()
+	app.Config.Jellyfin.URL = "http://jellyfin:8096"
+	items := []jellyfinAPI.BaseItemDto{
+		{
+			Id:          new("series"),
+			Type:        new(jellyfinAPI.BASEITEMKIND_SERIES),
+			Name:        *jellyfinAPI.NewNullableString(new("Series")),
+			DateCreated: *jellyfinAPI.NewNullableTime(new(time.Now())),
+			Overview:    *jellyfinAPI.NewNullableString(new("Overview of the series")),
+			ImageTags:   map[string]string{"Primary": "p1"},
+		},
+	}
+
+	seriesItems := parseSeriesItems(app, &items)
+
+	require.Contains(t, seriesItems, "series")
+	assert.Equal(t, "Overview of the series", seriesItems["series"].Overview)
+	assert.Equal(t, "http://jellyfin:8096/Items/series/Images/Primary?maxWidth=400&tag=p1", seriesItems["series"].PosterURL)
+}
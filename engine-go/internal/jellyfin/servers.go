@@ -0,0 +1,132 @@
+package jellyfin
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+)
+
+// The newsletter can aggregate several Jellyfin servers. The items of each server are gathered separately,
+// then merged: the first server wins when the same movie or series is found on several servers. The new seasons
+// of a series are gathered from all the servers.
+
+// DeduplicateMovies returns the movies without the ones whose TMDB id has already been seen, keeping the first one.
+// Movies without TMDB id are all kept.
+func DeduplicateMovies(movies *[]MovieItem) *[]MovieItem {
+	items := []MovieItem{}
+	seenTMDBIds := map[string]bool{}
+	for _, movie := range *movies {
+		if movie.TMDBId != "" {
+			if seenTMDBIds[movie.TMDBId] {
+				continue
+			}
+			seenTMDBIds[movie.TMDBId] = true
+		}
+		items = append(items, movie)
+	}
+	return &items
+}
+
+// DeduplicateSeries returns the series without the ones whose TMDB id has already been seen, keeping the first one.
+// The new seasons of the discarded series are merged into the kept one, so a season added on a single server
+// is still announced. Series without TMDB id are all kept.
+func DeduplicateSeries(series *[]NewlyAddedSeriesItem) *[]NewlyAddedSeriesItem {
+	items := []NewlyAddedSeriesItem{}
+	keptIndexes := map[string]int{} // Index in items, by TMDB id
+	for _, seriesItem := range *series {
+		if seriesItem.TMDBId != "" {
+			if keptIndex, seen := keptIndexes[seriesItem.TMDBId]; seen {
+				keptItem := &items[keptIndex]
+				if !keptItem.IsSeriesNew {
+					// A whole new series is announced as a whole, its seasons are not listed
+					keptItem.NewSeasons = mergeNewSeasons(keptItem.NewSeasons, seriesItem.NewSeasons)
+				}
+				continue
+			}
+			keptIndexes[seriesItem.TMDBId] = len(items)
+		}
+		items = append(items, seriesItem)
+	}
+	return &items
+}
+
+// mergeNewSeasons returns the seasons of keptSeasons, completed with the seasons of otherSeasons whose number is
+// missing. The new episodes of a season found in both are merged the same way, by episode number.
+// The maps given are not modified.
+func mergeNewSeasons(keptSeasons map[string]SeasonItem, otherSeasons map[string]SeasonItem) map[string]SeasonItem {
+	mergedSeasons := maps.Clone(keptSeasons)
+	for otherSeasonID, otherSeason := range otherSeasons {
+		keptSeasonID, found := findSeasonByNumber(mergedSeasons, otherSeason.SeasonNumber)
+		if !found {
+			if mergedSeasons == nil {
+				mergedSeasons = map[string]SeasonItem{}
+			}
+			mergedSeasons[otherSeasonID] = otherSeason
+			continue
+		}
+		keptSeason := mergedSeasons[keptSeasonID]
+		if keptSeason.IsSeasonNew {
+			// The whole season is already announced
+			continue
+		}
+		keptSeason.Episodes = maps.Clone(keptSeason.Episodes)
+		if keptSeason.Episodes == nil {
+			keptSeason.Episodes = map[string]EpisodeItem{}
+		}
+		for otherEpisodeID, otherEpisode := range otherSeason.Episodes {
+			isKnown := slices.ContainsFunc(
+				slices.Collect(maps.Values(keptSeason.Episodes)),
+				func(episode EpisodeItem) bool { return episode.EpisodeNumber == otherEpisode.EpisodeNumber },
+			)
+			if !isKnown {
+				keptSeason.Episodes[otherEpisodeID] = otherEpisode
+			}
+		}
+		mergedSeasons[keptSeasonID] = keptSeason
+	}
+	return mergedSeasons
+}
+
+func findSeasonByNumber(seasons map[string]SeasonItem, seasonNumber int32) (string, bool) {
+	for seasonID, season := range seasons {
+		if season.SeasonNumber == seasonNumber {
+			return seasonID, true
+		}
+	}
+	return "", false
+}
+
+// FilterRemovedItemsInLibraries returns the removed items that are in none of the library snapshots,
+// so an item removed from a Jellyfin server but still on another one is not announced as removed.
+// Items are matched by type, and by TMDB id or name (see FindRemovedItems).
+func FilterRemovedItemsInLibraries(
+	removedItems *[]RemovedItem,
+	snapshots []persistentdata.LibrarySnapshot,
+) *[]RemovedItem {
+	libraries := newLibraryContent(snapshots...)
+	items := []RemovedItem{}
+	for _, item := range *removedItems {
+		if !libraries.contains(item) {
+			items = append(items, item)
+		}
+	}
+	return &items
+}
+
+// DeduplicateRemovedItems returns the removed items without duplicates of the same type, name and production year.
+func DeduplicateRemovedItems(removedItems *[]RemovedItem) *[]RemovedItem {
+	items := []RemovedItem{}
+	seenItems := map[string]bool{}
+	for _, item := range *removedItems {
+		key := fmt.Sprintf("%s/%s/%d", item.ItemType, strings.ToLower(item.Name), item.ProductionYear)
+		if seenItems[key] {
+			continue
+		}
+		seenItems[key] = true
+		items = append(items, item)
+	}
+	return &items
+}
@@ -0,0 +1,150 @@
+package jellyfin
+
+import (
+	"slices"
+	"testing"
+
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicateMovies(t *testing.T) {
+	movies := []MovieItem{
+		{ID: "home-matrix", TMDBId: "603"},
+		{ID: "home-untagged"},
+		{ID: "cabin-matrix", TMDBId: "603", ServerURL: "https://cabin.example.com"},
+		{ID: "cabin-untagged", ServerURL: "https://cabin.example.com"},
+	}
+
+	assert.Equal(t, []MovieItem{
+		{ID: "home-matrix", TMDBId: "603"},
+		{ID: "home-untagged"},
+		{ID: "cabin-untagged", ServerURL: "https://cabin.example.com"},
+	}, *DeduplicateMovies(&movies))
+}
+
+func TestDeduplicateSeries(t *testing.T) {
+	series := []NewlyAddedSeriesItem{
+		{SeriesID: "home-got", TMDBId: "1399"},
+		{SeriesID: "cabin-got", TMDBId: "1399"},
+		{SeriesID: "cabin-untagged"},
+	}
+
+	assert.Equal(
+		t,
+		[]NewlyAddedSeriesItem{{SeriesID: "home-got", TMDBId: "1399"}, {SeriesID: "cabin-untagged"}},
+		*DeduplicateSeries(&series),
+	)
+}
+
+func TestDeduplicateSeries_MergesNewSeasons(t *testing.T) {
+	series := []NewlyAddedSeriesItem{
+		{
+			SeriesID: "home-got",
+			TMDBId:   "1399",
+			NewSeasons: map[string]SeasonItem{
+				"home-s2": {
+					SeasonNumber: 2,
+					Episodes:     map[string]EpisodeItem{"home-s2e1": {EpisodeNumber: 1}},
+				},
+				"home-s3": {SeasonNumber: 3, IsSeasonNew: true},
+			},
+		},
+		{
+			SeriesID: "cabin-got",
+			TMDBId:   "1399",
+			NewSeasons: map[string]SeasonItem{
+				"cabin-s2": {
+					SeasonNumber: 2,
+					Episodes: map[string]EpisodeItem{
+						"cabin-s2e1": {EpisodeNumber: 1},
+						"cabin-s2e2": {EpisodeNumber: 2},
+					},
+				},
+				"cabin-s3": {SeasonNumber: 3, IsSeasonNew: true},
+				"cabin-s4": {SeasonNumber: 4, IsSeasonNew: true},
+			},
+		},
+	}
+
+	deduplicatedSeries := *DeduplicateSeries(&series)
+
+	assert.Equal(t, []NewlyAddedSeriesItem{{
+		SeriesID: "home-got",
+		TMDBId:   "1399",
+		NewSeasons: map[string]SeasonItem{
+			"home-s2": {
+				SeasonNumber: 2,
+				Episodes: map[string]EpisodeItem{
+					"home-s2e1":  {EpisodeNumber: 1},
+					"cabin-s2e2": {EpisodeNumber: 2},
+				},
+			},
+			"home-s3":  {SeasonNumber: 3, IsSeasonNew: true},
+			"cabin-s4": {SeasonNumber: 4, IsSeasonNew: true},
+		},
+	}}, deduplicatedSeries)
+	assert.Len(t, series[0].NewSeasons, 2, "the series given are not modified")
+	assert.Len(t, series[0].NewSeasons["home-s2"].Episodes, 1, "the series given are not modified")
+}
+
+func TestDeduplicateRemovedItems(t *testing.T) {
+	removedItems := []RemovedItem{
+		{Name: "The Matrix", ItemType: "Movie", ProductionYear: 1999},
+		{Name: "the matrix", ItemType: "Movie", ProductionYear: 1999},
+		{Name: "The Matrix", ItemType: "Movie", ProductionYear: 2021},
+		{Name: "The Matrix", ItemType: "Series", ProductionYear: 1999},
+	}
+
+	assert.Equal(t, []RemovedItem{
+		{Name: "The Matrix", ItemType: "Movie", ProductionYear: 1999},
+		{Name: "The Matrix", ItemType: "Movie", ProductionYear: 2021},
+		{Name: "The Matrix", ItemType: "Series", ProductionYear: 1999},
+	}, *DeduplicateRemovedItems(&removedItems))
+}
+
+func TestFilterRemovedItemsInLibraries(t *testing.T) {
+	// Dune is moved from the home server to the cabin server, Oppenheimer is deleted from both
+	homePreviousSnapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"home dune":        {Name: "Dune", ItemType: "Movie", TMDBId: "438631", Folder: "Movies"},
+			"home oppenheimer": {Name: "Oppenheimer", ItemType: "Movie", TMDBId: "872585", Folder: "Movies"},
+		},
+	}
+	homeSnapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Movies"},
+		Items:   map[string]persistentdata.LibrarySnapshotItem{},
+	}
+	cabinPreviousSnapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Films"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			"cabin oppenheimer": {Name: "Oppenheimer", ItemType: "Movie", Folder: "Films"},
+			"cabin fargo":       {Name: "Fargo", ItemType: "Series", Folder: "Films"},
+		},
+	}
+	cabinSnapshot := persistentdata.LibrarySnapshot{
+		Folders: []string{"Films"},
+		Items: map[string]persistentdata.LibrarySnapshotItem{
+			// Found by its TMDB id, although the cabin server names it differently
+			"cabin dune": {Name: "Dune: Part One", ItemType: "Movie", TMDBId: "438631", Folder: "Films"},
+			// A movie with the name of the removed series doesn't hide it
+			"cabin fargo movie": {Name: "fargo", ItemType: "Movie", Folder: "Films"},
+		},
+	}
+	removedItems := slices.Concat(
+		*FindRemovedItems(homePreviousSnapshot, homeSnapshot),
+		*FindRemovedItems(cabinPreviousSnapshot, cabinSnapshot),
+	)
+
+	filteredItems := FilterRemovedItemsInLibraries(
+		&removedItems,
+		[]persistentdata.LibrarySnapshot{homeSnapshot, cabinSnapshot},
+	)
+
+	assert.Equal(t, []RemovedItem{
+		{Name: "Oppenheimer", ItemType: "Movie", TMDBId: "872585"},
+		{Name: "Fargo", ItemType: "Series"},
+		{Name: "Oppenheimer", ItemType: "Movie"},
+	}, *filteredItems)
+}
@@ -0,0 +1,63 @@
+package jellyfin
+
+import (
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+)
+
+// When a movie file is replaced by a better version (e.g. 1080p by 4K HDR), Jellyfin may reset its creation date,
+// so the movie looks newly added. To tell them apart, the quality of every movie is saved at each run
+// (see GetMoviesQualityRecords), and compared with the quality of the recently added movies in the next run.
+
+// GetMoviesQualityRecords returns the quality of the movies, by Jellyfin item ID, to be saved for the next run.
+func GetMoviesQualityRecords(movies *[]MovieItem) map[string]persistentdata.MovieQualityRecord {
+	records := map[string]persistentdata.MovieQualityRecord{}
+	for _, movie := range *movies {
+		records[movie.ID] = persistentdata.MovieQualityRecord{
+			Name:       movie.Name,
+			TMDBId:     movie.TMDBId,
+			Resolution: movie.Quality.Resolution,
+			VideoRange: movie.Quality.VideoRange,
+			Codec:      movie.Quality.Codec,
+		}
+	}
+	return records
+}
+
+// SplitUpgradedMovies separates the recently added movies known by the previous run with a lower quality
+// (the upgraded movies, with their PreviousQuality set) from the genuinely new ones.
+// previousMoviesQuality must only contain the records of the Jellyfin server and user of the movies.
+// A movie is matched by its Jellyfin item ID, or by its TMDB id, because the item ID changes when the file is renamed.
+// Without known quality, in this run or the previous one, a movie is considered new.
+func SplitUpgradedMovies(
+	recentlyAddedMovies *[]MovieItem,
+	previousMoviesQuality map[string]persistentdata.MovieQualityRecord,
+) (*[]MovieItem, *[]MovieItem) {
+	previousQualityByTMDBId := map[string]persistentdata.MovieQualityRecord{}
+	for _, record := range previousMoviesQuality {
+		if record.TMDBId != "" {
+			previousQualityByTMDBId[record.TMDBId] = record
+		}
+	}
+
+	newMovies := []MovieItem{}
+	upgradedMovies := []MovieItem{}
+	for _, movie := range *recentlyAddedMovies {
+		record, isKnown := previousMoviesQuality[movie.ID]
+		if !isKnown && movie.TMDBId != "" {
+			record, isKnown = previousQualityByTMDBId[movie.TMDBId]
+		}
+		previousQuality := MediaQuality{
+			Resolution: record.Resolution,
+			VideoRange: record.VideoRange,
+			Codec:      record.Codec,
+		}
+		if !isKnown || !previousQuality.IsKnown() || !movie.Quality.IsKnown() ||
+			!movie.Quality.IsBetterThan(previousQuality) {
+			newMovies = append(newMovies, movie)
+			continue
+		}
+		movie.PreviousQuality = previousQuality
+		upgradedMovies = append(upgradedMovies, movie)
+	}
+	return &newMovies, &upgradedMovies
+}
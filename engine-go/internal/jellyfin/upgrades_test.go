@@ -0,0 +1,150 @@
+package jellyfin
+
+import (
+	"testing"
+
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMediaQuality(t *testing.T) {
+	item := jellyfinAPI.BaseItemDto{
+		MediaStreams: []jellyfinAPI.MediaStream{
+			{
+				Type:  new(jellyfinAPI.MEDIASTREAMTYPE_AUDIO),
+				Codec: *jellyfinAPI.NewNullableString(new("eac3")),
+			},
+			{
+				Type:           new(jellyfinAPI.MEDIASTREAMTYPE_VIDEO),
+				Codec:          *jellyfinAPI.NewNullableString(new("hevc")),
+				Width:          *jellyfinAPI.NewNullableInt32(new(int32(3840))),
+				Height:         *jellyfinAPI.NewNullableInt32(new(int32(1600))),
+				VideoRangeType: new(jellyfinAPI.VIDEORANGETYPE_HDR10),
+			},
+		},
+	}
+
+	quality := getMediaQuality(&item)
+
+	assert.Equal(t, MediaQuality{Resolution: "4K", VideoRange: "HDR10", Codec: "HEVC"}, quality)
+	assert.Equal(t, "4K HDR10 HEVC", quality.String())
+	assert.True(t, quality.IsKnown())
+}
+
+func TestGetMediaQualityWithoutVideoStream(t *testing.T) {
+	quality := getMediaQuality(&jellyfinAPI.BaseItemDto{})
+
+	assert.Equal(t, MediaQuality{}, quality)
+	assert.False(t, quality.IsKnown())
+}
+
+func TestGetResolutionLabel(t *testing.T) {
+	tests := []struct {
+		width    int32
+		height   int32
+		expected string
+	}{
+		{3840, 2160, "4K"},
+		{2560, 1440, "1440p"},
+		{1920, 1080, "1080p"},
+		{1920, 800, "1080p"},
+		{1280, 720, "720p"},
+		{720, 576, "SD"},
+		{0, 0, ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getResolutionLabel(test.width, test.height))
+	}
+}
+
+func TestMediaQualityString(t *testing.T) {
+	assert.Equal(t, "1080p H264", MediaQuality{Resolution: "1080p", VideoRange: "SDR", Codec: "H264"}.String())
+	assert.Equal(t, "DOVI", MediaQuality{VideoRange: "DOVI"}.String())
+	assert.Empty(t, MediaQuality{}.String())
+}
+
+func TestGetMoviesQualityRecords(t *testing.T) {
+	movies := []MovieItem{
+		{
+			ID:      "7dcf7149f71046d5a50c626e3486259b",
+			Name:    "Movie 1",
+			TMDBId:  "2876",
+			Quality: MediaQuality{Resolution: "1080p", VideoRange: "SDR", Codec: "H264"},
+		},
+	}
+
+	records := GetMoviesQualityRecords(&movies)
+
+	assert.Equal(t, map[string]persistentdata.MovieQualityRecord{
+		"7dcf7149f71046d5a50c626e3486259b": {
+			Name:       "Movie 1",
+			TMDBId:     "2876",
+			Resolution: "1080p",
+			VideoRange: "SDR",
+			Codec:      "H264",
+		},
+	}, records)
+}
+
+func TestSplitUpgradedMovies(t *testing.T) {
+	uhd := MediaQuality{Resolution: "4K", VideoRange: "HDR10", Codec: "HEVC"}
+	fullHD := persistentdata.MovieQualityRecord{Resolution: "1080p", VideoRange: "SDR", Codec: "H264"}
+	previousMoviesQuality := map[string]persistentdata.MovieQualityRecord{
+		"upgraded":       fullHD,
+		"unchanged":      {Resolution: "4K", VideoRange: "HDR10", Codec: "HEVC"},
+		"unknownQuality": {},
+		"renamedFile":    {TMDBId: "157336", Resolution: "1080p", VideoRange: "SDR", Codec: "H264"},
+		"downgraded":     {Resolution: "4K", VideoRange: "DOVI", Codec: "HEVC"},
+	}
+	recentlyAddedMovies := []MovieItem{
+		{ID: "upgraded", Name: "Upgraded", Quality: uhd},
+		{ID: "unchanged", Name: "Unchanged", Quality: uhd},
+		{ID: "unknownQuality", Name: "Unknown previous quality", Quality: uhd},
+		{ID: "notAnalyzed", Name: "Not analyzed yet"},
+		{ID: "new", Name: "New", TMDBId: "27205", Quality: uhd},
+		{ID: "newID", Name: "Renamed file", TMDBId: "157336", Quality: uhd},
+		{ID: "downgraded", Name: "Downgraded", Quality: uhd},
+	}
+
+	newMovies, upgradedMovies := SplitUpgradedMovies(&recentlyAddedMovies, previousMoviesQuality)
+
+	newMovieNames := []string{}
+	for _, movie := range *newMovies {
+		newMovieNames = append(newMovieNames, movie.Name)
+	}
+	assert.Equal(
+		t,
+		[]string{"Unchanged", "Unknown previous quality", "Not analyzed yet", "New", "Downgraded"},
+		newMovieNames,
+	)
+	assert.Len(t, *upgradedMovies, 2)
+	assert.Equal(t, "Upgraded", (*upgradedMovies)[0].Name)
+	assert.Equal(t, "Renamed file", (*upgradedMovies)[1].Name)
+	for _, movie := range *upgradedMovies {
+		assert.Equal(t, MediaQuality{Resolution: "1080p", VideoRange: "SDR", Codec: "H264"}, movie.PreviousQuality)
+		assert.Equal(t, uhd, movie.Quality)
+	}
+}
+
+func TestMediaQualityIsBetterThan(t *testing.T) {
+	uhd := MediaQuality{Resolution: "4K", VideoRange: "HDR10", Codec: "HEVC"}
+
+	assert.True(t, uhd.IsBetterThan(MediaQuality{Resolution: "1080p", VideoRange: "DOVI", Codec: "AV1"}))
+	assert.True(t, MediaQuality{Resolution: "4K", VideoRange: "DOVIWithHDR10", Codec: "H264"}.IsBetterThan(uhd))
+	assert.True(t, MediaQuality{Resolution: "4K", VideoRange: "HDR10", Codec: "AV1"}.IsBetterThan(uhd))
+	assert.False(t, uhd.IsBetterThan(uhd))
+	assert.False(t, MediaQuality{Resolution: "1080p", VideoRange: "SDR", Codec: "H264"}.IsBetterThan(uhd))
+	assert.False(t, MediaQuality{Resolution: "4K", Codec: "HEVC"}.IsBetterThan(uhd))
+}
+
+func TestSplitUpgradedMoviesWithoutPreviousRun(t *testing.T) {
+	recentlyAddedMovies := []MovieItem{
+		{ID: "new", Name: "New", Quality: MediaQuality{Resolution: "4K"}},
+	}
+
+	newMovies, upgradedMovies := SplitUpgradedMovies(&recentlyAddedMovies, map[string]persistentdata.MovieQualityRecord{})
+
+	assert.Equal(t, recentlyAddedMovies, *newMovies)
+	assert.Empty(t, *upgradedMovies)
+}
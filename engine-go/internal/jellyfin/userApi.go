@@ -0,0 +1,38 @@
+package jellyfin
+
+import (
+	"context"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+)
+
+// User is a Jellyfin user account.
+type User struct {
+	ID   string
+	Name string
+}
+
+type jellyfinUserAPI struct {
+	jellyfinAPI.UserAPI
+}
+
+func (userAPI jellyfinUserAPI) GetUsers(app *app.ApplicationContext) ([]User, error) {
+	usersDto, httpResponse, httpErr := userAPI.UserAPI.GetUsers(context.Background()).Execute()
+
+	err := checkHTTPRequest("GetUsers", httpResponse, httpErr, app.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResponse.Body.Close()
+
+	users := make([]User, 0, len(usersDto))
+	for _, userDto := range usersDto {
+		users = append(users, User{
+			ID:   userDto.GetId(),
+			Name: userDto.GetName(),
+		})
+	}
+	return users, nil
+}
@@ -0,0 +1,37 @@
+package jellyfin
+
+import (
+	"strings"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"go.uber.org/zap"
+)
+
+// GetUserID resolves a Jellyfin user, designated by its name or its ID, into its ID.
+// User names are compared case-insensitively, like Jellyfin does at login.
+func (client *APIClient) GetUserID(selector string, app *app.ApplicationContext) (string, error) {
+	if client.UserAPI == nil {
+		return "", ErrUserNotFound
+	}
+	users, err := client.UserAPI.GetUsers(app)
+	if err != nil {
+		return "", err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Name, selector) || normalizeJellyfinID(user.ID) == normalizeJellyfinID(selector) {
+			return user.ID, nil
+		}
+	}
+
+	availableUsers := make([]string, 0, len(users))
+	for _, user := range users {
+		availableUsers = append(availableUsers, user.Name)
+	}
+	app.Logger.Error(
+		"Jellyfin user not found. The user must be the name or the ID of a Jellyfin user.",
+		zap.String("user", selector),
+		zap.Strings("available users", availableUsers),
+	)
+	return "", ErrUserNotFound
+}
@@ -0,0 +1,71 @@
+package jellyfin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserID(t *testing.T) {
+	users := []User{
+		{ID: "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c", Name: "Kid"},
+		{ID: "9a8b7c6d5e4f40318f2e1d0c9b8a7f6e", Name: "admin"},
+	}
+	tests := []struct {
+		name       string
+		selector   string
+		expectedID string
+	}{
+		{name: "By name", selector: "Kid", expectedID: "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c"},
+		{name: "By name, case insensitive", selector: "kid", expectedID: "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c"},
+		{name: "By ID", selector: "9a8b7c6d5e4f40318f2e1d0c9b8a7f6e", expectedID: "9a8b7c6d5e4f40318f2e1d0c9b8a7f6e"},
+		{
+			name:       "By ID with dashes",
+			selector:   "9A8B7C6D-5E4F-4031-8F2E-1D0C9B8A7F6E",
+			expectedID: "9a8b7c6d5e4f40318f2e1d0c9b8a7f6e",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app, recordedLogs := initApp()
+			client := APIClient{UserAPI: MockJellyfinUserAPI{
+				ExecuteGetUsers: func() ([]User, error) { return users, nil },
+			}}
+
+			userID, err := client.GetUserID(test.selector, app)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedID, userID)
+			assert.Empty(t, recordedLogs.All())
+		})
+	}
+}
+
+func TestGetUserIDWithUnknownUser(t *testing.T) {
+	app, recordedLogs := initApp()
+	client := APIClient{UserAPI: MockJellyfinUserAPI{
+		ExecuteGetUsers: func() ([]User, error) {
+			return []User{{ID: "4f1b0c3e2a9d4e8f9b7c6d5e4f3a2b1c", Name: "Kid"}}, nil
+		},
+	}}
+
+	_, err := client.GetUserID("unknown", app)
+
+	require.ErrorIs(t, err, ErrUserNotFound)
+	require.Len(t, recordedLogs.All(), 1)
+	assert.Equal(t, []any{"Kid"}, recordedLogs.All()[0].ContextMap()["available users"])
+}
+
+func TestGetUserIDWithAPIError(t *testing.T) {
+	app, _ := initApp()
+	apiErr := errors.New("connection refused")
+	client := APIClient{UserAPI: MockJellyfinUserAPI{
+		ExecuteGetUsers: func() ([]User, error) { return nil, apiErr },
+	}}
+
+	_, err := client.GetUserID("Kid", app)
+
+	assert.ErrorIs(t, err, apiErr)
+}
@@ -1,52 +1,567 @@
 package newsletter
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/dryrun"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
 	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/smtp"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/template"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/tmdb"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
 	"go.uber.org/zap"
 )
 
 type Workflow struct {
 	JellyfinClient jellyfin.APIClient
-	TMDBClient     tmdb.APIClient
+	// AdditionalJellyfinClients are the clients of the additional Jellyfin servers
+	// (see config.Configuration.AdditionalJellyfinServers), in the same order.
+	AdditionalJellyfinClients []jellyfin.APIClient
+	TMDBClient                tmdb.APIInterface
+}
+
+// runState is the data shared by the newsletters of a run: the movies queued by the webhook listener,
+// and the data saved by the previous run, with the data of this run to save for the next one.
+type runState struct {
+	queuedMovieIDs           []string
+	previousMoviesQuality    map[string]map[string]persistentdata.MovieQualityRecord
+	moviesQuality            map[string]map[string]persistentdata.MovieQualityRecord
+	previousLibrarySnapshots map[string]persistentdata.LibrarySnapshot
+	librarySnapshots         map[string]persistentdata.LibrarySnapshot
+	previousAnnouncedItems   map[string][]string
+	announcedItems           map[string][]string
+}
+
+// jellyfinServer is a Jellyfin server aggregated in a newsletter, with the context of the newsletter on this server.
+type jellyfinServer struct {
+	client jellyfin.APIClient
+	app    *app.ApplicationContext
 }
 
 // Run connects to Jellyfin to retrieve the latest items and send the newsletter to the configured recipients.
+// Recipients mapped to a Jellyfin user receive a newsletter built for this user (see app.JellyfinUserContexts).
+// The items of all the reachable Jellyfin servers are aggregated in the newsletter (see app.JellyfinServerContexts).
 // cronjob is optional and should be nil if the workflow is not called by a scheduled job. It is mainly used for logging purposes.
 func (workflow Workflow) Run(app *app.ApplicationContext) {
 	app.Logger.Info("Gathering new items and sending the newsletter ...")
-	err := workflow.JellyfinClient.TestConnection(app)
+	reachableServers := workflow.testJellyfinConnections(app)
+
+	previousMoviesQuality, err := persistentdata.GetMoviesQuality(app)
 	if err != nil {
-		app.Logger.Fatal(
-			"Jellyfin newsletter startup failed. An error occurred while connecting to Jellyfin.",
+		app.Logger.Warn(
+			"An error occured while reading the movies quality of the previous run. Upgraded movies will be announced as new.",
 			zap.Error(err),
 		)
+		previousMoviesQuality = map[string]map[string]persistentdata.MovieQualityRecord{}
 	}
 
-	recentlyAddedMovies := workflow.JellyfinClient.GetRecentlyAddedMovies(app)
-	recentlyAddedSeries := workflow.JellyfinClient.GetNewlyAddedSeries(app)
+	previousLibrarySnapshots := readPreviousLibrarySnapshots(app)
+	previousAnnouncedItems := readPreviousAnnouncedItems(app)
+	queuedEvents, isWebhookQueueRead := readWebhookQueue(app)
+	state := &runState{
+		queuedMovieIDs:        queuedMovieIDs(queuedEvents),
+		previousMoviesQuality: previousMoviesQuality,
+		// Movies quality of the users whose newsletter is not built in this run is kept for the next one
+		moviesQuality:            maps.Clone(previousMoviesQuality),
+		previousLibrarySnapshots: previousLibrarySnapshots,
+		// Snapshots of the users whose newsletter is not built in this run are kept for the next one
+		librarySnapshots:       maps.Clone(previousLibrarySnapshots),
+		previousAnnouncedItems: previousAnnouncedItems,
+		// Announced items of the users whose newsletter is not built in this run are kept for the next one
+		announcedItems: maps.Clone(previousAnnouncedItems),
+	}
 
-	if len(*recentlyAddedMovies) == 0 && len(*recentlyAddedSeries) == 0 {
-		app.Logger.Info("No new items detected. Email notification is skipped.")
+	isNewsletterSent := false
+	for _, userApp := range app.JellyfinUserContexts() {
+		servers := workflow.jellyfinServers(userApp, reachableServers)
+		if len(servers) == 0 {
+			userApp.Logger.Error(
+				"Impossible to build the newsletter of the Jellyfin user. Its recipients won't receive it.",
+				zap.Strings("recipients", userApp.Config.EmailRecipients),
+			)
+			continue
+		}
+		if workflow.sendNewsletter(userApp, servers, state) {
+			isNewsletterSent = true
+		}
+	}
+
+	err = persistentdata.UpdateMoviesQuality(state.moviesQuality, app)
+	if err != nil {
+		app.Logger.Warn(
+			"An error occured while saving the movies quality. Upgraded movies could be announced as new in the next newsletter.",
+			zap.Error(err),
+		)
+	}
+
+	if isEnabledOnAnyServer(app, isRemovedMediaAnnounced) {
+		err = persistentdata.UpdateLibrarySnapshots(state.librarySnapshots, app)
+		if err != nil {
+			app.Logger.Warn(
+				"An error occured while saving the library snapshot. Removed media won't be detected in the next newsletter.",
+				zap.Error(err),
+			)
+		}
+	}
+
+	if isEnabledOnAnyServer(app, isSnapshotDetectionUsed) {
+		err = persistentdata.UpdateAnnouncedItems(state.announcedItems, app)
+		if err != nil {
+			app.Logger.Warn(
+				"An error occured while saving the announced items. They could be announced again in the next newsletter.",
+				zap.Error(err),
+			)
+		}
+	}
+
+	workflow.saveTMDBCache(app)
+
+	if isWebhookQueueRead {
+		err = persistentdata.RemoveWebhookEvents(queuedEvents, app)
+		if err != nil {
+			app.Logger.Warn(
+				"An error occured while removing the webhook events of this run. They could be announced again.",
+				zap.Error(err),
+			)
+		}
+	}
+
+	if !isNewsletterSent {
 		return
 	}
 
-	tmdb.EnrichMovieItemsList(recentlyAddedMovies, workflow.TMDBClient, app)
-	tmdb.EnrichSeriesItemsList(recentlyAddedSeries, workflow.TMDBClient, app)
+	err = persistentdata.UpdateLastNewsletterDatetime(app.Clock.Now().UTC(), app)
+	if err != nil {
+		app.Logger.Warn(
+			"An error occured while saving the last newsletter datetime. This could lead to future error or items sent again.",
+			zap.Error(err),
+		)
+	}
+
+	app.Logger.Info("Thanks for using Jellyfin-Newsletter !")
+}
+
+// jellyfinClients returns the clients of the Jellyfin servers, in the order of app.JellyfinServerContexts.
+func (workflow Workflow) jellyfinClients() []jellyfin.APIClient {
+	return append([]jellyfin.APIClient{workflow.JellyfinClient}, workflow.AdditionalJellyfinClients...)
+}
+
+// testJellyfinConnections returns whether each Jellyfin server is reachable, in the order of jellyfinClients.
+// Unreachable servers are left out of the newsletter. The run fails if no server is reachable.
+func (workflow Workflow) testJellyfinConnections(app *app.ApplicationContext) []bool {
+	clients := workflow.jellyfinClients()
+	serverContexts := app.JellyfinServerContexts()
+	reachableServers := make([]bool, min(len(clients), len(serverContexts)))
+	isAnyServerReachable := false
+	for i := range reachableServers {
+		err := clients[i].TestConnection(serverContexts[i])
+		if err != nil {
+			if len(reachableServers) == 1 {
+				app.Logger.Fatal(
+					"Jellyfin newsletter startup failed. An error occurred while connecting to Jellyfin.",
+					zap.Error(err),
+				)
+			}
+			serverContexts[i].Logger.Error(
+				"An error occurred while connecting to the Jellyfin server. Its items are left out of the newsletter.",
+				zap.Error(err),
+			)
+			continue
+		}
+		reachableServers[i] = true
+		isAnyServerReachable = true
+	}
+	if !isAnyServerReachable {
+		app.Logger.Fatal("Jellyfin newsletter startup failed. No Jellyfin server is reachable.")
+	}
+	return reachableServers
+}
+
+// jellyfinServers returns the reachable Jellyfin servers the newsletter of app is built from.
+// The ID of the Jellyfin user of the newsletter is resolved on each server. The servers where the user is not found
+// are left out.
+func (workflow Workflow) jellyfinServers(app *app.ApplicationContext, reachableServers []bool) []jellyfinServer {
+	clients := workflow.jellyfinClients()
+	servers := []jellyfinServer{}
+	for i, serverApp := range app.JellyfinServerContexts() {
+		if i >= len(reachableServers) || !reachableServers[i] {
+			continue
+		}
+		if serverApp.Config.Jellyfin.User != "" {
+			userID, err := clients[i].GetUserID(serverApp.Config.Jellyfin.User, serverApp)
+			if err != nil {
+				serverApp.Logger.Error(
+					"Impossible to find the Jellyfin user on the server. Its items are left out of the newsletter.",
+					zap.Error(err),
+				)
+				continue
+			}
+			serverApp.Config.Jellyfin.UserID = userID
+		}
+		servers = append(servers, jellyfinServer{client: clients[i], app: serverApp})
+	}
+	return servers
+}
+
+// isEnabledOnAnyServer reports whether isEnabled is true for one of the Jellyfin servers of app.
+func isEnabledOnAnyServer(app *app.ApplicationContext, isEnabled func(config.JellyfinConfig) bool) bool {
+	return isEnabled(app.Config.Jellyfin) || slices.ContainsFunc(app.Config.AdditionalJellyfinServers, isEnabled)
+}
+
+func isRemovedMediaAnnounced(server config.JellyfinConfig) bool {
+	return server.AnnounceRemovedMedia
+}
+
+func isSnapshotDetectionUsed(server config.JellyfinConfig) bool {
+	return server.NewItemDetection == config.NewItemDetectionSnapshot
+}
+
+// stateKey returns the key the data of the newsletter of app are saved with: the Jellyfin user, prefixed by the
+// name of the Jellyfin server when it is named, since each server has its own library.
+// The data of the newsletter without Jellyfin user are saved with an empty user.
+func stateKey(app *app.ApplicationContext) string {
+	if app.Config.Jellyfin.Name == "" {
+		return app.Config.Jellyfin.User
+	}
+	return app.Config.Jellyfin.Name + "/" + app.Config.Jellyfin.User
+}
+
+// readPreviousLibrarySnapshots returns the library snapshots saved by the previous run, by state key (see stateKey).
+// They are only read when removed media are announced.
+func readPreviousLibrarySnapshots(app *app.ApplicationContext) map[string]persistentdata.LibrarySnapshot {
+	if !isEnabledOnAnyServer(app, isRemovedMediaAnnounced) {
+		return map[string]persistentdata.LibrarySnapshot{}
+	}
+	snapshots, err := persistentdata.GetLibrarySnapshots(app)
+	if err != nil {
+		app.Logger.Warn(
+			"An error occured while reading the library snapshot of the previous run. Removed media won't be announced.",
+			zap.Error(err),
+		)
+		return map[string]persistentdata.LibrarySnapshot{}
+	}
+	return snapshots
+}
+
+// findRemovedMedia returns the movies and series removed since the previous run, with the library snapshot of
+// this run, also saved in librarySnapshots. Snapshots are saved by Jellyfin user and server (see stateKey),
+// because each user sees a different library. The snapshot is empty when removed media are not announced.
+func (workflow Workflow) findRemovedMedia(
+	app *app.ApplicationContext,
+	previousLibrarySnapshots map[string]persistentdata.LibrarySnapshot,
+	librarySnapshots map[string]persistentdata.LibrarySnapshot,
+) (*[]jellyfin.RemovedItem, persistentdata.LibrarySnapshot) {
+	if !app.Config.Jellyfin.AnnounceRemovedMedia {
+		return &[]jellyfin.RemovedItem{}, persistentdata.LibrarySnapshot{}
+	}
+	previousSnapshot := previousLibrarySnapshots[stateKey(app)]
+	snapshot := workflow.JellyfinClient.GetLibrarySnapshot(previousSnapshot, app)
+	librarySnapshots[stateKey(app)] = snapshot
+	return jellyfin.FindRemovedItems(previousSnapshot, snapshot), snapshot
+}
+
+// readPreviousAnnouncedItems returns the announcement keys saved by the previous runs, by state key (see stateKey).
+// They are only read by the snapshot detection of new items, see detectUnannouncedItems.
+func readPreviousAnnouncedItems(app *app.ApplicationContext) map[string][]string {
+	if !isEnabledOnAnyServer(app, isSnapshotDetectionUsed) {
+		return map[string][]string{}
+	}
+	announcedItems, err := persistentdata.GetAnnouncedItems(app)
+	if err != nil {
+		// Without announced items, the library is recorded again as on the first run, so nothing is announced
+		app.Logger.Warn(
+			"An error occured while reading the announced items. The library is recorded again, no item is announced.",
+			zap.Error(err),
+		)
+		return map[string][]string{}
+	}
+	return announcedItems
+}
+
+// detectedItems are the new items found by the detection mode, before the webhook queue and the exclusion rules.
+type detectedItems struct {
+	movies      *[]jellyfin.MovieItem
+	series      *[]jellyfin.NewlyAddedSeriesItem
+	musicAlbums *[]jellyfin.MusicAlbumItem
+	books       *[]jellyfin.BookItem
+}
 
-	moviesCount, episodesCount, err := workflow.JellyfinClient.LibraryAPI.GetItemsStats(app)
+// detectNewItems returns the items added within the observed period, or the items never announced with the
+// snapshot detection (see detectUnannouncedItems).
+func (workflow Workflow) detectNewItems(
+	app *app.ApplicationContext,
+	movies *[]jellyfin.MovieItem,
+	previousMoviesQuality map[string]persistentdata.MovieQualityRecord,
+	previousAnnouncedItems map[string][]string,
+	announcedItems map[string][]string,
+) detectedItems {
+	if app.Config.Jellyfin.NewItemDetection == config.NewItemDetectionSnapshot {
+		return workflow.detectUnannouncedItems(app, movies, previousMoviesQuality, previousAnnouncedItems, announcedItems)
+	}
+	return detectedItems{
+		movies:      jellyfin.FilterRecentlyAddedMovies(movies, app),
+		series:      workflow.JellyfinClient.GetNewlyAddedSeries(app),
+		musicAlbums: workflow.JellyfinClient.GetRecentlyAddedMusicAlbums(app),
+		books:       workflow.JellyfinClient.GetRecentlyAddedBooks(app),
+	}
+}
+
+// detectUnannouncedItems returns the items whose announcement key is not announced yet for the state key of app,
+// and saves the keys of the items seen in announcedItems. The movies whose quality improved since the previous run
+// are returned too, so upgrades are still detected (see jellyfin.SplitUpgradedMovies).
+// The first run of a user only records the keys, without detecting anything, so the library is not announced.
+func (workflow Workflow) detectUnannouncedItems(
+	app *app.ApplicationContext,
+	movies *[]jellyfin.MovieItem,
+	previousMoviesQuality map[string]persistentdata.MovieQualityRecord,
+	previousAnnouncedItems map[string][]string,
+	announcedItems map[string][]string,
+) detectedItems {
+	previousKeys, isSeeded := previousAnnouncedItems[stateKey(app)]
+	announcedKeys := map[string]bool{}
+	for _, key := range previousKeys {
+		announcedKeys[key] = true
+	}
+
+	series, seriesKeys := workflow.JellyfinClient.GetUnannouncedSeries(announcedKeys, app)
+	musicAlbums, musicAlbumKeys := workflow.JellyfinClient.GetUnannouncedMusicAlbums(announcedKeys, app)
+	books, bookKeys := workflow.JellyfinClient.GetUnannouncedBooks(announcedKeys, app)
+	// Keys of the previous runs are kept, so the items of a folder that cannot be read are not announced again
+	seenKeys := slices.Concat(
+		previousKeys,
+		jellyfin.GetMoviesAnnouncementKeys(movies),
+		seriesKeys,
+		musicAlbumKeys,
+		bookKeys,
+	)
+	slices.Sort(seenKeys)
+	announcedItems[stateKey(app)] = slices.Compact(seenKeys)
+
+	if !isSeeded {
+		app.Logger.Info(
+			"First run of the snapshot detection. The items of the library are recorded as announced, none is announced.",
+			zap.Int("items", len(announcedItems[stateKey(app)])),
+		)
+		return detectedItems{
+			movies:      &[]jellyfin.MovieItem{},
+			series:      &[]jellyfin.NewlyAddedSeriesItem{},
+			musicAlbums: &[]jellyfin.MusicAlbumItem{},
+			books:       &[]jellyfin.BookItem{},
+		}
+	}
+
+	_, upgradedMovies := jellyfin.SplitUpgradedMovies(movies, previousMoviesQuality)
+	return detectedItems{
+		movies:      jellyfin.MergeMovies(jellyfin.FilterUnannouncedMovies(movies, announcedKeys), upgradedMovies),
+		series:      series,
+		musicAlbums: musicAlbums,
+		books:       books,
+	}
+}
+
+// readWebhookQueue returns the events queued by the webhook listener, and whether the queue has been read.
+// The queue is only read when the webhook is enabled.
+func readWebhookQueue(app *app.ApplicationContext) ([]persistentdata.WebhookEvent, bool) {
+	if !app.Config.Webhook.Enabled {
+		return []persistentdata.WebhookEvent{}, false
+	}
+	events, err := persistentdata.GetWebhookEvents(app)
 	if err != nil {
-		app.Logger.Fatal("Failed to get Jellyfin items statistics.", zap.Error(err))
+		app.Logger.Warn(
+			"An error occured while reading the webhook queue. Only the movies found by the folder scan are announced.",
+			zap.Error(err),
+		)
+		return []persistentdata.WebhookEvent{}, false
+	}
+	app.Logger.Debug("Webhook queue read.", zap.Int("events", len(events)))
+	return events, true
+}
+
+// queuedMovieIDs returns the IDs of the movies among the events queued by the webhook listener.
+func queuedMovieIDs(events []persistentdata.WebhookEvent) []string {
+	movieIDs := []string{}
+	for _, event := range events {
+		if event.ItemType == string(jellyfinAPI.BASEITEMKIND_MOVIE) {
+			movieIDs = append(movieIDs, event.ItemID)
+		}
+	}
+	return movieIDs
+}
+
+// selectNewMovies returns the movies to announce: the detected movies (see detectNewItems),
+// and/or the movies queued by the webhook listener, depending on the webhook mode.
+func selectNewMovies(
+	detectedMovies *[]jellyfin.MovieItem,
+	movies *[]jellyfin.MovieItem,
+	queuedMovieIDs []string,
+	app *app.ApplicationContext,
+) *[]jellyfin.MovieItem {
+	if !app.Config.Webhook.Enabled {
+		return detectedMovies
+	}
+	queuedMovies := jellyfin.SelectMoviesByIDs(movies, queuedMovieIDs)
+	if app.Config.Webhook.Mode == config.WebhookModeReplace {
+		return queuedMovies
+	}
+	return jellyfin.MergeMovies(detectedMovies, queuedMovies)
+}
+
+// tmdbAPIClient returns the TMDB client, or nil if TMDB is not configured.
+// Without TMDB, the newsletter relies on Jellyfin metadata only.
+func (workflow Workflow) tmdbAPIClient(app *app.ApplicationContext) tmdb.APIInterface {
+	if !app.Config.TMDB.Enabled {
+		return nil
+	}
+	return workflow.TMDBClient
+}
+
+// saveTMDBCache saves the TMDB responses fetched during the run, when the TMDB client caches them.
+func (workflow Workflow) saveTMDBCache(app *app.ApplicationContext) {
+	cachingClient, ok := workflow.TMDBClient.(tmdb.CachingAPIInterface)
+	if !ok {
+		return
+	}
+	if err := cachingClient.SaveCache(); err != nil {
+		app.Logger.Warn(
+			"An error occured while saving the TMDB cache. TMDB responses will be fetched again in the next newsletter.",
+			zap.Error(err),
+		)
+	}
+}
+
+// newsletterItems are the items announced by a newsletter.
+type newsletterItems struct {
+	movies         *[]jellyfin.MovieItem
+	upgradedMovies *[]jellyfin.MovieItem
+	series         *[]jellyfin.NewlyAddedSeriesItem
+	musicAlbums    *[]jellyfin.MusicAlbumItem
+	books          *[]jellyfin.BookItem
+	removedMedia   *[]jellyfin.RemovedItem
+	// librarySnapshot is the library of the server in this run, to check the media removed from the other servers
+	librarySnapshot persistentdata.LibrarySnapshot
+}
+
+func (items newsletterItems) isEmpty() bool {
+	return len(*items.movies) == 0 && len(*items.series) == 0 && len(*items.musicAlbums) == 0 &&
+		len(*items.books) == 0 && len(*items.upgradedMovies) == 0 && len(*items.removedMedia) == 0
+}
+
+// mergeNewsletterItems merges the items of several Jellyfin servers. The movies and series found on several servers
+// are announced once, with the link of the first server (see jellyfin.DeduplicateMovies).
+// The media removed from a server but still on another one are not announced as removed.
+func mergeNewsletterItems(serversItems []newsletterItems) newsletterItems {
+	movies := []jellyfin.MovieItem{}
+	upgradedMovies := []jellyfin.MovieItem{}
+	series := []jellyfin.NewlyAddedSeriesItem{}
+	musicAlbums := []jellyfin.MusicAlbumItem{}
+	books := []jellyfin.BookItem{}
+	removedMedia := []jellyfin.RemovedItem{}
+	librarySnapshots := []persistentdata.LibrarySnapshot{}
+	for _, items := range serversItems {
+		movies = append(movies, *items.movies...)
+		upgradedMovies = append(upgradedMovies, *items.upgradedMovies...)
+		series = append(series, *items.series...)
+		musicAlbums = append(musicAlbums, *items.musicAlbums...)
+		books = append(books, *items.books...)
+		removedMedia = append(removedMedia, *items.removedMedia...)
+		librarySnapshots = append(librarySnapshots, items.librarySnapshot)
+	}
+	return newsletterItems{
+		movies:         jellyfin.DeduplicateMovies(&movies),
+		upgradedMovies: jellyfin.DeduplicateMovies(&upgradedMovies),
+		series:         jellyfin.DeduplicateSeries(&series),
+		musicAlbums:    &musicAlbums,
+		books:          &books,
+		removedMedia: jellyfin.DeduplicateRemovedItems(
+			jellyfin.FilterRemovedItemsInLibraries(&removedMedia, librarySnapshots),
+		),
+	}
+}
+
+// gatherItems returns the items of the Jellyfin server of the workflow to announce in the newsletter of app.
+// New items are detected by addition date or by announcement key, depending on the detection mode (see detectNewItems),
+// and the announcement keys of the items seen are added to state.announcedItems.
+// Movies and series matching an exclusion rule are left out.
+// New movies (see selectNewMovies) with a better quality than in the previous run are upgrades, not new movies.
+// The quality of the movies seen is saved in state.moviesQuality, by state key (see stateKey).
+// Removed media are found by comparing the library with the snapshot of the previous run, see findRemovedMedia.
+func (workflow Workflow) gatherItems(app *app.ApplicationContext, state *runState) newsletterItems {
+	movies := workflow.JellyfinClient.GetMovies(app)
+	state.moviesQuality[stateKey(app)] = jellyfin.GetMoviesQualityRecords(movies)
+	newItems := workflow.detectNewItems(
+		app,
+		movies,
+		state.previousMoviesQuality[stateKey(app)],
+		state.previousAnnouncedItems,
+		state.announcedItems,
+	)
+	recentlyAddedMovies, upgradedMovies := jellyfin.SplitUpgradedMovies(
+		jellyfin.FilterExcludedMovies(selectNewMovies(newItems.movies, movies, state.queuedMovieIDs, app), app),
+		state.previousMoviesQuality[stateKey(app)],
+	)
+	if !app.Config.Jellyfin.AnnounceUpgrades && len(*upgradedMovies) > 0 {
+		app.Logger.Info("Upgraded movies are not announced.", zap.Int("upgraded movies", len(*upgradedMovies)))
+		upgradedMovies = &[]jellyfin.MovieItem{}
+	}
+	removedMedia, librarySnapshot := workflow.findRemovedMedia(
+		app,
+		state.previousLibrarySnapshots,
+		state.librarySnapshots,
+	)
+	return newsletterItems{
+		movies:          recentlyAddedMovies,
+		upgradedMovies:  upgradedMovies,
+		series:          jellyfin.FilterExcludedSeries(newItems.series, app),
+		musicAlbums:     newItems.musicAlbums,
+		books:           newItems.books,
+		removedMedia:    removedMedia,
+		librarySnapshot: librarySnapshot,
+	}
+}
+
+// sendNewsletter builds the newsletter and sends it to the recipients of app.
+// The items of the Jellyfin servers (see gatherItems) are merged, then enriched with TMDB,
+// and the library statistics are summed.
+// It returns false if there is no new item, so no newsletter is sent.
+func (workflow Workflow) sendNewsletter(app *app.ApplicationContext, servers []jellyfinServer, state *runState) bool {
+	serversItems := []newsletterItems{}
+	for _, server := range servers {
+		serverWorkflow := Workflow{JellyfinClient: server.client, TMDBClient: workflow.TMDBClient}
+		serversItems = append(serversItems, serverWorkflow.gatherItems(server.app, state))
+	}
+	items := mergeNewsletterItems(serversItems)
+
+	if items.isEmpty() {
+		app.Logger.Info("No new items detected. Email notification is skipped.")
+		return false
+	}
+
+	tmdbAPIClient := workflow.tmdbAPIClient(app)
+	tmdb.EnrichMovieItemsList(items.movies, tmdbAPIClient, app)
+	tmdb.EnrichMovieItemsList(items.upgradedMovies, tmdbAPIClient, app)
+	tmdb.EnrichSeriesItemsList(items.series, tmdbAPIClient, app)
+
+	var moviesCount, episodesCount int32
+	for _, server := range servers {
+		serverMoviesCount, serverEpisodesCount, err := server.client.LibraryAPI.GetItemsStats(server.app)
+		if err != nil {
+			server.app.Logger.Fatal("Failed to get Jellyfin items statistics.", zap.Error(err))
+		}
+		moviesCount += serverMoviesCount
+		episodesCount += serverEpisodesCount
 	}
 
 	emailHTML, err := template.BuildNewMediaEmailHTML(
-		recentlyAddedMovies,
-		recentlyAddedSeries,
+		items.movies,
+		items.series,
+		items.musicAlbums,
+		items.books,
+		items.upgradedMovies,
+		items.removedMedia,
 		moviesCount,
 		episodesCount,
 		app,
@@ -56,7 +571,16 @@ func (workflow Workflow) Run(app *app.ApplicationContext) {
 	}
 
 	if app.Config.DryRun.Enabled {
-		dryrun.SaveDryRunEmail(emailHTML, recentlyAddedMovies, recentlyAddedSeries, app)
+		dryrun.SaveDryRunEmail(
+			emailHTML,
+			items.movies,
+			items.series,
+			items.musicAlbums,
+			items.books,
+			items.upgradedMovies,
+			items.removedMedia,
+			app,
+		)
 		app.Logger.Info("Successfully generated the newsletter (dry run).")
 	} else {
 		err = smtp.SendEmailToAllRecipients(emailHTML, app)
@@ -64,14 +588,5 @@ func (workflow Workflow) Run(app *app.ApplicationContext) {
 			app.Logger.Fatal("Failed to send emails to recipients.", zap.Error(err))
 		}
 	}
-
-	err = persistentdata.UpdateLastNewsletterDatetime(app.Clock.Now().UTC(), app)
-	if err != nil {
-		app.Logger.Warn(
-			"An error occured while saving the last newsletter datetime. This could lead to future error or items sent again.",
-			zap.Error(err),
-		)
-	}
-
-	app.Logger.Info("Thanks for using Jellyfin-Newsletter !")
+	return true
 }
@@ -0,0 +1,41 @@
+package persistentdata
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+)
+
+const (
+	AnnouncedItemsFilename = "ANNOUNCED_ITEMS.json"
+)
+
+// GetAnnouncedItems returns the announcement keys of the items announced by the previous runs, by Jellyfin user.
+// They are used by the snapshot detection of new items. The keys of the newsletter without Jellyfin user are saved
+// with an empty user. If the file doesn't exist, it returns an empty map, without error.
+func GetAnnouncedItems(app *app.ApplicationContext) (map[string][]string, error) {
+	data, err := os.ReadFile(getProfileFilepath(AnnouncedItemsFilename, app))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string][]string{}, nil
+		}
+		return nil, err
+	}
+
+	announcedItems := map[string][]string{}
+	if err = json.Unmarshal(data, &announcedItems); err != nil {
+		return nil, err
+	}
+	return announcedItems, nil
+}
+
+// UpdateAnnouncedItems replaces the saved announcement keys.
+func UpdateAnnouncedItems(announcedItems map[string][]string, app *app.ApplicationContext) error {
+	data, err := json.MarshalIndent(announcedItems, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getProfileFilepath(AnnouncedItemsFilename, app), data, 0600)
+}
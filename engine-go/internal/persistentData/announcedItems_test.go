@@ -0,0 +1,44 @@
+package persistentdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Case 1: file does not exist → empty map returned, no error.
+func TestGetAnnouncedItems_FileNotExist_ReturnsEmptyMap(t *testing.T) {
+	app := newAppWithTempDir(t)
+
+	got, err := GetAnnouncedItems(app)
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// Case 2: announced items written → read back identically, including the users without announced item.
+func TestUpdateAnnouncedItems_RoundTrip(t *testing.T) {
+	app := newAppWithTempDir(t)
+	want := map[string][]string{
+		"":      {"movie/tmdb/603", "series/tmdb/1399/S1E1"},
+		"alice": {},
+	}
+
+	require.NoError(t, UpdateAnnouncedItems(want, app))
+
+	got, err := GetAnnouncedItems(app)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+// Case 3: file exists but is not valid JSON → error returned.
+func TestGetAnnouncedItems_MalformedFile_ReturnsError(t *testing.T) {
+	app := newAppWithTempDir(t)
+	writeTestFile(t, getProfileFilepath(AnnouncedItemsFilename, app), "not-json")
+
+	got, err := GetAnnouncedItems(app)
+
+	require.Error(t, err)
+	assert.Nil(t, got)
+}
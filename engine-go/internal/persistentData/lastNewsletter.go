@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
@@ -14,9 +15,20 @@ const (
 	LastNewsletterFilename = "LAST_NEWSLETTER.txt"
 )
 
-func getLastNewsletterFilepath(app *app.ApplicationContext) string {
+// getProfileFilepath returns the path of a file stored next to the configuration file.
+// Each profile has its own file (e.g. LAST_NEWSLETTER_<profile>.txt), so profiles don't override each other's data.
+func getProfileFilepath(filename string, app *app.ApplicationContext) string {
 	baseDirectory, _ := filepath.Split(app.Config.ConfigFilePath)
-	return filepath.Join(baseDirectory, LastNewsletterFilename)
+	if app.Config.ProfileName != "" {
+		extension := filepath.Ext(filename)
+		filename = strings.TrimSuffix(filename, extension) + "_" + app.Config.ProfileName + extension
+	}
+	return filepath.Join(baseDirectory, filename)
+}
+
+// getLastNewsletterFilepath returns the path of the file storing the last newsletter datetime.
+func getLastNewsletterFilepath(app *app.ApplicationContext) string {
+	return getProfileFilepath(LastNewsletterFilename, app)
 }
 
 // GetLastNewsletterDatetime get the time in the LAST_NEWSLETTER.txt file.
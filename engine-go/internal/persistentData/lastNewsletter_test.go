@@ -121,3 +121,30 @@ func TestUpdateLastNewsletterDatetime_InvalidDirectory_ReturnsError(t *testing.T
 
 	require.Error(t, err)
 }
+
+// Case 7: profiles → each profile has its own file, and doesn't override the others.
+func TestUpdateLastNewsletterDatetime_Profiles_UseSeparateFiles(t *testing.T) {
+	familyApp := newAppWithTempDir(t)
+	familyApp.Config.ProfileName = "family"
+	friendsApp := &app.ApplicationContext{
+		Config: &config.Configuration{
+			ConfigFilePath: familyApp.Config.ConfigFilePath,
+			ProfileName:    "friends",
+		},
+	}
+	baseDirectory := filepath.Dir(familyApp.Config.ConfigFilePath)
+	assert.Equal(t, filepath.Join(baseDirectory, "LAST_NEWSLETTER_family.txt"), getLastNewsletterFilepath(familyApp))
+
+	familyDate := time.Date(2024, 6, 15, 10, 30, 45, 0, time.UTC)
+	friendsDate := time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, UpdateLastNewsletterDatetime(familyDate, familyApp))
+	require.NoError(t, UpdateLastNewsletterDatetime(friendsDate, friendsApp))
+
+	got, err := GetLastNewsletterDatetime(familyApp)
+	require.NoError(t, err)
+	assert.Equal(t, familyDate, *got)
+
+	got, err = GetLastNewsletterDatetime(friendsApp)
+	require.NoError(t, err)
+	assert.Equal(t, friendsDate, *got)
+}
@@ -0,0 +1,58 @@
+package persistentdata
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+)
+
+const (
+	LibrarySnapshotsFilename = "LIBRARY_SNAPSHOT.json"
+)
+
+// LibrarySnapshotItem is a movie or a series seen by a run, saved to detect removed media in the next one.
+type LibrarySnapshotItem struct {
+	Name           string `json:"name"`
+	ItemType       string `json:"item_type"` // Jellyfin item type, "Movie" or "Series"
+	ProductionYear int32  `json:"production_year,omitempty"`
+	TMDBId         string `json:"tmdb_id,omitempty"`
+	Folder         string `json:"folder"` // Watched folder the item was found in
+	// Excluded is true if an exclusion rule matches the item. Excluded items are never reported as removed.
+	Excluded bool `json:"excluded,omitempty"`
+}
+
+// LibrarySnapshot is the content of the folders checked for removed media.
+type LibrarySnapshot struct {
+	Folders []string                       `json:"folders"`
+	Items   map[string]LibrarySnapshotItem `json:"items"` // By Jellyfin item ID
+}
+
+// GetLibrarySnapshots returns the snapshots saved by the previous run, by Jellyfin user.
+// The snapshot of the newsletter without Jellyfin user is saved with an empty user.
+// If the file doesn't exist, it returns an empty map, without error.
+func GetLibrarySnapshots(app *app.ApplicationContext) (map[string]LibrarySnapshot, error) {
+	data, err := os.ReadFile(getProfileFilepath(LibrarySnapshotsFilename, app))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]LibrarySnapshot{}, nil
+		}
+		return nil, err
+	}
+
+	snapshots := map[string]LibrarySnapshot{}
+	if err = json.Unmarshal(data, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// UpdateLibrarySnapshots replaces the saved snapshots.
+func UpdateLibrarySnapshots(snapshots map[string]LibrarySnapshot, app *app.ApplicationContext) error {
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getProfileFilepath(LibrarySnapshotsFilename, app), data, 0600)
+}
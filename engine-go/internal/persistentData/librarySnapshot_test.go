@@ -0,0 +1,54 @@
+package persistentdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Case 1: file does not exist → empty map returned, no error.
+func TestGetLibrarySnapshots_FileNotExist_ReturnsEmptyMap(t *testing.T) {
+	app := newAppWithTempDir(t)
+
+	got, err := GetLibrarySnapshots(app)
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// Case 2: snapshots written → read back identically.
+func TestUpdateLibrarySnapshots_RoundTrip(t *testing.T) {
+	app := newAppWithTempDir(t)
+	want := map[string]LibrarySnapshot{
+		"": {
+			Folders: []string{"Movies", "Shows"},
+			Items: map[string]LibrarySnapshotItem{
+				"8b54388aca994d4fb867944d3150a7e0": {
+					Name:           "Movie 1",
+					ItemType:       "Movie",
+					ProductionYear: 2023,
+					Folder:         "Movies",
+				},
+			},
+		},
+		"alice": {Folders: []string{"Movies"}, Items: map[string]LibrarySnapshotItem{}},
+	}
+
+	require.NoError(t, UpdateLibrarySnapshots(want, app))
+
+	got, err := GetLibrarySnapshots(app)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+// Case 3: file exists but is not valid JSON → error returned.
+func TestGetLibrarySnapshots_MalformedFile_ReturnsError(t *testing.T) {
+	app := newAppWithTempDir(t)
+	writeTestFile(t, getProfileFilepath(LibrarySnapshotsFilename, app), "not-json")
+
+	got, err := GetLibrarySnapshots(app)
+
+	require.Error(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,50 @@
+package persistentdata
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+)
+
+const (
+	MoviesQualityFilename = "MOVIES_QUALITY.json"
+)
+
+// MovieQualityRecord is the quality of a movie file, saved at each run to detect replaced files in the next one.
+type MovieQualityRecord struct {
+	Name       string `json:"name"`
+	TMDBId     string `json:"tmdb_id,omitempty"`
+	Resolution string `json:"resolution,omitempty"`
+	VideoRange string `json:"video_range,omitempty"`
+	Codec      string `json:"codec,omitempty"`
+}
+
+// GetMoviesQuality returns the quality of the movies saved by the previous run, by Jellyfin user and server,
+// then by Jellyfin item ID. The quality of the newsletter without Jellyfin user is saved with an empty user.
+// If the file doesn't exist, it returns an empty map, without error.
+func GetMoviesQuality(app *app.ApplicationContext) (map[string]map[string]MovieQualityRecord, error) {
+	data, err := os.ReadFile(getProfileFilepath(MoviesQualityFilename, app))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]map[string]MovieQualityRecord{}, nil
+		}
+		return nil, err
+	}
+
+	moviesQuality := map[string]map[string]MovieQualityRecord{}
+	if err = json.Unmarshal(data, &moviesQuality); err != nil {
+		return nil, err
+	}
+	return moviesQuality, nil
+}
+
+// UpdateMoviesQuality replaces the saved quality of the movies.
+func UpdateMoviesQuality(moviesQuality map[string]map[string]MovieQualityRecord, app *app.ApplicationContext) error {
+	data, err := json.MarshalIndent(moviesQuality, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getProfileFilepath(MoviesQualityFilename, app), data, 0600)
+}
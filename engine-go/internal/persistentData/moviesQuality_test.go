@@ -0,0 +1,54 @@
+package persistentdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Case 1: file does not exist → empty map returned, no error.
+func TestGetMoviesQuality_FileNotExist_ReturnsEmptyMap(t *testing.T) {
+	app := newAppWithTempDir(t)
+
+	got, err := GetMoviesQuality(app)
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// Case 2: quality written → read back identically.
+func TestUpdateMoviesQuality_RoundTrip(t *testing.T) {
+	app := newAppWithTempDir(t)
+	want := map[string]map[string]MovieQualityRecord{
+		"": {
+			"8b54388aca994d4fb867944d3150a7e0": {
+				Name:       "Movie 1",
+				TMDBId:     "2876",
+				Resolution: "4K",
+				VideoRange: "HDR10",
+				Codec:      "HEVC",
+			},
+		},
+		"cabin/alice": {
+			"8b54388aca994d4fb867944d3150a7e0": {Name: "Movie 1", TMDBId: "2876", Resolution: "1080p"},
+		},
+	}
+
+	require.NoError(t, UpdateMoviesQuality(want, app))
+
+	got, err := GetMoviesQuality(app)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+// Case 3: file exists but is not valid JSON → error returned.
+func TestGetMoviesQuality_MalformedFile_ReturnsError(t *testing.T) {
+	app := newAppWithTempDir(t)
+	writeTestFile(t, getProfileFilepath(MoviesQualityFilename, app), "not-json")
+
+	got, err := GetMoviesQuality(app)
+
+	require.Error(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,86 @@
+package persistentdata
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+)
+
+const (
+	TMDBCacheFilename = "TMDB_CACHE.json"
+)
+
+// tmdbCacheMutex serializes the cache updates of the newsletters running at the same time.
+var tmdbCacheMutex sync.Mutex
+
+// TMDBCacheEntry is a TMDB response saved to be reused by the next runs.
+type TMDBCacheEntry struct {
+	Response  json.RawMessage `json:"response"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
+
+// getTMDBCacheFilepath returns the path of the TMDB cache. The cache is shared by all the profiles,
+// since TMDB responses don't depend on the newsletter (the language is part of the cache key).
+func getTMDBCacheFilepath(app *app.ApplicationContext) string {
+	return filepath.Join(filepath.Dir(app.Config.ConfigFilePath), TMDBCacheFilename)
+}
+
+// readTMDBCache returns the saved entries. The caller must hold tmdbCacheMutex.
+func readTMDBCache(app *app.ApplicationContext) (map[string]TMDBCacheEntry, error) {
+	data, err := os.ReadFile(getTMDBCacheFilepath(app))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]TMDBCacheEntry{}, nil
+		}
+		return nil, err
+	}
+
+	entries := map[string]TMDBCacheEntry{}
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetTMDBCache returns the saved TMDB responses, by request key.
+// If the file doesn't exist, it returns an empty map, without error.
+func GetTMDBCache(app *app.ApplicationContext) (map[string]TMDBCacheEntry, error) {
+	tmdbCacheMutex.Lock()
+	defer tmdbCacheMutex.Unlock()
+	return readTMDBCache(app)
+}
+
+// UpdateTMDBCache adds entries to the saved TMDB responses. When a request is already saved, the most recently
+// fetched response is kept, so newsletters running at the same time don't override each other's responses.
+// Entries fetched before fetchedAfter are dropped.
+func UpdateTMDBCache(entries map[string]TMDBCacheEntry, fetchedAfter time.Time, app *app.ApplicationContext) error {
+	tmdbCacheMutex.Lock()
+	defer tmdbCacheMutex.Unlock()
+
+	savedEntries, err := readTMDBCache(app)
+	if err != nil {
+		// A corrupted cache is replaced
+		savedEntries = map[string]TMDBCacheEntry{}
+	}
+	for key, entry := range entries {
+		if savedEntry, ok := savedEntries[key]; !ok || entry.FetchedAt.After(savedEntry.FetchedAt) {
+			savedEntries[key] = entry
+		}
+	}
+	for key, entry := range savedEntries {
+		if entry.FetchedAt.Before(fetchedAfter) {
+			delete(savedEntries, key)
+		}
+	}
+
+	data, err := json.MarshalIndent(savedEntries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getTMDBCacheFilepath(app), data, 0600)
+}
@@ -0,0 +1,75 @@
+package persistentdata
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Case 1: file does not exist → empty map returned, no error.
+func TestGetTMDBCache_FileNotExist_ReturnsEmptyMap(t *testing.T) {
+	app := newAppWithTempDir(t)
+
+	got, err := GetTMDBCache(app)
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// Case 2: entries merged with the saved ones → the most recently fetched response is kept, old entries are dropped.
+func TestUpdateTMDBCache_MergesAndDropsOldEntries(t *testing.T) {
+	app := newAppWithTempDir(t)
+	now := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, UpdateTMDBCache(map[string]TMDBCacheEntry{
+		"movie|id=1|language=en": {Response: json.RawMessage(`{"overview":"old"}`), FetchedAt: now.Add(-time.Hour)},
+		"movie|id=2|language=en": {Response: json.RawMessage(`{"overview":"kept"}`), FetchedAt: now.Add(-time.Hour)},
+		"movie|id=3|language=en": {Response: json.RawMessage(`{"overview":"dropped"}`), FetchedAt: now.AddDate(-1, 0, 0)},
+	}, time.Time{}, app))
+
+	require.NoError(t, UpdateTMDBCache(map[string]TMDBCacheEntry{
+		"movie|id=1|language=en": {Response: json.RawMessage(`{"overview":"new"}`), FetchedAt: now},
+		"movie|id=2|language=en": {Response: json.RawMessage(`{"overview":"older"}`), FetchedAt: now.Add(-2 * time.Hour)},
+	}, now.AddDate(0, -6, 0), app))
+
+	got, err := GetTMDBCache(app)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.JSONEq(t, `{"overview":"new"}`, string(got["movie|id=1|language=en"].Response))
+	assert.JSONEq(t, `{"overview":"kept"}`, string(got["movie|id=2|language=en"].Response))
+	assert.True(t, now.Equal(got["movie|id=1|language=en"].FetchedAt))
+}
+
+// Case 3: file exists but is not valid JSON → error returned on read, replaced on update.
+func TestTMDBCache_MalformedFile(t *testing.T) {
+	app := newAppWithTempDir(t)
+	writeTestFile(t, getTMDBCacheFilepath(app), "not-json")
+
+	got, err := GetTMDBCache(app)
+	require.Error(t, err)
+	assert.Nil(t, got)
+
+	entries := map[string]TMDBCacheEntry{
+		"tv|id=1|language=en": {Response: json.RawMessage(`{}`), FetchedAt: time.Now().UTC()},
+	}
+	require.NoError(t, UpdateTMDBCache(entries, time.Time{}, app))
+	got, err = GetTMDBCache(app)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+// Case 4: the cache is shared by the profiles.
+func TestTMDBCache_SharedByProfiles(t *testing.T) {
+	app := newAppWithTempDir(t)
+	entries := map[string]TMDBCacheEntry{
+		"tv|id=1|language=en": {Response: json.RawMessage(`{}`), FetchedAt: time.Now().UTC()},
+	}
+	require.NoError(t, UpdateTMDBCache(entries, time.Time{}, app))
+
+	app.Config.ProfileName = "family"
+	got, err := GetTMDBCache(app)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
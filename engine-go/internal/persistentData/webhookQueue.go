@@ -0,0 +1,95 @@
+package persistentdata
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+)
+
+const (
+	WebhookQueueFilename = "WEBHOOK_QUEUE.json"
+)
+
+// webhookQueueMutex serializes the queue updates of the webhook listener and of the newsletter runs.
+var webhookQueueMutex sync.Mutex
+
+// WebhookEvent is an item added to Jellyfin, received by the webhook listener.
+type WebhookEvent struct {
+	ItemID     string    `json:"item_id"`
+	ItemType   string    `json:"item_type"` // Jellyfin item type, e.g. "Movie" or "Episode"
+	Name       string    `json:"name,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// readWebhookEvents returns the queued events. The caller must hold webhookQueueMutex.
+func readWebhookEvents(app *app.ApplicationContext) ([]WebhookEvent, error) {
+	data, err := os.ReadFile(getProfileFilepath(WebhookQueueFilename, app))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []WebhookEvent{}, nil
+		}
+		return nil, err
+	}
+
+	events := []WebhookEvent{}
+	if err = json.Unmarshal(data, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// writeWebhookEvents replaces the queued events. The caller must hold webhookQueueMutex.
+func writeWebhookEvents(events []WebhookEvent, app *app.ApplicationContext) error {
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getProfileFilepath(WebhookQueueFilename, app), data, 0600)
+}
+
+// GetWebhookEvents returns the events queued by the webhook listener, oldest first.
+// If the file doesn't exist, it returns an empty list, without error.
+func GetWebhookEvents(app *app.ApplicationContext) ([]WebhookEvent, error) {
+	webhookQueueMutex.Lock()
+	defer webhookQueueMutex.Unlock()
+	return readWebhookEvents(app)
+}
+
+// AppendWebhookEvent adds an event at the end of the queue.
+func AppendWebhookEvent(event WebhookEvent, app *app.ApplicationContext) error {
+	webhookQueueMutex.Lock()
+	defer webhookQueueMutex.Unlock()
+
+	events, err := readWebhookEvents(app)
+	if err != nil {
+		return err
+	}
+	return writeWebhookEvents(append(events, event), app)
+}
+
+// RemoveWebhookEvents removes the events consumed by a newsletter run, i.e. the events it has read.
+// The events received since are kept for the next run, whatever their reception date.
+func RemoveWebhookEvents(consumedEvents []WebhookEvent, app *app.ApplicationContext) error {
+	webhookQueueMutex.Lock()
+	defer webhookQueueMutex.Unlock()
+
+	events, err := readWebhookEvents(app)
+	if err != nil {
+		return err
+	}
+	remainingEvents := []WebhookEvent{}
+	for _, event := range events {
+		isConsumed := slices.ContainsFunc(consumedEvents, func(consumedEvent WebhookEvent) bool {
+			return consumedEvent.ItemID == event.ItemID && consumedEvent.ReceivedAt.Equal(event.ReceivedAt)
+		})
+		if !isConsumed {
+			remainingEvents = append(remainingEvents, event)
+		}
+	}
+	return writeWebhookEvents(remainingEvents, app)
+}
@@ -0,0 +1,74 @@
+package persistentdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Case 1: file does not exist → empty queue returned, no error.
+func TestGetWebhookEvents_FileNotExist_ReturnsEmptyQueue(t *testing.T) {
+	app := newAppWithTempDir(t)
+
+	got, err := GetWebhookEvents(app)
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// Case 2: events appended → read back in order.
+func TestAppendWebhookEvent_KeepsOrder(t *testing.T) {
+	app := newAppWithTempDir(t)
+	first := WebhookEvent{
+		ItemID:     "8b54388aca994d4fb867944d3150a7e0",
+		ItemType:   "Movie",
+		Name:       "Movie 1",
+		ReceivedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+	second := WebhookEvent{
+		ItemID:     "7dcf7149f71046d5a50c626e3486259b",
+		ItemType:   "Episode",
+		ReceivedAt: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, AppendWebhookEvent(first, app))
+	require.NoError(t, AppendWebhookEvent(second, app))
+
+	got, err := GetWebhookEvents(app)
+	require.NoError(t, err)
+	assert.Equal(t, []WebhookEvent{first, second}, got)
+}
+
+// Case 3: the events read by the run are removed, the events queued while the run is in progress are kept.
+func TestRemoveWebhookEvents_KeepsEventsQueuedDuringRun(t *testing.T) {
+	app := newAppWithTempDir(t)
+	receivedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	consumed := WebhookEvent{ItemID: "consumed", ItemType: "Movie", ReceivedAt: receivedAt}
+	require.NoError(t, AppendWebhookEvent(consumed, app))
+
+	readEvents, err := GetWebhookEvents(app)
+	require.NoError(t, err)
+	// Queued while the run is in progress. Its reception date doesn't tell it apart from the consumed event.
+	queuedDuringRun := WebhookEvent{ItemID: "queued during run", ItemType: "Movie", ReceivedAt: receivedAt}
+	require.NoError(t, AppendWebhookEvent(queuedDuringRun, app))
+
+	require.NoError(t, RemoveWebhookEvents(readEvents, app))
+
+	got, err := GetWebhookEvents(app)
+	require.NoError(t, err)
+	assert.Equal(t, []WebhookEvent{queuedDuringRun}, got)
+}
+
+// Case 4: file exists but is not valid JSON → error returned, and nothing is appended.
+func TestWebhookQueue_MalformedFile_ReturnsError(t *testing.T) {
+	app := newAppWithTempDir(t)
+	writeTestFile(t, getProfileFilepath(WebhookQueueFilename, app), "not-json")
+
+	got, err := GetWebhookEvents(app)
+	require.Error(t, err)
+	assert.Nil(t, got)
+
+	require.Error(t, AppendWebhookEvent(WebhookEvent{ItemID: "id"}, app))
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// TMDB allows around 50 requests per second and 20 simultaneous connections per IP address,
+// see https://developer.themoviedb.org/docs/rate-limiting. The TMDB limiter stays below these limits.
+const (
+	TMDBRequestsPerSecond = 40
+	TMDBRequestsBurst     = 20
+)
+
+// Limiter is a token bucket shared by the requests towards an API. It is safe for concurrent use.
+type Limiter struct {
+	mutex             sync.Mutex
+	requestsPerSecond float64
+	burst             float64
+	tokens            float64
+	lastRefill        time.Time
+	pausedUntil       time.Time
+}
+
+// New returns a limiter allowing requestsPerSecond requests per second on average,
+// and up to burst requests at once.
+func New(requestsPerSecond float64, burst int) *Limiter {
+	return &Limiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             float64(burst),
+		tokens:            float64(burst),
+		lastRefill:        time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before sending its request.
+func (limiter *Limiter) reserve(now time.Time) time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	if now.After(limiter.lastRefill) {
+		elapsed := now.Sub(limiter.lastRefill).Seconds()
+		limiter.tokens = min(limiter.burst, limiter.tokens+elapsed*limiter.requestsPerSecond)
+		limiter.lastRefill = now
+	}
+	limiter.tokens--
+
+	var delay time.Duration
+	if limiter.tokens < 0 {
+		// The token is borrowed from the future refills
+		delay = time.Duration(-limiter.tokens / limiter.requestsPerSecond * float64(time.Second))
+	}
+	return max(delay, limiter.pausedUntil.Sub(now))
+}
+
+// Wait blocks until a request can be sent. A nil limiter doesn't limit the requests.
+func (limiter *Limiter) Wait() {
+	if limiter == nil {
+		return
+	}
+	if delay := limiter.reserve(time.Now()); delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// Pause delays the requests of every caller by duration, e.g. when the API answers that the limit is reached.
+func (limiter *Limiter) Pause(duration time.Duration) {
+	if limiter == nil {
+		return
+	}
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	if pausedUntil := time.Now().Add(duration); pausedUntil.After(limiter.pausedUntil) {
+		limiter.pausedUntil = pausedUntil
+	}
+}
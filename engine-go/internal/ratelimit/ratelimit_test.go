@@ -0,0 +1,24 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	limiter := New(10, 2)
+	now := limiter.lastRefill
+
+	assert.Equal(t, time.Duration(0), limiter.reserve(now), "the burst is allowed")
+	assert.Equal(t, time.Duration(0), limiter.reserve(now), "the burst is allowed")
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, limiter.reserve(now))
+
+	later := now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), limiter.reserve(later), "tokens are refilled")
+
+	limiter.Pause(time.Hour)
+	assert.Greater(t, limiter.reserve(later), 50*time.Minute, "a pause delays every request")
+}
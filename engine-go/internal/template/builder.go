@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"html/template"
 	"io/fs"
+	"maps"
 	"net/url"
 	"path/filepath"
 	"slices"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
 	"go.uber.org/zap"
 )
 
@@ -27,55 +29,139 @@ const (
 )
 
 type newMovieItemTemplateData struct {
+	PosterURL              string
+	Name                   string
+	AddedOnLabel           string
+	AdditionDate           string
+	Overview               string
+	IncludeItemOverviews   bool
+	MediaURL               string
+	ProductionYear         string
+	Genres                 string // e.g. "Science Fiction, Drama"
+	Runtime                string // e.g. "169 min"
+	OfficialRating         string // e.g. "PG-13"
+	CommunityRating        string // Out of 10, e.g. "8.4"
+	CriticRating           string // Percentage, e.g. "73%"
+	Resolution             string // e.g. "4K"
+	VideoRange             string // HDR format, e.g. "HDR10". Empty for SDR
+	AudioLanguagesLabel    string
+	AudioLanguages         string // e.g. "English, French"
+	SubtitleLanguagesLabel string
+	SubtitleLanguages      string
+}
+type newSeriesItemTemplateData struct {
 	PosterURL            string
-	Name                 string
+	SeriesName           string
 	AddedOnLabel         string
 	AdditionDate         string
 	Overview             string
+	NewSeriesTitle       string
 	IncludeItemOverviews bool
 	MediaURL             string
+	NewSeasons           []newSeasonTemplateData // Empty for a whole new series, presented as a whole
 }
-type newSeriesItemTemplateData struct {
-	PosterURL            string
-	SeriesName           string
+
+type newSeasonTemplateData struct {
+	SeasonLabel string // e.g. "S03"
+	Name        string
+	Overview    string
+	PosterURL   string
+	IsSeasonNew bool
+	Episodes    []newEpisodeTemplateData // New episodes of the season. Empty for a whole new season
+}
+
+type newEpisodeTemplateData struct {
+	EpisodeLabel string // e.g. "E05"
+	Name         string
+	AirDate      string // Empty if unknown
+}
+
+type newMusicItemTemplateData struct {
+	ImageURL     string
+	Name         string
+	AlbumArtist  string
+	AddedOnLabel string
+	AdditionDate string
+	MediaURL     string
+}
+
+type newBookItemTemplateData struct {
+	ImageURL             string
+	Name                 string
+	Author               string
+	FormatLabel          string // "Audiobook" for audiobooks, empty for books
 	AddedOnLabel         string
 	AdditionDate         string
 	Overview             string
-	NewSeriesTitle       string
 	IncludeItemOverviews bool
 	MediaURL             string
 }
 
+type upgradedMovieItemTemplateData struct {
+	PosterURL       string
+	Name            string
+	QualityLabel    string // Previous and new quality, e.g. "1080p → 4K HDR10 HEVC"
+	UpgradedOnLabel string
+	UpgradeDate     string
+	MediaURL        string
+}
+
+type removedItemTemplateData struct {
+	Name           string
+	ProductionYear string
+	TypeLabel      string // "Movie" or "Series"
+}
+
 type newMediaTemplateData struct {
-	HTMLLang                         string
-	HTMLDir                          string
-	Title                            string
-	Subtitle                         string
-	JellyfinURL                      string
-	DiscoverNowLabel                 string
-	DisplayNewMovies                 bool
-	NewFilmLabel                     string
-	NewMovies                        []newMovieItemTemplateData
-	RemainingMoviesNotDisplayedCount int // #151. If 0, all movies are displayed
-	DisplayNewSeries                 bool
-	NewSeriesLabel                   string
-	NewSeries                        []newSeriesItemTemplateData
-	RemainingSeriesNotDisplayedCount int // #151. If 0, all series are displayed
-	CurrentlyAvailableLabel          string
-	MoviesCount                      string
-	MoviesLabel                      string
-	SeriesCount                      string
-	SeriesLabel                      string
-	FooterLabel                      string
-	FooterProjectLinkLabel           string
-	FooterOpenSourceProjectLabel     string
-	FooterDevelopedByLabel           string
-	FooterLicenceAndCopyright        string
-	AndLocalized                     string
-	TheContributorsLabel             string
-	AndMoreTitlesPrefixLabel         string
-	AndMoreTitlesSuffixLabelSeries   string
-	AndMoreTitlesSuffixLabelMovies   string
+	HTMLLang                                 string
+	HTMLDir                                  string
+	Title                                    string
+	Subtitle                                 string
+	JellyfinURL                              string
+	DiscoverNowLabel                         string
+	DisplayNewMovies                         bool
+	NewFilmLabel                             string
+	NewMovies                                []newMovieItemTemplateData
+	RemainingMoviesNotDisplayedCount         int // #151. If 0, all movies are displayed
+	DisplayNewSeries                         bool
+	NewSeriesLabel                           string
+	NewSeries                                []newSeriesItemTemplateData
+	RemainingSeriesNotDisplayedCount         int // #151. If 0, all series are displayed
+	DisplayNewMusic                          bool
+	NewMusicLabel                            string
+	NewMusic                                 []newMusicItemTemplateData
+	RemainingMusicNotDisplayedCount          int // If 0, all albums are displayed
+	DisplayNewBooks                          bool
+	NewBooksLabel                            string
+	NewBooks                                 []newBookItemTemplateData
+	RemainingBooksNotDisplayedCount          int // If 0, all books are displayed
+	DisplayUpgradedMovies                    bool
+	UpgradedMoviesLabel                      string
+	UpgradedMovies                           []upgradedMovieItemTemplateData
+	RemainingUpgradedMoviesNotDisplayedCount int // If 0, all upgraded movies are displayed
+	DisplayRemovedMedia                      bool
+	RemovedMediaLabel                        string
+	RemovedMedia                             []removedItemTemplateData
+	RemainingRemovedMediaNotDisplayedCount   int // If 0, all removed items are displayed
+	CurrentlyAvailableLabel                  string
+	MoviesCount                              string
+	MoviesLabel                              string
+	SeriesCount                              string
+	SeriesLabel                              string
+	FooterLabel                              string
+	FooterProjectLinkLabel                   string
+	FooterOpenSourceProjectLabel             string
+	FooterDevelopedByLabel                   string
+	FooterLicenceAndCopyright                string
+	AndLocalized                             string
+	TheContributorsLabel                     string
+	AndMoreTitlesPrefixLabel                 string
+	AndMoreTitlesSuffixLabelSeries           string
+	AndMoreTitlesSuffixLabelMovies           string
+	AndMoreTitlesSuffixLabelMusic            string
+	AndMoreTitlesSuffixLabelBooks            string
+	AndMoreTitlesSuffixLabelUpgradedMovies   string
+	AndMoreTitlesSuffixLabelRemovedMedia     string
 }
 
 type titlePlaceholders struct {
@@ -257,6 +343,47 @@ func buildNewSeriesItemFromSeriesNewItems(item jellyfin.NewlyAddedSeriesItem, ap
 	return title
 }
 
+// buildNewSeasonsTemplateData lists the new seasons of a partially updated series, sorted by season number,
+// with their new episodes sorted by episode number. The seasons of a whole new series are not listed.
+func buildNewSeasonsTemplateData(item jellyfin.NewlyAddedSeriesItem) []newSeasonTemplateData {
+	newSeasonsData := []newSeasonTemplateData{}
+	if item.IsSeriesNew {
+		return newSeasonsData
+	}
+
+	seasons := slices.SortedFunc(maps.Values(item.NewSeasons), func(a, b jellyfin.SeasonItem) int {
+		return int(a.SeasonNumber - b.SeasonNumber)
+	})
+	for _, season := range seasons {
+		episodesData := []newEpisodeTemplateData{}
+		if !season.IsSeasonNew {
+			episodes := slices.SortedFunc(maps.Values(season.Episodes), func(a, b jellyfin.EpisodeItem) int {
+				return int(a.EpisodeNumber - b.EpisodeNumber)
+			})
+			for _, episode := range episodes {
+				airDate := ""
+				if !episode.AirDate.IsZero() {
+					airDate = episode.AirDate.Format("2006-01-02")
+				}
+				episodesData = append(episodesData, newEpisodeTemplateData{
+					EpisodeLabel: fmt.Sprintf("E%02d", episode.EpisodeNumber),
+					Name:         episode.Name,
+					AirDate:      airDate,
+				})
+			}
+		}
+		newSeasonsData = append(newSeasonsData, newSeasonTemplateData{
+			SeasonLabel: fmt.Sprintf("S%02d", season.SeasonNumber),
+			Name:        season.Name,
+			Overview:    season.Overview,
+			PosterURL:   season.PosterURL,
+			IsSeasonNew: season.IsSeasonNew,
+			Episodes:    episodesData,
+		})
+	}
+	return newSeasonsData
+}
+
 func BuildEmailTitleWithPlaceholders(
 	templateStr string,
 	observedPeriodDays int,
@@ -363,6 +490,16 @@ func getMediaURL(jellyfinParsedURL *url.URL, mediaID string) string {
 	return mediaURL.String()
 }
 
+// itemServerURL returns the parsed public URL of the Jellyfin server of an item (see jellyfin.MovieItem.ServerURL),
+// or defaultParsedURL if the item has no server URL.
+func itemServerURL(defaultParsedURL *url.URL, serverURL string) *url.URL {
+	if serverURL == "" {
+		return defaultParsedURL
+	}
+	parsedServerURL, _ := url.Parse(serverURL)
+	return parsedServerURL
+}
+
 func sortJellyfinNewSeriesItems(
 	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem,
 	app *app.ApplicationContext,
@@ -384,6 +521,45 @@ func sortJellyfinNewSeriesItems(
 	return newJellyfinSeriesSorted
 }
 
+func sortJellyfinNewMusicAlbums(
+	newJellyfinMusicAlbums *[]jellyfin.MusicAlbumItem,
+	app *app.ApplicationContext,
+) []jellyfin.MusicAlbumItem {
+	newJellyfinMusicAlbumsSorted := slices.Clone(*newJellyfinMusicAlbums)
+	slices.SortFunc(newJellyfinMusicAlbumsSorted, func(a, b jellyfin.MusicAlbumItem) int {
+		switch app.Config.EmailTemplate.SortMode {
+		case SortModeNameAsc:
+			return strings.Compare(a.Name, b.Name)
+		case SortModeNameDesc:
+			return strings.Compare(b.Name, a.Name)
+		case SortModeDateDesc:
+			return b.AdditionDate.Compare(a.AdditionDate)
+		// date_asc is the default option
+		default:
+			return a.AdditionDate.Compare(b.AdditionDate)
+		}
+	})
+	return newJellyfinMusicAlbumsSorted
+}
+
+func sortJellyfinNewBooks(newJellyfinBooks *[]jellyfin.BookItem, app *app.ApplicationContext) []jellyfin.BookItem {
+	newJellyfinBooksSorted := slices.Clone(*newJellyfinBooks)
+	slices.SortFunc(newJellyfinBooksSorted, func(a, b jellyfin.BookItem) int {
+		switch app.Config.EmailTemplate.SortMode {
+		case SortModeNameAsc:
+			return strings.Compare(a.Name, b.Name)
+		case SortModeNameDesc:
+			return strings.Compare(b.Name, a.Name)
+		case SortModeDateDesc:
+			return b.AdditionDate.Compare(a.AdditionDate)
+		// date_asc is the default option
+		default:
+			return a.AdditionDate.Compare(b.AdditionDate)
+		}
+	})
+	return newJellyfinBooksSorted
+}
+
 func shouldOverviewsBeDisplayed(itemsCount int, app *app.ApplicationContext) bool {
 	switch app.Config.EmailTemplate.DisplayOverviewMaxItems {
 	case -1:
@@ -439,13 +615,25 @@ func getNewMovieTemplateDataFromSortedNewItems(
 			break
 		}
 		newMoviesData = append(newMoviesData, newMovieItemTemplateData{
-			PosterURL:            newMovieItem.PosterURL,
-			Name:                 newMovieItem.Name,
-			AdditionDate:         newMovieItem.AdditionDate.Format("2006-01-02"),
-			Overview:             newMovieItem.Overview,
-			AddedOnLabel:         app.Localizer.Localize("added_on"),
-			IncludeItemOverviews: displayMovieOverviews,
-			MediaURL:             getMediaURL(jellyfinParsedURL, newMovieItem.ID),
+			PosterURL:              newMovieItem.PosterURL,
+			Name:                   newMovieItem.Name,
+			AdditionDate:           newMovieItem.AdditionDate.Format("2006-01-02"),
+			Overview:               newMovieItem.Overview,
+			AddedOnLabel:           app.Localizer.Localize("added_on"),
+			IncludeItemOverviews:   displayMovieOverviews,
+			MediaURL:               getMediaURL(itemServerURL(jellyfinParsedURL, newMovieItem.ServerURL), newMovieItem.ID),
+			ProductionYear:         formatProductionYear(newMovieItem.ProductionYear),
+			Genres:                 strings.Join(newMovieItem.Genres, ", "),
+			Runtime:                formatRuntime(newMovieItem.RuntimeMinutes),
+			OfficialRating:         newMovieItem.OfficialRating,
+			CommunityRating:        formatCommunityRating(newMovieItem.CommunityRating),
+			CriticRating:           formatCriticRating(newMovieItem.CriticRating),
+			Resolution:             newMovieItem.Quality.Resolution,
+			VideoRange:             formatVideoRange(newMovieItem.Quality.VideoRange),
+			AudioLanguagesLabel:    app.Localizer.Localize("audio_languages"),
+			AudioLanguages:         formatLanguages(newMovieItem.AudioLanguages, app),
+			SubtitleLanguagesLabel: app.Localizer.Localize("subtitle_languages"),
+			SubtitleLanguages:      formatLanguages(newMovieItem.SubtitleLanguages, app),
 		})
 	}
 
@@ -478,16 +666,151 @@ func getNewSerieTemplatesDataFromSortedItems(
 			Overview:             newSeriesItem.Overview,
 			NewSeriesTitle:       buildNewSeriesItemFromSeriesNewItems(newSeriesItem, app),
 			IncludeItemOverviews: displaySeriesOverviews,
-			MediaURL:             getMediaURL(jellyfinParsedURL, newSeriesItem.SeriesID),
+			MediaURL:             getMediaURL(itemServerURL(jellyfinParsedURL, newSeriesItem.ServerURL), newSeriesItem.SeriesID),
+			NewSeasons:           buildNewSeasonsTemplateData(newSeriesItem),
 		})
 	}
 
 	return newSeriesData
 }
 
+func getNewMusicTemplateDataFromSortedItems(
+	newJellyfinMusicAlbumsSorted []jellyfin.MusicAlbumItem,
+	app *app.ApplicationContext,
+) []newMusicItemTemplateData {
+	jellyfinParsedURL, _ := url.Parse(app.Config.EmailTemplate.JellyfinURL)
+	newMusicData := []newMusicItemTemplateData{}
+
+	for i, newAlbumItem := range newJellyfinMusicAlbumsSorted {
+		if app.Config.EmailTemplate.MaxDisplayedItems != 0 && i >= app.Config.EmailTemplate.MaxDisplayedItems {
+			app.Logger.Debug(
+				"MaxDisplayedItems setting reached. Next new items will be ignored.",
+				zap.Int("MaxDisplayedItems", app.Config.EmailTemplate.MaxDisplayedItems),
+				zap.Int("newJellyfinMusicAlbumsSorted count", len(newJellyfinMusicAlbumsSorted)),
+				zap.String("type", "music"),
+			)
+			break
+		}
+		newMusicData = append(newMusicData, newMusicItemTemplateData{
+			ImageURL:     newAlbumItem.ImageURL,
+			Name:         newAlbumItem.Name,
+			AlbumArtist:  newAlbumItem.AlbumArtist,
+			AddedOnLabel: app.Localizer.Localize("added_on"),
+			AdditionDate: newAlbumItem.AdditionDate.Format("2006-01-02"),
+			MediaURL:     getMediaURL(itemServerURL(jellyfinParsedURL, newAlbumItem.ServerURL), newAlbumItem.ID),
+		})
+	}
+
+	return newMusicData
+}
+
+func getNewBookTemplateDataFromSortedItems(
+	newJellyfinBooksSorted []jellyfin.BookItem,
+	app *app.ApplicationContext,
+) []newBookItemTemplateData {
+	jellyfinParsedURL, _ := url.Parse(app.Config.EmailTemplate.JellyfinURL)
+	displayBookOverviews := shouldOverviewsBeDisplayed(len(newJellyfinBooksSorted), app)
+	newBooksData := []newBookItemTemplateData{}
+
+	for i, newBookItem := range newJellyfinBooksSorted {
+		if app.Config.EmailTemplate.MaxDisplayedItems != 0 && i >= app.Config.EmailTemplate.MaxDisplayedItems {
+			app.Logger.Debug(
+				"MaxDisplayedItems setting reached. Next new items will be ignored.",
+				zap.Int("MaxDisplayedItems", app.Config.EmailTemplate.MaxDisplayedItems),
+				zap.Int("newJellyfinBooksSorted count", len(newJellyfinBooksSorted)),
+				zap.String("type", "books"),
+			)
+			break
+		}
+		formatLabel := ""
+		if newBookItem.IsAudioBook {
+			formatLabel = app.Localizer.Localize("audiobook")
+		}
+		newBooksData = append(newBooksData, newBookItemTemplateData{
+			ImageURL:             newBookItem.ImageURL,
+			Name:                 newBookItem.Name,
+			Author:               newBookItem.Author,
+			FormatLabel:          formatLabel,
+			AddedOnLabel:         app.Localizer.Localize("added_on"),
+			AdditionDate:         newBookItem.AdditionDate.Format("2006-01-02"),
+			Overview:             newBookItem.Overview,
+			IncludeItemOverviews: displayBookOverviews,
+			MediaURL:             getMediaURL(itemServerURL(jellyfinParsedURL, newBookItem.ServerURL), newBookItem.ID),
+		})
+	}
+
+	return newBooksData
+}
+
+func getUpgradedMovieTemplateDataFromSortedItems(
+	upgradedJellyfinMoviesSorted []jellyfin.MovieItem,
+	app *app.ApplicationContext,
+) []upgradedMovieItemTemplateData {
+	jellyfinParsedURL, _ := url.Parse(app.Config.EmailTemplate.JellyfinURL)
+	upgradedMoviesData := []upgradedMovieItemTemplateData{}
+
+	for i, upgradedMovieItem := range upgradedJellyfinMoviesSorted {
+		if app.Config.EmailTemplate.MaxDisplayedItems != 0 && i >= app.Config.EmailTemplate.MaxDisplayedItems {
+			app.Logger.Debug(
+				"MaxDisplayedItems setting reached. Next new items will be ignored.",
+				zap.Int("MaxDisplayedItems", app.Config.EmailTemplate.MaxDisplayedItems),
+				zap.Int("upgradedJellyfinMoviesSorted count", len(upgradedJellyfinMoviesSorted)),
+				zap.String("type", "upgraded movies"),
+			)
+			break
+		}
+		upgradedMoviesData = append(upgradedMoviesData, upgradedMovieItemTemplateData{
+			PosterURL:       upgradedMovieItem.PosterURL,
+			Name:            upgradedMovieItem.Name,
+			QualityLabel:    upgradedMovieItem.PreviousQuality.String() + " → " + upgradedMovieItem.Quality.String(),
+			UpgradedOnLabel: app.Localizer.Localize("upgraded_on"),
+			UpgradeDate:     upgradedMovieItem.AdditionDate.Format("2006-01-02"),
+			MediaURL:        getMediaURL(itemServerURL(jellyfinParsedURL, upgradedMovieItem.ServerURL), upgradedMovieItem.ID),
+		})
+	}
+
+	return upgradedMoviesData
+}
+
+// getRemovedItemTemplateDataFromSortedItems returns the template data of the removed movies and series.
+// They have no poster nor link, because they are not in Jellyfin anymore.
+func getRemovedItemTemplateDataFromSortedItems(
+	removedItemsSorted []jellyfin.RemovedItem,
+	app *app.ApplicationContext,
+) []removedItemTemplateData {
+	removedItemsData := []removedItemTemplateData{}
+
+	for i, removedItem := range removedItemsSorted {
+		if app.Config.EmailTemplate.MaxDisplayedItems != 0 && i >= app.Config.EmailTemplate.MaxDisplayedItems {
+			app.Logger.Debug(
+				"MaxDisplayedItems setting reached. Next new items will be ignored.",
+				zap.Int("MaxDisplayedItems", app.Config.EmailTemplate.MaxDisplayedItems),
+				zap.Int("removedItemsSorted count", len(removedItemsSorted)),
+				zap.String("type", "removed media"),
+			)
+			break
+		}
+		typeLabel := app.Localizer.LocalizeWithPlural("movies", 1)
+		if removedItem.ItemType == string(jellyfinAPI.BASEITEMKIND_SERIES) {
+			typeLabel = app.Localizer.Localize("series")
+		}
+		removedItemsData = append(removedItemsData, removedItemTemplateData{
+			Name:           removedItem.Name,
+			ProductionYear: formatProductionYear(removedItem.ProductionYear),
+			TypeLabel:      typeLabel,
+		})
+	}
+
+	return removedItemsData
+}
+
 func buildNewMediaTemplateData(
 	newJellyfinMovies *[]jellyfin.MovieItem,
 	newJellyfinSeries *[]jellyfin.NewlyAddedSeriesItem,
+	newJellyfinMusicAlbums *[]jellyfin.MusicAlbumItem,
+	newJellyfinBooks *[]jellyfin.BookItem,
+	upgradedJellyfinMovies *[]jellyfin.MovieItem,
+	removedJellyfinItems *[]jellyfin.RemovedItem,
 	movieCount int32,
 	episodesCount int32,
 	app *app.ApplicationContext) (*newMediaTemplateData, error) {
@@ -505,6 +828,18 @@ func buildNewMediaTemplateData(
 	newJellyfinSeriesSorted := sortJellyfinNewSeriesItems(newJellyfinSeries, app)
 	newSeriesData := getNewSerieTemplatesDataFromSortedItems(newJellyfinSeriesSorted, app)
 
+	newJellyfinMusicAlbumsSorted := sortJellyfinNewMusicAlbums(newJellyfinMusicAlbums, app)
+	newMusicData := getNewMusicTemplateDataFromSortedItems(newJellyfinMusicAlbumsSorted, app)
+
+	newJellyfinBooksSorted := sortJellyfinNewBooks(newJellyfinBooks, app)
+	newBooksData := getNewBookTemplateDataFromSortedItems(newJellyfinBooksSorted, app)
+
+	upgradedJellyfinMoviesSorted := sortJellyfinNewMovies(upgradedJellyfinMovies, app)
+	upgradedMoviesData := getUpgradedMovieTemplateDataFromSortedItems(upgradedJellyfinMoviesSorted, app)
+
+	// Removed items are already sorted by name, see jellyfin.FindRemovedItems
+	removedItemsData := getRemovedItemTemplateDataFromSortedItems(*removedJellyfinItems, app)
+
 	title, err := BuildEmailTitleWithPlaceholders(
 		app.Config.EmailTemplate.Title,
 		app.Config.Jellyfin.ObservedPeriodDays,
@@ -524,33 +859,49 @@ func buildNewMediaTemplateData(
 	}
 
 	data := newMediaTemplateData{
-		HTMLLang:                         app.Config.EmailTemplate.Language,
-		HTMLDir:                          htmlDir,
-		Title:                            title,
-		Subtitle:                         subtitle,
-		JellyfinURL:                      app.Config.EmailTemplate.JellyfinURL,
-		DiscoverNowLabel:                 app.Localizer.Localize("discover_now"),
-		DisplayNewMovies:                 len(newMoviesData) > 0,
-		NewFilmLabel:                     app.Localizer.Localize("new_film"),
-		NewMovies:                        newMoviesData,
-		RemainingMoviesNotDisplayedCount: len(newJellyfinMoviesSorted) - len(newMoviesData),
-		DisplayNewSeries:                 len(newSeriesData) > 0,
-		NewSeriesLabel:                   app.Localizer.Localize("new_tvs"),
-		NewSeries:                        newSeriesData,
-		RemainingSeriesNotDisplayedCount: len(newJellyfinSeriesSorted) - len(newSeriesData),
-		CurrentlyAvailableLabel:          app.Localizer.Localize("currently_available"),
-		MoviesCount:                      strconv.Itoa(int(movieCount)),
-		SeriesCount:                      strconv.Itoa(int(episodesCount)),
-		MoviesLabel:                      app.Localizer.LocalizeWithPlural("movies", int(movieCount)),
-		SeriesLabel:                      app.Localizer.LocalizeWithPlural("episode", int(episodesCount)),
-		FooterLabel:                      buildFooterLabel(app),
-		FooterProjectLinkLabel:           "Jellyfin Newsletter",
-		FooterOpenSourceProjectLabel:     app.Localizer.Localize("footer_project_open_source"),
-		FooterDevelopedByLabel:           app.Localizer.Localize("footer_developed_with_hearth"),
-		FooterLicenceAndCopyright:        app.Localizer.Localize("license_and_copyright"),
-		AndLocalized:                     app.Localizer.Localize("and"),
-		TheContributorsLabel:             app.Localizer.Localize("the_contributors"),
-		AndMoreTitlesPrefixLabel:         app.Localizer.Localize("and_more_titles_prefix_label"),
+		HTMLLang:                                 app.Config.EmailTemplate.Language,
+		HTMLDir:                                  htmlDir,
+		Title:                                    title,
+		Subtitle:                                 subtitle,
+		JellyfinURL:                              app.Config.EmailTemplate.JellyfinURL,
+		DiscoverNowLabel:                         app.Localizer.Localize("discover_now"),
+		DisplayNewMovies:                         len(newMoviesData) > 0,
+		NewFilmLabel:                             app.Localizer.Localize("new_film"),
+		NewMovies:                                newMoviesData,
+		RemainingMoviesNotDisplayedCount:         len(newJellyfinMoviesSorted) - len(newMoviesData),
+		DisplayNewSeries:                         len(newSeriesData) > 0,
+		NewSeriesLabel:                           app.Localizer.Localize("new_tvs"),
+		NewSeries:                                newSeriesData,
+		RemainingSeriesNotDisplayedCount:         len(newJellyfinSeriesSorted) - len(newSeriesData),
+		DisplayNewMusic:                          len(newMusicData) > 0,
+		NewMusicLabel:                            app.Localizer.Localize("new_music"),
+		NewMusic:                                 newMusicData,
+		RemainingMusicNotDisplayedCount:          len(newJellyfinMusicAlbumsSorted) - len(newMusicData),
+		DisplayNewBooks:                          len(newBooksData) > 0,
+		NewBooksLabel:                            app.Localizer.Localize("new_books"),
+		NewBooks:                                 newBooksData,
+		RemainingBooksNotDisplayedCount:          len(newJellyfinBooksSorted) - len(newBooksData),
+		DisplayUpgradedMovies:                    len(upgradedMoviesData) > 0,
+		UpgradedMoviesLabel:                      app.Localizer.Localize("upgraded_movies"),
+		UpgradedMovies:                           upgradedMoviesData,
+		RemainingUpgradedMoviesNotDisplayedCount: len(upgradedJellyfinMoviesSorted) - len(upgradedMoviesData),
+		DisplayRemovedMedia:                      len(removedItemsData) > 0,
+		RemovedMediaLabel:                        app.Localizer.Localize("removed_media"),
+		RemovedMedia:                             removedItemsData,
+		RemainingRemovedMediaNotDisplayedCount:   len(*removedJellyfinItems) - len(removedItemsData),
+		CurrentlyAvailableLabel:                  app.Localizer.Localize("currently_available"),
+		MoviesCount:                              strconv.Itoa(int(movieCount)),
+		SeriesCount:                              strconv.Itoa(int(episodesCount)),
+		MoviesLabel:                              app.Localizer.LocalizeWithPlural("movies", int(movieCount)),
+		SeriesLabel:                              app.Localizer.LocalizeWithPlural("episode", int(episodesCount)),
+		FooterLabel:                              buildFooterLabel(app),
+		FooterProjectLinkLabel:                   "Jellyfin Newsletter",
+		FooterOpenSourceProjectLabel:             app.Localizer.Localize("footer_project_open_source"),
+		FooterDevelopedByLabel:                   app.Localizer.Localize("footer_developed_with_hearth"),
+		FooterLicenceAndCopyright:                app.Localizer.Localize("license_and_copyright"),
+		AndLocalized:                             app.Localizer.Localize("and"),
+		TheContributorsLabel:                     app.Localizer.Localize("the_contributors"),
+		AndMoreTitlesPrefixLabel:                 app.Localizer.Localize("and_more_titles_prefix_label"),
 		AndMoreTitlesSuffixLabelSeries: app.Localizer.LocalizeWithPlural(
 			"and_more_titles_suffix_label",
 			len(newJellyfinSeriesSorted)-len(newSeriesData),
@@ -559,6 +910,22 @@ func buildNewMediaTemplateData(
 			"and_more_titles_suffix_label",
 			len(newJellyfinMoviesSorted)-len(newMoviesData),
 		),
+		AndMoreTitlesSuffixLabelMusic: app.Localizer.LocalizeWithPlural(
+			"and_more_titles_suffix_label",
+			len(newJellyfinMusicAlbumsSorted)-len(newMusicData),
+		),
+		AndMoreTitlesSuffixLabelBooks: app.Localizer.LocalizeWithPlural(
+			"and_more_titles_suffix_label",
+			len(newJellyfinBooksSorted)-len(newBooksData),
+		),
+		AndMoreTitlesSuffixLabelUpgradedMovies: app.Localizer.LocalizeWithPlural(
+			"and_more_titles_suffix_label",
+			len(upgradedJellyfinMoviesSorted)-len(upgradedMoviesData),
+		),
+		AndMoreTitlesSuffixLabelRemovedMedia: app.Localizer.LocalizeWithPlural(
+			"and_more_titles_suffix_label",
+			len(*removedJellyfinItems)-len(removedItemsData),
+		),
 	}
 	return &data, nil
 }
@@ -566,6 +933,10 @@ func buildNewMediaTemplateData(
 func BuildNewMediaEmailHTML(
 	newMovies *[]jellyfin.MovieItem,
 	newSeries *[]jellyfin.NewlyAddedSeriesItem,
+	newMusicAlbums *[]jellyfin.MusicAlbumItem,
+	newBooks *[]jellyfin.BookItem,
+	upgradedMovies *[]jellyfin.MovieItem,
+	removedItems *[]jellyfin.RemovedItem,
 	movieCount int32,
 	episodesCount int32,
 	app *app.ApplicationContext,
@@ -577,7 +948,17 @@ func BuildNewMediaEmailHTML(
 		return "", err
 	}
 
-	tmplData, err := buildNewMediaTemplateData(newMovies, newSeries, movieCount, episodesCount, app)
+	tmplData, err := buildNewMediaTemplateData(
+		newMovies,
+		newSeries,
+		newMusicAlbums,
+		newBooks,
+		upgradedMovies,
+		removedItems,
+		movieCount,
+		episodesCount,
+		app,
+	)
 	if err != nil {
 		return "", err
 	}
@@ -4,6 +4,7 @@ import (
 	"html"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -23,13 +24,21 @@ import (
 func getJellyfinNewMovies() []jellyfin.MovieItem {
 	return []jellyfin.MovieItem{
 		{
-			ID:             "fd9416da9026421995b40dae418d2b5d",
-			Name:           "Oppenheimer",
-			AdditionDate:   new(time.Date(2026, 01, 02, 01, 01, 0, 0, time.UTC)),
-			TMDBId:         "1273",
-			ProductionYear: int32(2023),
-			Overview:       "The story of J. Robert Oppenheimer's role in the development of the atomic bomb during World War II.",
-			PosterURL:      "https://image.tmdb.org/t/p/w500/8Gxv8gSFCU0XGDykEGv7zR1n2ua.jpg",
+			ID:                "fd9416da9026421995b40dae418d2b5d",
+			Name:              "Oppenheimer",
+			AdditionDate:      new(time.Date(2026, 01, 02, 01, 01, 0, 0, time.UTC)),
+			TMDBId:            "1273",
+			ProductionYear:    int32(2023),
+			Overview:          "The story of J. Robert Oppenheimer's role in the development of the atomic bomb during World War II.",
+			PosterURL:         "https://image.tmdb.org/t/p/w500/8Gxv8gSFCU0XGDykEGv7zR1n2ua.jpg",
+			Genres:            []string{"Drama", "History"},
+			RuntimeMinutes:    181,
+			CommunityRating:   8.06,
+			CriticRating:      93,
+			OfficialRating:    "R",
+			AudioLanguages:    []string{"eng", "fre"},
+			SubtitleLanguages: []string{"fre", "ger"},
+			Quality:           jellyfin.MediaQuality{Resolution: "4K", VideoRange: "HDR10", Codec: "HEVC"},
 		},
 		{
 			ID:             "7dcf7149f71046d5a50c626e3486259b",
@@ -43,6 +52,51 @@ func getJellyfinNewMovies() []jellyfin.MovieItem {
 	}
 }
 
+// getExpectedNewSeasons returns the NewSeasons template data of the series of getJellyfinNewSeriesItems.
+func getExpectedNewSeasons(seriesName string) []newSeasonTemplateData {
+	switch seriesName {
+	case "Stranger Things":
+		return []newSeasonTemplateData{
+			{
+				SeasonLabel: "S01",
+				Name:        "Season 1",
+				Episodes:    []newEpisodeTemplateData{{EpisodeLabel: "E05", Name: "Episode 5"}},
+			},
+			{
+				SeasonLabel: "S02",
+				Name:        "Season 2",
+				Episodes:    []newEpisodeTemplateData{{EpisodeLabel: "E10", Name: "Episode 10"}},
+			},
+		}
+	case "Family Guy":
+		return []newSeasonTemplateData{
+			{
+				SeasonLabel: "S24",
+				Name:        "Season 24",
+				Episodes: []newEpisodeTemplateData{
+					{EpisodeLabel: "E01", Name: "Episode 1", AirDate: "2025-10-12"},
+					{EpisodeLabel: "E02", Name: "Episode 2"},
+					{EpisodeLabel: "E03", Name: "Episode 3"},
+					{EpisodeLabel: "E07", Name: "Episode 7"},
+				},
+			},
+		}
+	case "How I Met Your Mother":
+		return []newSeasonTemplateData{
+			{
+				SeasonLabel: "S09",
+				Name:        "Season 9",
+				Overview:    "The final season of How I Met Your Mother.",
+				IsSeasonNew: true,
+				Episodes:    []newEpisodeTemplateData{},
+			},
+		}
+	default:
+		// Whole new series
+		return []newSeasonTemplateData{}
+	}
+}
+
 func getJellyfinNewSeriesItems() []jellyfin.NewlyAddedSeriesItem {
 	return []jellyfin.NewlyAddedSeriesItem{
 		{
@@ -56,6 +110,7 @@ func getJellyfinNewSeriesItems() []jellyfin.NewlyAddedSeriesItem {
 					AdditionDate: time.Date(2026, 01, 06, 01, 01, 0, 0, time.UTC),
 					Episodes:     nil,
 					IsSeasonNew:  true,
+					Overview:     "The final season of How I Met Your Mother.",
 				},
 			},
 			TMDBId:         "9372",
@@ -116,6 +171,7 @@ func getJellyfinNewSeriesItems() []jellyfin.NewlyAddedSeriesItem {
 							Name:          "Episode 1",
 							AdditionDate:  time.Date(2026, 01, 05, 01, 01, 0, 0, time.UTC),
 							EpisodeNumber: int32(1),
+							AirDate:       time.Date(2025, 10, 12, 0, 0, 0, 0, time.UTC),
 						},
 						"3140d8050c1b42689ee11acca7ba565a": {
 							Name:          "Episode 2",
@@ -156,6 +212,72 @@ func getJellyfinNewSeriesItems() []jellyfin.NewlyAddedSeriesItem {
 	}
 }
 
+func getJellyfinNewMusicAlbums() []jellyfin.MusicAlbumItem {
+	return []jellyfin.MusicAlbumItem{
+		{
+			ID:             "5b1f3e0c8a2d4b6e9f7a1c3d5e7f9a1b",
+			Name:           "Random Access Memories",
+			AlbumArtist:    "Daft Punk",
+			AdditionDate:   time.Date(2026, 01, 8, 01, 01, 0, 0, time.UTC),
+			ProductionYear: int32(2013),
+			ImageURL:       "https://jellyfin.example.com/Items/5b1f3e0c8a2d4b6e9f7a1c3d5e7f9a1b/Images/Primary?maxWidth=400&tag=b2",
+		},
+		{
+			ID:             "0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e",
+			Name:           "Abbey Road",
+			AlbumArtist:    "The Beatles",
+			AdditionDate:   time.Date(2026, 01, 7, 01, 01, 0, 0, time.UTC),
+			ProductionYear: int32(1969),
+			ImageURL:       "https://jellyfin.example.com/Items/0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e/Images/Primary?maxWidth=400&tag=a1",
+		},
+	}
+}
+
+func getJellyfinRemovedItems() []jellyfin.RemovedItem {
+	return []jellyfin.RemovedItem{
+		{Name: "Fargo", ItemType: "Series", ProductionYear: 2014},
+		{Name: "Oppenheimer", ItemType: "Movie", ProductionYear: 2023},
+	}
+}
+
+func getJellyfinUpgradedMovies() []jellyfin.MovieItem {
+	return []jellyfin.MovieItem{
+		{
+			ID:              "4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f",
+			Name:            "Interstellar",
+			AdditionDate:    new(time.Date(2026, 01, 11, 01, 01, 0, 0, time.UTC)),
+			ProductionYear:  int32(2014),
+			PosterURL:       "https://jellyfin.example.com/Items/4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f/Images/Primary?maxWidth=400&tag=e5",
+			Quality:         jellyfin.MediaQuality{Resolution: "4K", VideoRange: "HDR10", Codec: "HEVC"},
+			PreviousQuality: jellyfin.MediaQuality{Resolution: "1080p", VideoRange: "SDR", Codec: "H264"},
+		},
+	}
+}
+
+func getJellyfinNewBooks() []jellyfin.BookItem {
+	return []jellyfin.BookItem{
+		{
+			ID:             "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b",
+			Name:           "The Hobbit",
+			Author:         "J. R. R. Tolkien",
+			AdditionDate:   time.Date(2026, 01, 10, 01, 01, 0, 0, time.UTC),
+			ProductionYear: int32(1937),
+			Overview:       "Bilbo Baggins is swept into a quest to reclaim the lost Dwarf Kingdom of Erebor from the fearsome dragon Smaug.",
+			ImageURL:       "https://jellyfin.example.com/Items/9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b/Images/Primary?maxWidth=400&tag=d4",
+		},
+		{
+			ID:             "1f2e3d4c5b6a79881f2e3d4c5b6a7988",
+			Name:           "Dune",
+			Author:         "Frank Herbert",
+			IsAudioBook:    true,
+			AdditionDate:   time.Date(2026, 01, 9, 01, 01, 0, 0, time.UTC),
+			ProductionYear: int32(1965),
+			Overview:       "Paul Atreides and his family take over the desert planet Arrakis, the only source of the most valuable substance in the universe.",
+			ImageURL:       "https://jellyfin.example.com/Items/1f2e3d4c5b6a79881f2e3d4c5b6a7988/Images/Primary?maxWidth=400&tag=c3",
+		},
+	}
+}
+
 func getExpectedNewMediaTemplateData() newMediaTemplateData {
 	title := "New items from " + time.Now().
 		AddDate(0, 0, -30).
@@ -166,22 +288,37 @@ func getExpectedNewMediaTemplateData() newMediaTemplateData {
 
 	newMovies := []newMovieItemTemplateData{
 		{
-			PosterURL:            "https://image.tmdb.org/t/p/w500/oZNPzxqM2s5DyVWab09NTQScDQt.jpg",
-			Name:                 "Star Wars: Episode II - Attack of the Clones",
-			AdditionDate:         "2026-01-01",
-			Overview:             "Following an assassination attempt on Senator Padmé Amidala, Jedi Knights Anakin Skywalker and Obi-Wan Kenobi investigate a mysterious plot into the heart of the Separatist movement and the beginning of the Clone Wars.",
-			AddedOnLabel:         "Added on",
-			IncludeItemOverviews: true,
-			MediaURL:             "https://jellyfin.example.com/web/#/details?id=7dcf7149f71046d5a50c626e3486259b",
+			PosterURL:              "https://image.tmdb.org/t/p/w500/oZNPzxqM2s5DyVWab09NTQScDQt.jpg",
+			Name:                   "Star Wars: Episode II - Attack of the Clones",
+			AdditionDate:           "2026-01-01",
+			Overview:               "Following an assassination attempt on Senator Padmé Amidala, Jedi Knights Anakin Skywalker and Obi-Wan Kenobi investigate a mysterious plot into the heart of the Separatist movement and the beginning of the Clone Wars.",
+			AddedOnLabel:           "Added on",
+			IncludeItemOverviews:   true,
+			MediaURL:               "https://jellyfin.example.com/web/#/details?id=7dcf7149f71046d5a50c626e3486259b",
+			ProductionYear:         "2025",
+			AudioLanguagesLabel:    "Audio:",
+			SubtitleLanguagesLabel: "Subtitles:",
 		},
 		{
-			PosterURL:            "https://image.tmdb.org/t/p/w500/8Gxv8gSFCU0XGDykEGv7zR1n2ua.jpg",
-			Name:                 "Oppenheimer",
-			AdditionDate:         "2026-01-02",
-			Overview:             "The story of J. Robert Oppenheimer's role in the development of the atomic bomb during World War II.",
-			AddedOnLabel:         "Added on",
-			IncludeItemOverviews: true,
-			MediaURL:             "https://jellyfin.example.com/web/#/details?id=fd9416da9026421995b40dae418d2b5d",
+			PosterURL:              "https://image.tmdb.org/t/p/w500/8Gxv8gSFCU0XGDykEGv7zR1n2ua.jpg",
+			Name:                   "Oppenheimer",
+			AdditionDate:           "2026-01-02",
+			Overview:               "The story of J. Robert Oppenheimer's role in the development of the atomic bomb during World War II.",
+			AddedOnLabel:           "Added on",
+			IncludeItemOverviews:   true,
+			MediaURL:               "https://jellyfin.example.com/web/#/details?id=fd9416da9026421995b40dae418d2b5d",
+			ProductionYear:         "2023",
+			Genres:                 "Drama, History",
+			Runtime:                "181 min",
+			OfficialRating:         "R",
+			CommunityRating:        "8.1",
+			CriticRating:           "93%",
+			Resolution:             "4K",
+			VideoRange:             "HDR10",
+			AudioLanguagesLabel:    "Audio:",
+			AudioLanguages:         "English, French",
+			SubtitleLanguagesLabel: "Subtitles:",
+			SubtitleLanguages:      "French, German",
 		},
 	}
 
@@ -196,6 +333,7 @@ func getExpectedNewMediaTemplateData() newMediaTemplateData {
 			NewSeriesTitle:       "Game of thrones",
 			IncludeItemOverviews: true,
 			MediaURL:             "https://jellyfin.example.com/web/#/details?id=3d7b0576370c48d3b7c37c49f612afc9",
+			NewSeasons:           getExpectedNewSeasons("Game of thrones"),
 		},
 		{
 			// Old series, new episodes in 2 seasons
@@ -207,6 +345,7 @@ func getExpectedNewMediaTemplateData() newMediaTemplateData {
 			NewSeriesTitle:       "Stranger Things: Seasons 1-2",
 			IncludeItemOverviews: true,
 			MediaURL:             "https://jellyfin.example.com/web/#/details?id=66b82dc8d65544b3b8d75a5eaaa581cd",
+			NewSeasons:           getExpectedNewSeasons("Stranger Things"),
 		},
 		{
 			// Old series, new episodes in 1 season
@@ -218,6 +357,7 @@ func getExpectedNewMediaTemplateData() newMediaTemplateData {
 			NewSeriesTitle:       "Family Guy: Season 24, Episodes 1-3 & 7",
 			IncludeItemOverviews: true,
 			MediaURL:             "https://jellyfin.example.com/web/#/details?id=94327c537a324e7c84f45b1a6e71dd35",
+			NewSeasons:           getExpectedNewSeasons("Family Guy"),
 		},
 		{
 			// Old series, new season
@@ -229,39 +369,103 @@ func getExpectedNewMediaTemplateData() newMediaTemplateData {
 			NewSeriesTitle:       "How I Met Your Mother: Season 9",
 			IncludeItemOverviews: true,
 			MediaURL:             "https://jellyfin.example.com/web/#/details?id=c828b89264f84def88b7dc3d9072a147",
+			NewSeasons:           getExpectedNewSeasons("How I Met Your Mother"),
+		},
+	}
+
+	newMusic := []newMusicItemTemplateData{
+		{
+			ImageURL:     "https://jellyfin.example.com/Items/0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e/Images/Primary?maxWidth=400&tag=a1",
+			Name:         "Abbey Road",
+			AlbumArtist:  "The Beatles",
+			AddedOnLabel: "Added on",
+			AdditionDate: "2026-01-07",
+			MediaURL:     "https://jellyfin.example.com/web/#/details?id=0a2c4e6f8b1d3f5a7c9e2b4d6f8a0c2e",
+		},
+		{
+			ImageURL:     "https://jellyfin.example.com/Items/5b1f3e0c8a2d4b6e9f7a1c3d5e7f9a1b/Images/Primary?maxWidth=400&tag=b2",
+			Name:         "Random Access Memories",
+			AlbumArtist:  "Daft Punk",
+			AddedOnLabel: "Added on",
+			AdditionDate: "2026-01-08",
+			MediaURL:     "https://jellyfin.example.com/web/#/details?id=5b1f3e0c8a2d4b6e9f7a1c3d5e7f9a1b",
+		},
+	}
+
+	newBooks := []newBookItemTemplateData{
+		{
+			ImageURL:             "https://jellyfin.example.com/Items/1f2e3d4c5b6a79881f2e3d4c5b6a7988/Images/Primary?maxWidth=400&tag=c3",
+			Name:                 "Dune",
+			Author:               "Frank Herbert",
+			FormatLabel:          "Audiobook",
+			AddedOnLabel:         "Added on",
+			AdditionDate:         "2026-01-09",
+			Overview:             "Paul Atreides and his family take over the desert planet Arrakis, the only source of the most valuable substance in the universe.",
+			IncludeItemOverviews: true,
+			MediaURL:             "https://jellyfin.example.com/web/#/details?id=1f2e3d4c5b6a79881f2e3d4c5b6a7988",
+		},
+		{
+			ImageURL:             "https://jellyfin.example.com/Items/9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b/Images/Primary?maxWidth=400&tag=d4",
+			Name:                 "The Hobbit",
+			Author:               "J. R. R. Tolkien",
+			AddedOnLabel:         "Added on",
+			AdditionDate:         "2026-01-10",
+			Overview:             "Bilbo Baggins is swept into a quest to reclaim the lost Dwarf Kingdom of Erebor from the fearsome dragon Smaug.",
+			IncludeItemOverviews: true,
+			MediaURL:             "https://jellyfin.example.com/web/#/details?id=9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b",
 		},
 	}
 
 	return newMediaTemplateData{
-		HTMLLang:                         "en",
-		HTMLDir:                          "ltr",
-		Title:                            title,
-		Subtitle:                         subtitle,
-		JellyfinURL:                      "https://jellyfin.example.com",
-		DiscoverNowLabel:                 "Discover now",
-		DisplayNewMovies:                 true,
-		NewFilmLabel:                     "New movies:",
-		NewMovies:                        newMovies,
-		DisplayNewSeries:                 true,
-		NewSeriesLabel:                   "New shows:",
-		NewSeries:                        newSeries,
-		CurrentlyAvailableLabel:          "Currently available in Jellyfin:",
-		MoviesCount:                      strconv.Itoa(54),
-		SeriesCount:                      strconv.Itoa(1253),
-		RemainingMoviesNotDisplayedCount: 0,
-		RemainingSeriesNotDisplayedCount: 0,
-		MoviesLabel:                      "Movies",
-		SeriesLabel:                      "Episodes",
-		FooterLabel:                      "You are recieving this email because you are using seaweedbrain's Jellyfin server. If you want to stop receiving these emails, you can unsubscribe by notifying stop@example.com.",
-		FooterProjectLinkLabel:           "Jellyfin Newsletter",
-		FooterOpenSourceProjectLabel:     "is an open source project.",
-		FooterDevelopedByLabel:           "Developed with ❤️ by",
-		AndLocalized:                     "and",
-		TheContributorsLabel:             "the contributors",
-		FooterLicenceAndCopyright:        "Copyright © 2025 Nathan Stchepinsky, licensed under AGPLv3.",
-		AndMoreTitlesPrefixLabel:         "... and",
-		AndMoreTitlesSuffixLabelSeries:   "more titles!",
-		AndMoreTitlesSuffixLabelMovies:   "more titles!",
+		HTMLLang:                                 "en",
+		HTMLDir:                                  "ltr",
+		Title:                                    title,
+		Subtitle:                                 subtitle,
+		JellyfinURL:                              "https://jellyfin.example.com",
+		DiscoverNowLabel:                         "Discover now",
+		DisplayNewMovies:                         true,
+		NewFilmLabel:                             "New movies:",
+		NewMovies:                                newMovies,
+		DisplayNewSeries:                         true,
+		NewSeriesLabel:                           "New shows:",
+		NewSeries:                                newSeries,
+		DisplayNewMusic:                          true,
+		NewMusicLabel:                            "New music:",
+		NewMusic:                                 newMusic,
+		DisplayNewBooks:                          true,
+		NewBooksLabel:                            "New books:",
+		NewBooks:                                 newBooks,
+		CurrentlyAvailableLabel:                  "Currently available in Jellyfin:",
+		MoviesCount:                              strconv.Itoa(54),
+		SeriesCount:                              strconv.Itoa(1253),
+		RemainingMoviesNotDisplayedCount:         0,
+		RemainingSeriesNotDisplayedCount:         0,
+		RemainingMusicNotDisplayedCount:          0,
+		RemainingBooksNotDisplayedCount:          0,
+		DisplayUpgradedMovies:                    false,
+		UpgradedMoviesLabel:                      "Upgraded movies:",
+		UpgradedMovies:                           []upgradedMovieItemTemplateData{},
+		RemainingUpgradedMoviesNotDisplayedCount: 0,
+		DisplayRemovedMedia:                      false,
+		RemovedMediaLabel:                        "No longer available:",
+		RemovedMedia:                             []removedItemTemplateData{},
+		RemainingRemovedMediaNotDisplayedCount:   0,
+		MoviesLabel:                              "Movies",
+		SeriesLabel:                              "Episodes",
+		FooterLabel:                              "You are recieving this email because you are using seaweedbrain's Jellyfin server. If you want to stop receiving these emails, you can unsubscribe by notifying stop@example.com.",
+		FooterProjectLinkLabel:                   "Jellyfin Newsletter",
+		FooterOpenSourceProjectLabel:             "is an open source project.",
+		FooterDevelopedByLabel:                   "Developed with ❤️ by",
+		AndLocalized:                             "and",
+		TheContributorsLabel:                     "the contributors",
+		FooterLicenceAndCopyright:                "Copyright © 2025 Nathan Stchepinsky, licensed under AGPLv3.",
+		AndMoreTitlesPrefixLabel:                 "... and",
+		AndMoreTitlesSuffixLabelSeries:           "more titles!",
+		AndMoreTitlesSuffixLabelMovies:           "more titles!",
+		AndMoreTitlesSuffixLabelMusic:            "more titles!",
+		AndMoreTitlesSuffixLabelBooks:            "more titles!",
+		AndMoreTitlesSuffixLabelUpgradedMovies:   "more titles!",
+		AndMoreTitlesSuffixLabelRemovedMedia:     "more titles!",
 	}
 }
 
@@ -301,6 +505,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 		getExpectedNewMediaTemplateDataFunc func() newMediaTemplateData
 		getJellyfinNewMovies                func() []jellyfin.MovieItem
 		getJellyfinNewSeriesItems           func() []jellyfin.NewlyAddedSeriesItem
+		getJellyfinNewMusicAlbums           func() []jellyfin.MusicAlbumItem
+		getJellyfinNewBooks                 func() []jellyfin.BookItem
 		movieCount                          int
 		episodeCount                        int
 	}{
@@ -310,6 +516,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			getExpectedNewMediaTemplateDataFunc: getExpectedNewMediaTemplateData,
 			getJellyfinNewMovies:                getJellyfinNewMovies,
 			getJellyfinNewSeriesItems:           getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums:           getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:                 getJellyfinNewBooks,
 			movieCount:                          54,
 			episodeCount:                        1253,
 		},
@@ -328,10 +536,15 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 				for i := range expected.NewSeries {
 					expected.NewSeries[i].IncludeItemOverviews = false
 				}
+				for i := range expected.NewBooks {
+					expected.NewBooks[i].IncludeItemOverviews = false
+				}
 				return expected
 			},
 			getJellyfinNewMovies:      getJellyfinNewMovies,
 			getJellyfinNewSeriesItems: getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums: getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:       getJellyfinNewBooks,
 			movieCount:                54,
 			episodeCount:              1253,
 		},
@@ -350,10 +563,15 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 				for i := range expected.NewSeries {
 					expected.NewSeries[i].IncludeItemOverviews = false
 				}
+				for i := range expected.NewBooks {
+					expected.NewBooks[i].IncludeItemOverviews = false
+				}
 				return expected
 			},
 			getJellyfinNewMovies:      getJellyfinNewMovies,
 			getJellyfinNewSeriesItems: getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums: getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:       getJellyfinNewBooks,
 			movieCount:                54,
 			episodeCount:              1253,
 		},
@@ -367,6 +585,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			getExpectedNewMediaTemplateDataFunc: getExpectedNewMediaTemplateData,
 			getJellyfinNewMovies:                getJellyfinNewMovies,
 			getJellyfinNewSeriesItems:           getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums:           getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:                 getJellyfinNewBooks,
 			movieCount:                          54,
 			episodeCount:                        1253,
 		},
@@ -380,6 +600,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			getExpectedNewMediaTemplateDataFunc: getExpectedNewMediaTemplateData,
 			getJellyfinNewMovies:                getJellyfinNewMovies,
 			getJellyfinNewSeriesItems:           getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums:           getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:                 getJellyfinNewBooks,
 			movieCount:                          54,
 			episodeCount:                        1253,
 		},
@@ -393,6 +615,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			getExpectedNewMediaTemplateDataFunc: getExpectedNewMediaTemplateData,
 			getJellyfinNewMovies:                getJellyfinNewMovies,
 			getJellyfinNewSeriesItems:           getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums:           getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:                 getJellyfinNewBooks,
 			movieCount:                          54,
 			episodeCount:                        1253,
 		},
@@ -419,10 +643,14 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 				}
 				expected.NewMovies = newMovies
 				expected.NewSeries = newSeries
+				slices.Reverse(expected.NewMusic)
+				slices.Reverse(expected.NewBooks)
 				return expected
 			},
 			getJellyfinNewMovies:      getJellyfinNewMovies,
 			getJellyfinNewSeriesItems: getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums: getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:       getJellyfinNewBooks,
 			movieCount:                54,
 			episodeCount:              1253,
 		},
@@ -435,26 +663,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			},
 			getExpectedNewMediaTemplateDataFunc: func() newMediaTemplateData {
 				expected := getExpectedNewMediaTemplateData()
-				newMovies := []newMovieItemTemplateData{
-					{
-						PosterURL:            "https://image.tmdb.org/t/p/w500/8Gxv8gSFCU0XGDykEGv7zR1n2ua.jpg",
-						Name:                 "Oppenheimer",
-						AdditionDate:         "2026-01-02",
-						Overview:             "The story of J. Robert Oppenheimer's role in the development of the atomic bomb during World War II.",
-						AddedOnLabel:         "Added on",
-						IncludeItemOverviews: true,
-						MediaURL:             "https://jellyfin.example.com/web/#/details?id=fd9416da9026421995b40dae418d2b5d",
-					},
-					{
-						PosterURL:            "https://image.tmdb.org/t/p/w500/oZNPzxqM2s5DyVWab09NTQScDQt.jpg",
-						Name:                 "Star Wars: Episode II - Attack of the Clones",
-						AdditionDate:         "2026-01-01",
-						Overview:             "Following an assassination attempt on Senator Padmé Amidala, Jedi Knights Anakin Skywalker and Obi-Wan Kenobi investigate a mysterious plot into the heart of the Separatist movement and the beginning of the Clone Wars.",
-						AddedOnLabel:         "Added on",
-						IncludeItemOverviews: true,
-						MediaURL:             "https://jellyfin.example.com/web/#/details?id=7dcf7149f71046d5a50c626e3486259b",
-					},
-				}
+				// Oppenheimer, then Star Wars
+				newMovies := []newMovieItemTemplateData{expected.NewMovies[1], expected.NewMovies[0]}
 
 				newSeries := []newSeriesItemTemplateData{
 					{
@@ -467,6 +677,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "Family Guy: Season 24, Episodes 1-3 & 7",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=94327c537a324e7c84f45b1a6e71dd35",
+						NewSeasons:           getExpectedNewSeasons("Family Guy"),
 					},
 					{
 						// Whole new series
@@ -478,6 +689,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "Game of thrones",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=3d7b0576370c48d3b7c37c49f612afc9",
+						NewSeasons:           getExpectedNewSeasons("Game of thrones"),
 					},
 					{
 						// Old series, new season
@@ -489,6 +701,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "How I Met Your Mother: Season 9",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=c828b89264f84def88b7dc3d9072a147",
+						NewSeasons:           getExpectedNewSeasons("How I Met Your Mother"),
 					},
 					{
 						// Old series, new episodes in 2 seasons
@@ -500,6 +713,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "Stranger Things: Seasons 1-2",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=66b82dc8d65544b3b8d75a5eaaa581cd",
+						NewSeasons:           getExpectedNewSeasons("Stranger Things"),
 					},
 				}
 				expected.NewMovies = newMovies
@@ -508,6 +722,8 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			},
 			getJellyfinNewMovies:      getJellyfinNewMovies,
 			getJellyfinNewSeriesItems: getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums: getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:       getJellyfinNewBooks,
 			movieCount:                54,
 			episodeCount:              1253,
 		},
@@ -520,26 +736,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			},
 			getExpectedNewMediaTemplateDataFunc: func() newMediaTemplateData {
 				expected := getExpectedNewMediaTemplateData()
-				newMovies := []newMovieItemTemplateData{
-					{
-						PosterURL:            "https://image.tmdb.org/t/p/w500/oZNPzxqM2s5DyVWab09NTQScDQt.jpg",
-						Name:                 "Star Wars: Episode II - Attack of the Clones",
-						AdditionDate:         "2026-01-01",
-						Overview:             "Following an assassination attempt on Senator Padmé Amidala, Jedi Knights Anakin Skywalker and Obi-Wan Kenobi investigate a mysterious plot into the heart of the Separatist movement and the beginning of the Clone Wars.",
-						AddedOnLabel:         "Added on",
-						IncludeItemOverviews: true,
-						MediaURL:             "https://jellyfin.example.com/web/#/details?id=7dcf7149f71046d5a50c626e3486259b",
-					},
-					{
-						PosterURL:            "https://image.tmdb.org/t/p/w500/8Gxv8gSFCU0XGDykEGv7zR1n2ua.jpg",
-						Name:                 "Oppenheimer",
-						AdditionDate:         "2026-01-02",
-						Overview:             "The story of J. Robert Oppenheimer's role in the development of the atomic bomb during World War II.",
-						AddedOnLabel:         "Added on",
-						IncludeItemOverviews: true,
-						MediaURL:             "https://jellyfin.example.com/web/#/details?id=fd9416da9026421995b40dae418d2b5d",
-					},
-				}
+				newMovies := []newMovieItemTemplateData{expected.NewMovies[0], expected.NewMovies[1]}
 
 				newSeries := []newSeriesItemTemplateData{
 					{
@@ -552,6 +749,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "Stranger Things: Seasons 1-2",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=66b82dc8d65544b3b8d75a5eaaa581cd",
+						NewSeasons:           getExpectedNewSeasons("Stranger Things"),
 					},
 					{
 						// Old series, new season
@@ -563,6 +761,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "How I Met Your Mother: Season 9",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=c828b89264f84def88b7dc3d9072a147",
+						NewSeasons:           getExpectedNewSeasons("How I Met Your Mother"),
 					},
 					{
 						// Whole new series
@@ -574,6 +773,7 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "Game of thrones",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=3d7b0576370c48d3b7c37c49f612afc9",
+						NewSeasons:           getExpectedNewSeasons("Game of thrones"),
 					},
 					{
 						// Old series, new episodes in 1 season
@@ -585,14 +785,19 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 						NewSeriesTitle:       "Family Guy: Season 24, Episodes 1-3 & 7",
 						IncludeItemOverviews: true,
 						MediaURL:             "https://jellyfin.example.com/web/#/details?id=94327c537a324e7c84f45b1a6e71dd35",
+						NewSeasons:           getExpectedNewSeasons("Family Guy"),
 					},
 				}
 				expected.NewMovies = newMovies
 				expected.NewSeries = newSeries
+				slices.Reverse(expected.NewMusic)
+				slices.Reverse(expected.NewBooks)
 				return expected
 			},
 			getJellyfinNewMovies:      getJellyfinNewMovies,
 			getJellyfinNewSeriesItems: getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums: getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:       getJellyfinNewBooks,
 			movieCount:                54,
 			episodeCount:              1253,
 		},
@@ -624,10 +829,18 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 				expected.RemainingMoviesNotDisplayedCount = 1
 				expected.RemainingSeriesNotDisplayedCount = 3
 				expected.AndMoreTitlesSuffixLabelMovies = "more title!"
+				expected.NewMusic = expected.NewMusic[:1]
+				expected.RemainingMusicNotDisplayedCount = 1
+				expected.AndMoreTitlesSuffixLabelMusic = "more title!"
+				expected.NewBooks = expected.NewBooks[:1]
+				expected.RemainingBooksNotDisplayedCount = 1
+				expected.AndMoreTitlesSuffixLabelBooks = "more title!"
 				return expected
 			},
 			getJellyfinNewMovies:      getJellyfinNewMovies,
 			getJellyfinNewSeriesItems: getJellyfinNewSeriesItems,
+			getJellyfinNewMusicAlbums: getJellyfinNewMusicAlbums,
+			getJellyfinNewBooks:       getJellyfinNewBooks,
 			movieCount:                54,
 			episodeCount:              1253,
 		},
@@ -639,9 +852,15 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 			expectedTemplateData := test.getExpectedNewMediaTemplateDataFunc()
 			newSeries := test.getJellyfinNewSeriesItems()
 			newMovies := test.getJellyfinNewMovies()
+			newMusicAlbums := test.getJellyfinNewMusicAlbums()
+			newBooks := test.getJellyfinNewBooks()
 			templateData, err := buildNewMediaTemplateData(
 				&newMovies,
 				&newSeries,
+				&newMusicAlbums,
+				&newBooks,
+				&[]jellyfin.MovieItem{},
+				&[]jellyfin.RemovedItem{},
 				int32(test.movieCount),
 				int32(test.episodeCount),
 				app,
@@ -654,6 +873,95 @@ func TestBuildNewMediaTemplateData(t *testing.T) {
 	}
 }
 
+func TestBuildNewMediaTemplateDataWithUpgradedMovies(t *testing.T) {
+	app, recordedLogs := getAppContext()
+	newMovies := getJellyfinNewMovies()
+	newSeries := getJellyfinNewSeriesItems()
+	newMusicAlbums := getJellyfinNewMusicAlbums()
+	newBooks := getJellyfinNewBooks()
+	upgradedMovies := getJellyfinUpgradedMovies()
+	expectedTemplateData := getExpectedNewMediaTemplateData()
+	expectedTemplateData.DisplayUpgradedMovies = true
+	expectedTemplateData.UpgradedMovies = []upgradedMovieItemTemplateData{
+		{
+			PosterURL:       "https://jellyfin.example.com/Items/4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f/Images/Primary?maxWidth=400&tag=e5",
+			Name:            "Interstellar",
+			QualityLabel:    "1080p H264 → 4K HDR10 HEVC",
+			UpgradedOnLabel: "Upgraded on",
+			UpgradeDate:     "2026-01-11",
+			MediaURL:        "https://jellyfin.example.com/web/#/details?id=4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f",
+		},
+	}
+
+	templateData, err := buildNewMediaTemplateData(
+		&newMovies,
+		&newSeries,
+		&newMusicAlbums,
+		&newBooks,
+		&upgradedMovies,
+		&[]jellyfin.RemovedItem{},
+		54,
+		1253,
+		app,
+	)
+
+	require.NoError(t, err)
+	assert.Empty(t, recordedLogs)
+	assert.Equal(t, expectedTemplateData, *templateData)
+}
+
+func TestBuildNewMediaTemplateDataWithRemovedMedia(t *testing.T) {
+	app, recordedLogs := getAppContext()
+	newMovies := getJellyfinNewMovies()
+	newSeries := getJellyfinNewSeriesItems()
+	newMusicAlbums := getJellyfinNewMusicAlbums()
+	newBooks := getJellyfinNewBooks()
+	removedItems := getJellyfinRemovedItems()
+	expectedTemplateData := getExpectedNewMediaTemplateData()
+	expectedTemplateData.DisplayRemovedMedia = true
+	expectedTemplateData.RemovedMedia = []removedItemTemplateData{
+		{Name: "Fargo", ProductionYear: "2014", TypeLabel: "Series"},
+		{Name: "Oppenheimer", ProductionYear: "2023", TypeLabel: "Movie"},
+	}
+
+	templateData, err := buildNewMediaTemplateData(
+		&newMovies,
+		&newSeries,
+		&newMusicAlbums,
+		&newBooks,
+		&[]jellyfin.MovieItem{},
+		&removedItems,
+		54,
+		1253,
+		app,
+	)
+
+	require.NoError(t, err)
+	assert.Empty(t, recordedLogs)
+	assert.Equal(t, expectedTemplateData, *templateData)
+}
+
+func TestMediaURLOfItemsOfAnotherServer(t *testing.T) {
+	app, _ := getAppContext()
+	additionDate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	movies := []jellyfin.MovieItem{
+		{ID: "home-movie", AdditionDate: &additionDate},
+		{ID: "cabin-movie", AdditionDate: &additionDate, ServerURL: "https://cabin.example.com"},
+	}
+	series := []jellyfin.NewlyAddedSeriesItem{
+		{SeriesID: "cabin-series", AdditionDate: additionDate, IsSeriesNew: true, ServerURL: "https://cabin.example.com"},
+	}
+
+	moviesData := getNewMovieTemplateDataFromSortedNewItems(movies, app)
+	seriesData := getNewSerieTemplatesDataFromSortedItems(series, app)
+
+	require.Len(t, moviesData, 2)
+	assert.Equal(t, "https://jellyfin.example.com/web/#/details?id=home-movie", moviesData[0].MediaURL)
+	assert.Equal(t, "https://cabin.example.com/web/#/details?id=cabin-movie", moviesData[1].MediaURL)
+	require.Len(t, seriesData, 1)
+	assert.Equal(t, "https://cabin.example.com/web/#/details?id=cabin-series", seriesData[0].MediaURL)
+}
+
 func TestCheckIfValidThemeIsAvailable(t *testing.T) {
 	validThemeList := []string{"classic"}
 	app, _ := getAppContext()
@@ -674,12 +982,23 @@ func TestCheckIfUnknownThemeIsAvailable(t *testing.T) {
 func TestBuildNewMediaEmailHTML(t *testing.T) {
 	newMovies := getJellyfinNewMovies()
 	newSeries := getJellyfinNewSeriesItems()
+	newMusicAlbums := getJellyfinNewMusicAlbums()
+	newBooks := getJellyfinNewBooks()
+	upgradedMovies := getJellyfinUpgradedMovies()
+	removedItems := getJellyfinRemovedItems()
 	movieCount := int32(54)
 	seriesCount := int32(1253)
 	app, _ := getAppContext()
 	expectedTemplateData := getExpectedNewMediaTemplateData()
 
-	escapedHTML, err := BuildNewMediaEmailHTML(&newMovies, &newSeries, movieCount, seriesCount, app)
+	escapedHTML, err := BuildNewMediaEmailHTML(
+		&newMovies,
+		&newSeries,
+		&newMusicAlbums,
+		&newBooks,
+		&upgradedMovies,
+		&removedItems,
+		movieCount, seriesCount, app)
 	unescapedHTML := html.UnescapeString(escapedHTML)
 
 	require.NoError(t, err)
@@ -721,11 +1040,51 @@ func TestBuildNewMediaEmailHTML(t *testing.T) {
 		assert.Contains(t, unescapedHTML, series.Overview)
 		assert.Contains(t, unescapedHTML, series.NewSeriesTitle)
 	}
+
+	assert.Contains(t, unescapedHTML, expectedTemplateData.NewMusicLabel)
+	for _, album := range expectedTemplateData.NewMusic {
+		assert.Contains(t, unescapedHTML, album.ImageURL)
+		assert.Contains(t, unescapedHTML, album.Name)
+		assert.Contains(t, unescapedHTML, album.AlbumArtist)
+		assert.Contains(t, unescapedHTML, album.AdditionDate)
+		assert.Contains(t, unescapedHTML, album.MediaURL)
+	}
+
+	assert.Contains(t, unescapedHTML, expectedTemplateData.NewBooksLabel)
+	for _, book := range expectedTemplateData.NewBooks {
+		assert.Contains(t, unescapedHTML, book.ImageURL)
+		assert.Contains(t, unescapedHTML, book.Name)
+		assert.Contains(t, unescapedHTML, book.Author)
+		assert.Contains(t, unescapedHTML, book.AdditionDate)
+		assert.Contains(t, unescapedHTML, book.Overview)
+	}
+	assert.Contains(t, regexp.MustCompile(`\s+`).ReplaceAllString(unescapedHTML, " "), "Frank Herbert (Audiobook)")
+
+	normalizedHTML := regexp.MustCompile(`\s+`).ReplaceAllString(unescapedHTML, " ")
+	assert.Contains(t, normalizedHTML, "Drama, History")
+	assert.Contains(t, normalizedHTML, "181 min")
+	assert.Contains(t, normalizedHTML, "★ 8.1")
+	assert.Contains(t, normalizedHTML, "🍅 93%")
+	assert.Contains(t, normalizedHTML, ">HDR10<")
+	assert.Contains(t, normalizedHTML, "Audio: English, French")
+	assert.Contains(t, normalizedHTML, "Subtitles: French, German")
+
+	assert.Contains(t, unescapedHTML, expectedTemplateData.UpgradedMoviesLabel)
+	assert.Contains(t, unescapedHTML, "Interstellar")
+	assert.Contains(t, unescapedHTML, "1080p H264 → 4K HDR10 HEVC")
+	assert.Contains(t, unescapedHTML, "Upgraded on")
+	assert.Contains(t, unescapedHTML, "2026-01-11")
+
+	assert.Contains(t, unescapedHTML, expectedTemplateData.RemovedMediaLabel)
+	assert.Contains(t, normalizedHTML, "Fargo (2014) · Series")
+	assert.Contains(t, normalizedHTML, "Oppenheimer (2023) · Movie")
 }
 
 func TestBuildNewMediaEmailHTMLWithCustomDirTheme(t *testing.T) {
 	newMovies := getJellyfinNewMovies()
 	newSeries := getJellyfinNewSeriesItems()
+	newMusicAlbums := getJellyfinNewMusicAlbums()
+	newBooks := getJellyfinNewBooks()
 	movieCount := int32(1254)
 	seriesCount := int32(1253)
 	app, _ := getAppContext()
@@ -734,7 +1093,14 @@ func TestBuildNewMediaEmailHTMLWithCustomDirTheme(t *testing.T) {
 	app.Config.EmailTemplate.Theme = "custom_theme1"
 	expectedTemplateData := getExpectedNewMediaTemplateData()
 
-	escapedHTML, err := BuildNewMediaEmailHTML(&newMovies, &newSeries, movieCount, seriesCount, app)
+	escapedHTML, err := BuildNewMediaEmailHTML(
+		&newMovies,
+		&newSeries,
+		&newMusicAlbums,
+		&newBooks,
+		&[]jellyfin.MovieItem{},
+		&[]jellyfin.RemovedItem{},
+		movieCount, seriesCount, app)
 	unescapedHTML := html.UnescapeString(escapedHTML)
 
 	// collapse multi spaces
@@ -787,6 +1153,8 @@ func TestBuildNewMediaEmailHTMLWithCustomDirTheme(t *testing.T) {
 func TestBuildNewMediaEmailHTMLWithThemeFallBack(t *testing.T) {
 	newMovies := getJellyfinNewMovies()
 	newSeries := getJellyfinNewSeriesItems()
+	newMusicAlbums := getJellyfinNewMusicAlbums()
+	newBooks := getJellyfinNewBooks()
 	movieCount := int32(54)
 	seriesCount := int32(1253)
 	app, _ := getAppContext()
@@ -795,7 +1163,14 @@ func TestBuildNewMediaEmailHTMLWithThemeFallBack(t *testing.T) {
 	app.Config.EmailTemplate.ThemesDirFS = &dirFS
 	app.Config.EmailTemplate.Theme = "classic"
 
-	escapedHTML, err := BuildNewMediaEmailHTML(&newMovies, &newSeries, movieCount, seriesCount, app)
+	escapedHTML, err := BuildNewMediaEmailHTML(
+		&newMovies,
+		&newSeries,
+		&newMusicAlbums,
+		&newBooks,
+		&[]jellyfin.MovieItem{},
+		&[]jellyfin.RemovedItem{},
+		movieCount, seriesCount, app)
 	unescapedHTML := html.UnescapeString(escapedHTML)
 
 	require.NoError(t, err)
@@ -805,13 +1180,22 @@ func TestBuildNewMediaEmailHTMLWithThemeFallBack(t *testing.T) {
 func TestBuildNewMediaEmailHTMLWithMaxDisplayedItemsLimitReached(t *testing.T) {
 	newMovies := getJellyfinNewMovies()
 	newSeries := getJellyfinNewSeriesItems()
+	newMusicAlbums := getJellyfinNewMusicAlbums()
+	newBooks := getJellyfinNewBooks()
 	movieCount := int32(54)
 	seriesCount := int32(1253)
 	app, _ := getAppContext()
 	app.Config.EmailTemplate.MaxDisplayedItems = 1
 	expectedTemplateData := getExpectedNewMediaTemplateData()
 
-	escapedHTML, err := BuildNewMediaEmailHTML(&newMovies, &newSeries, movieCount, seriesCount, app)
+	escapedHTML, err := BuildNewMediaEmailHTML(
+		&newMovies,
+		&newSeries,
+		&newMusicAlbums,
+		&newBooks,
+		&[]jellyfin.MovieItem{},
+		&[]jellyfin.RemovedItem{},
+		movieCount, seriesCount, app)
 	unescapedHTML := html.UnescapeString(escapedHTML)
 
 	require.NoError(t, err)
@@ -0,0 +1,69 @@
+package template
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	jellyfinAPI "github.com/sj14/jellyfin-go/api"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// formatProductionYear returns the year, or an empty string if unknown.
+func formatProductionYear(productionYear int32) string {
+	if productionYear == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(productionYear))
+}
+
+// formatRuntime returns the runtime in minutes, e.g. "169 min", or an empty string if unknown.
+func formatRuntime(runtimeMinutes int) string {
+	if runtimeMinutes <= 0 {
+		return ""
+	}
+	return strconv.Itoa(runtimeMinutes) + " min"
+}
+
+// formatCommunityRating returns the rating out of 10 with one decimal, e.g. "8.4", or an empty string if unknown.
+func formatCommunityRating(rating float32) string {
+	if rating <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(rating), 'f', 1, 32)
+}
+
+// formatCriticRating returns the rating as a percentage, e.g. "73%", or an empty string if unknown.
+func formatCriticRating(rating float32) string {
+	if rating <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(rating+0.5)) + "%"
+}
+
+// formatVideoRange returns the HDR format of the video. SDR is implied, so an empty string is returned.
+func formatVideoRange(videoRange string) string {
+	if videoRange == string(jellyfinAPI.VIDEORANGETYPE_SDR) {
+		return ""
+	}
+	return videoRange
+}
+
+// formatLanguages returns the names of the languages in the newsletter language, e.g. "English, French".
+// Jellyfin reports ISO 639-2 codes (e.g. "fre"), unknown codes are displayed as is.
+func formatLanguages(languageCodes []string, app *app.ApplicationContext) string {
+	namer := display.Tags(language.Make(app.Config.EmailTemplate.Language))
+	names := make([]string, 0, len(languageCodes))
+	for _, code := range languageCodes {
+		name := ""
+		if tag, err := language.Parse(code); err == nil {
+			name = namer.Name(tag)
+		}
+		if name == "" {
+			name = code
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,31 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatLanguages(t *testing.T) {
+	app, _ := getAppContext()
+	assert.Equal(t, "English, German, xx-unknown", formatLanguages([]string{"eng", "ger", "xx-unknown"}, app))
+	assert.Empty(t, formatLanguages(nil, app))
+
+	// Languages are named in the newsletter language
+	app.Config.EmailTemplate.Language = "fr"
+	assert.Equal(t, "anglais, français", formatLanguages([]string{"eng", "fre"}, app))
+}
+
+func TestFormatRatings(t *testing.T) {
+	assert.Equal(t, "7.0", formatCommunityRating(7))
+	assert.Empty(t, formatCommunityRating(0))
+	assert.Equal(t, "88%", formatCriticRating(87.6))
+	assert.Empty(t, formatCriticRating(0))
+}
+
+func TestFormatRuntimeAndVideoRange(t *testing.T) {
+	assert.Equal(t, "95 min", formatRuntime(95))
+	assert.Empty(t, formatRuntime(0))
+	assert.Equal(t, "DOVI", formatVideoRange("DOVI"))
+	assert.Empty(t, formatVideoRange("SDR"))
+}
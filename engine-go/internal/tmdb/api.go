@@ -8,15 +8,19 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/ratelimit"
 	"go.uber.org/zap"
 )
 
 type APIInterface interface {
 	GetMediaByID(id string, mediaType MediaType) (*GetMediaHTTPResponse, error)
 	SearchMediaByName(name string, productionYear int, mediaType MediaType) (*SearchMediaHTTPResponse, error)
+	FindMediaByExternalID(externalID string, source ExternalSource) (*FindMediaHTTPResponse, error)
+	GetSeasonByNumber(seriesID string, seasonNumber int) (*GetSeasonHTTPResponse, error)
 }
 
 type APIClient struct {
@@ -25,28 +29,75 @@ type APIClient struct {
 	Logger     *zap.Logger
 	BaseURL    string
 	HTTPClient *http.Client
+	// RateLimiter is shared by the clients of every newsletter, see app.ApplicationContext.TMDBRateLimiter.
+	// Without limiter, requests are not limited.
+	RateLimiter *ratelimit.Limiter
+	// Failed requests are retried MaxRetries times, see doRequest.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
 }
 
 func InitTMDBApiClient(httpClient *http.Client, app *app.ApplicationContext) APIClient {
 	return APIClient{
-		APIKey:     app.Config.TMDB.APIKey,
-		Lang:       app.Config.EmailTemplate.Language,
-		Logger:     app.Logger,
-		BaseURL:    "https://api.themoviedb.org/3",
-		HTTPClient: httpClient,
+		APIKey:         app.Config.TMDB.APIKey,
+		Lang:           app.Config.EmailTemplate.Language,
+		Logger:         app.Logger,
+		BaseURL:        "https://api.themoviedb.org/3",
+		HTTPClient:     httpClient,
+		RateLimiter:    app.TMDBRateLimiter,
+		MaxRetries:     defaultMaxRetries,
+		RetryBaseDelay: defaultRetryBaseDelay,
 	}
 }
 
 type GetMediaHTTPResponse struct {
-	Overview   string  `json:"overview"`
-	PosterPath string  `json:"poster_path"`
-	Popularity float64 `json:"popularity"`
+	ID            int                 `json:"id"`
+	Title         string              `json:"title"`          // Movies only
+	OriginalTitle string              `json:"original_title"` // Movies only
+	Name          string              `json:"name"`           // Series only
+	OriginalName  string              `json:"original_name"`  // Series only
+	ReleaseDate   string              `json:"release_date"`   // Movies only, e.g. "2010-07-15"
+	FirstAirDate  string              `json:"first_air_date"` // Series only, e.g. "2011-04-17"
+	MediaType     string              `json:"media_type"`     // Only in the results mixing media types
+	Overview      string              `json:"overview"`
+	PosterPath    string              `json:"poster_path"`
+	Popularity    float64             `json:"popularity"`
+	VoteAverage   float64             `json:"vote_average"`
+	Genres        []GenreHTTPResponse `json:"genres"`  // Only in the details, search results have genre ids
+	Runtime       int                 `json:"runtime"` // Movies only, in minutes
+}
+
+type GenreHTTPResponse struct {
+	Name string `json:"name"`
 }
 
 type SearchMediaHTTPResponse struct {
 	Results []GetMediaHTTPResponse `json:"results"`
 }
 
+// GetSeasonHTTPResponse is the details of a season of a series, with its episodes.
+type GetSeasonHTTPResponse struct {
+	SeasonNumber int                   `json:"season_number"`
+	Name         string                `json:"name"`
+	Overview     string                `json:"overview"`
+	PosterPath   string                `json:"poster_path"`
+	AirDate      string                `json:"air_date"` // e.g. "2024-05-01"
+	Episodes     []EpisodeHTTPResponse `json:"episodes"`
+}
+
+type EpisodeHTTPResponse struct {
+	EpisodeNumber int    `json:"episode_number"`
+	Name          string `json:"name"`
+	Overview      string `json:"overview"`
+	AirDate       string `json:"air_date"` // e.g. "2024-05-01"
+}
+
+// FindMediaHTTPResponse lists the media matching an external id, by media type.
+type FindMediaHTTPResponse struct {
+	MovieResults []GetMediaHTTPResponse `json:"movie_results"`
+	TVResults    []GetMediaHTTPResponse `json:"tv_results"`
+}
+
 func (client APIClient) prepareGetAPIRequest(url string) (*http.Request, error) {
 	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
 	if err != nil {
@@ -128,7 +179,7 @@ func (client APIClient) GetMediaByID(id string, mediaType MediaType) (*GetMediaH
 		return nil, err
 	}
 
-	httpResponse, execReqErr := client.HTTPClient.Do(request)
+	httpResponse, execReqErr := client.doRequest(request)
 
 	err = checkHTTPResponse(encodedURL, httpResponse, execReqErr, client.Logger)
 
@@ -212,7 +263,7 @@ func (client APIClient) SearchMediaByName(
 		return nil, err
 	}
 
-	httpResponse, execReqErr := client.HTTPClient.Do(request)
+	httpResponse, execReqErr := client.doRequest(request)
 
 	err = checkHTTPResponse(encodedURL, httpResponse, execReqErr, client.Logger)
 
@@ -244,3 +295,150 @@ func (client APIClient) SearchMediaByName(
 	}
 	return &decodedBody, nil
 }
+
+// FindMediaByExternalID returns the media matching the id of another database, e.g. the IMDb id "tt0111161".
+func (client APIClient) FindMediaByExternalID(
+	externalID string,
+	source ExternalSource,
+) (*FindMediaHTTPResponse, error) {
+	baseURL, err := url.JoinPath(client.BaseURL, "find", externalID)
+
+	if err != nil {
+		client.Logger.Error(
+			"An error occurred while buidling TMDB URL",
+			zap.Error(err),
+			zap.String("baseURL", client.BaseURL),
+			zap.String("External id", externalID),
+		)
+		return nil, err
+	}
+
+	apiURL, err := url.Parse(baseURL)
+	if err != nil {
+		client.Logger.Error(
+			"An error occurred while parsing TMDB URL",
+			zap.Error(err),
+			zap.String("baseURL", baseURL),
+		)
+		return nil, err
+	}
+	urlQuery := apiURL.Query()
+	urlQuery.Add("language", client.Lang)
+	urlQuery.Add("external_source", source.ToString())
+	apiURL.RawQuery = urlQuery.Encode()
+	encodedURL := apiURL.String()
+
+	request, err := client.prepareGetAPIRequest(encodedURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	httpResponse, execReqErr := client.doRequest(request)
+
+	err = checkHTTPResponse(encodedURL, httpResponse, execReqErr, client.Logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResponse.Body.Close()
+
+	body, err := io.ReadAll(httpResponse.Body)
+
+	if err != nil {
+		client.Logger.Error("Impossible to read the HTTP response body.",
+			zap.String("URL", encodedURL),
+			zap.Int("HTTP Status code", httpResponse.StatusCode),
+			zap.Error(err))
+		return nil, err
+	}
+
+	var decodedBody FindMediaHTTPResponse
+	jsonDecodeErr := json.Unmarshal(body, &decodedBody)
+
+	if jsonDecodeErr != nil {
+		client.Logger.Error(
+			"An error occurred while decoding TMDB API's answer.",
+			zap.Error(jsonDecodeErr),
+			zap.String("URL", encodedURL),
+		)
+		return nil, jsonDecodeErr
+	}
+	return &decodedBody, nil
+}
+
+// GetSeasonByNumber returns the details of the season seasonNumber of the series seriesID, with its episodes.
+func (client APIClient) GetSeasonByNumber(seriesID string, seasonNumber int) (*GetSeasonHTTPResponse, error) {
+	baseURL, err := url.JoinPath(
+		client.BaseURL,
+		MediaTypeSeries.ToString(),
+		seriesID,
+		"season",
+		strconv.Itoa(seasonNumber),
+	)
+
+	if err != nil {
+		client.Logger.Error(
+			"An error occurred while buidling TMDB URL",
+			zap.Error(err),
+			zap.String("baseURL", client.BaseURL),
+			zap.String("Series id", seriesID),
+			zap.Int("Season number", seasonNumber),
+		)
+		return nil, err
+	}
+
+	apiURL, err := url.Parse(baseURL)
+	if err != nil {
+		client.Logger.Error(
+			"An error occurred while parsing TMDB URL",
+			zap.Error(err),
+			zap.String("baseURL", baseURL),
+		)
+		return nil, err
+	}
+	urlQuery := apiURL.Query()
+	urlQuery.Add("language", client.Lang)
+	apiURL.RawQuery = urlQuery.Encode()
+	encodedURL := apiURL.String()
+
+	request, err := client.prepareGetAPIRequest(encodedURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	httpResponse, execReqErr := client.doRequest(request)
+
+	err = checkHTTPResponse(encodedURL, httpResponse, execReqErr, client.Logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResponse.Body.Close()
+
+	body, err := io.ReadAll(httpResponse.Body)
+
+	if err != nil {
+		client.Logger.Error("Impossible to read the HTTP response body.",
+			zap.String("URL", encodedURL),
+			zap.Int("HTTP Status code", httpResponse.StatusCode),
+			zap.Error(err))
+		return nil, err
+	}
+
+	var decodedBody GetSeasonHTTPResponse
+	jsonDecodeErr := json.Unmarshal(body, &decodedBody)
+
+	if jsonDecodeErr != nil {
+		client.Logger.Error(
+			"An error occurred while decoding TMDB API's answer.",
+			zap.Error(jsonDecodeErr),
+			zap.String("URL", encodedURL),
+		)
+		return nil, jsonDecodeErr
+	}
+	return &decodedBody, nil
+}
@@ -0,0 +1,180 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	"go.uber.org/zap"
+)
+
+// Overviews and posters almost never change, so TMDB responses are cached on disk (see persistentdata.GetTMDBCache)
+// and reused for tmdb.cache_ttl_days days. Expired responses are kept as a fallback when TMDB is unreachable,
+// and dropped after staleEntriesRetention.
+
+const staleEntriesRetention = 180 * 24 * time.Hour
+
+// CachingAPIInterface is an APIInterface whose responses must be saved at the end of the run.
+type CachingAPIInterface interface {
+	APIInterface
+	SaveCache() error
+}
+
+// CachedAPIClient wraps an APIInterface with the on-disk cache. It is safe for concurrent use.
+type CachedAPIClient struct {
+	client     APIInterface
+	lang       string
+	ttl        time.Duration
+	app        *app.ApplicationContext
+	mutex      sync.Mutex
+	entries    map[string]persistentdata.TMDBCacheEntry
+	newEntries map[string]persistentdata.TMDBCacheEntry
+}
+
+// NewCachedAPIClient returns client with the responses cached by the previous runs.
+// If the cache can't be read, it starts empty.
+func NewCachedAPIClient(client APIInterface, app *app.ApplicationContext) *CachedAPIClient {
+	entries, err := persistentdata.GetTMDBCache(app)
+	if err != nil {
+		app.Logger.Warn(
+			"An error occured while reading the TMDB cache. TMDB responses will be fetched again.",
+			zap.Error(err),
+		)
+		entries = map[string]persistentdata.TMDBCacheEntry{}
+	}
+	return &CachedAPIClient{
+		client:     client,
+		lang:       app.Config.EmailTemplate.Language,
+		ttl:        time.Duration(app.Config.TMDB.CacheTTLDays) * 24 * time.Hour,
+		app:        app,
+		entries:    entries,
+		newEntries: map[string]persistentdata.TMDBCacheEntry{},
+	}
+}
+
+// mediaCacheKey returns the cache key of the details of a media, e.g. "movie|id=27205|language=en".
+func (client *CachedAPIClient) mediaCacheKey(id string, mediaType MediaType) string {
+	return fmt.Sprintf("%s|id=%s|language=%s", mediaType.ToString(), id, client.lang)
+}
+
+// searchCacheKey returns the cache key of a search by name, e.g. "movie|query=inception|year=2010|language=en".
+// TMDB search is case insensitive, so is the key.
+func (client *CachedAPIClient) searchCacheKey(name string, productionYear int, mediaType MediaType) string {
+	return fmt.Sprintf(
+		"%s|query=%s|year=%d|language=%s",
+		mediaType.ToString(),
+		strings.ToLower(strings.TrimSpace(name)),
+		productionYear,
+		client.lang,
+	)
+}
+
+// findCacheKey returns the cache key of a search by external id, e.g. "find|imdb_id=tt1375666|language=en".
+func (client *CachedAPIClient) findCacheKey(externalID string, source ExternalSource) string {
+	return fmt.Sprintf("find|%s=%s|language=%s", source.ToString(), externalID, client.lang)
+}
+
+// seasonCacheKey returns the cache key of the details of a season, e.g. "tv|id=1399|season=3|language=en".
+func (client *CachedAPIClient) seasonCacheKey(seriesID string, seasonNumber int) string {
+	return fmt.Sprintf("%s|id=%s|season=%d|language=%s", MediaTypeSeries.ToString(), seriesID, seasonNumber, client.lang)
+}
+
+func (client *CachedAPIClient) getEntry(key string) (persistentdata.TMDBCacheEntry, bool) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	entry, ok := client.entries[key]
+	return entry, ok
+}
+
+func (client *CachedAPIClient) setEntry(key string, entry persistentdata.TMDBCacheEntry) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.entries[key] = entry
+	client.newEntries[key] = entry
+}
+
+// cachedRequest returns the cached response of the request identified by key while it's fresh.
+// Otherwise the response is fetched with request and cached. If request fails, the expired response is returned
+// when there is one.
+func cachedRequest[T any](client *CachedAPIClient, key string, request func() (*T, error)) (*T, error) {
+	entry, isCached := client.getEntry(key)
+	var cachedResponse T
+	if isCached && json.Unmarshal(entry.Response, &cachedResponse) != nil {
+		isCached = false
+	}
+	if isCached && client.app.Clock.Now().Sub(entry.FetchedAt) < client.ttl {
+		return &cachedResponse, nil
+	}
+
+	response, err := request()
+	if err != nil {
+		if isCached {
+			client.app.Logger.Warn(
+				"TMDB request failed. An expired cached response is used instead.",
+				zap.String("request", key),
+				zap.Time("fetched at", entry.FetchedAt),
+				zap.Error(err),
+			)
+			return &cachedResponse, nil
+		}
+		return nil, err
+	}
+
+	rawResponse, err := json.Marshal(response)
+	if err == nil {
+		client.setEntry(key, persistentdata.TMDBCacheEntry{Response: rawResponse, FetchedAt: client.app.Clock.Now()})
+	}
+	return response, nil
+}
+
+func (client *CachedAPIClient) GetMediaByID(id string, mediaType MediaType) (*GetMediaHTTPResponse, error) {
+	return cachedRequest(client, client.mediaCacheKey(id, mediaType), func() (*GetMediaHTTPResponse, error) {
+		return client.client.GetMediaByID(id, mediaType)
+	})
+}
+
+func (client *CachedAPIClient) SearchMediaByName(
+	name string,
+	productionYear int,
+	mediaType MediaType,
+) (*SearchMediaHTTPResponse, error) {
+	key := client.searchCacheKey(name, productionYear, mediaType)
+	return cachedRequest(client, key, func() (*SearchMediaHTTPResponse, error) {
+		return client.client.SearchMediaByName(name, productionYear, mediaType)
+	})
+}
+
+func (client *CachedAPIClient) FindMediaByExternalID(
+	externalID string,
+	source ExternalSource,
+) (*FindMediaHTTPResponse, error) {
+	return cachedRequest(client, client.findCacheKey(externalID, source), func() (*FindMediaHTTPResponse, error) {
+		return client.client.FindMediaByExternalID(externalID, source)
+	})
+}
+
+func (client *CachedAPIClient) GetSeasonByNumber(seriesID string, seasonNumber int) (*GetSeasonHTTPResponse, error) {
+	return cachedRequest(client, client.seasonCacheKey(seriesID, seasonNumber), func() (*GetSeasonHTTPResponse, error) {
+		return client.client.GetSeasonByNumber(seriesID, seasonNumber)
+	})
+}
+
+// SaveCache saves the responses fetched since the client creation, and drops the entries expired
+// for more than staleEntriesRetention.
+func (client *CachedAPIClient) SaveCache() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if len(client.newEntries) == 0 {
+		return nil
+	}
+	fetchedAfter := client.app.Clock.Now().Add(-client.ttl - staleEntriesRetention)
+	if err := persistentdata.UpdateTMDBCache(client.newEntries, fetchedAfter, client.app); err != nil {
+		return err
+	}
+	client.newEntries = map[string]persistentdata.TMDBCacheEntry{}
+	return nil
+}
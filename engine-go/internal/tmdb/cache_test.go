@@ -0,0 +1,189 @@
+package tmdb
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var errTMDBUnreachable = errors.New("TMDB is unreachable")
+
+type MockTMDBAPI struct {
+	calls                    int
+	ExecuteGetMediaByID      func(id string) (*GetMediaHTTPResponse, error)
+	ExecuteSearchMediaByName func(name string) (*SearchMediaHTTPResponse, error)
+}
+
+func (m *MockTMDBAPI) FindMediaByExternalID(externalID string, _ ExternalSource) (*FindMediaHTTPResponse, error) {
+	m.calls++
+	media, err := m.ExecuteGetMediaByID(externalID)
+	if err != nil {
+		return nil, err
+	}
+	return &FindMediaHTTPResponse{MovieResults: []GetMediaHTTPResponse{*media}}, nil
+}
+
+func (m *MockTMDBAPI) GetSeasonByNumber(seriesID string, seasonNumber int) (*GetSeasonHTTPResponse, error) {
+	m.calls++
+	media, err := m.ExecuteGetMediaByID(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetSeasonHTTPResponse{SeasonNumber: seasonNumber, Overview: media.Overview}, nil
+}
+
+func (m *MockTMDBAPI) GetMediaByID(id string, _ MediaType) (*GetMediaHTTPResponse, error) {
+	m.calls++
+	return m.ExecuteGetMediaByID(id)
+}
+
+func (m *MockTMDBAPI) SearchMediaByName(name string, _ int, _ MediaType) (*SearchMediaHTTPResponse, error) {
+	m.calls++
+	return m.ExecuteSearchMediaByName(name)
+}
+
+func getCacheTestApp(t *testing.T) *app.ApplicationContext {
+	return &app.ApplicationContext{
+		Logger: zap.NewNop(),
+		Config: &config.Configuration{
+			ConfigFilePath: filepath.Join(t.TempDir(), "config.yml"),
+			TMDB:           config.TMDBConfig{Enabled: true, CacheTTLDays: 7},
+			EmailTemplate:  config.EmailTemplateConfig{Language: "en"},
+		},
+		Clock: clock.FixedClock{Time: time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)},
+	}
+}
+
+func getMockTMDBAPI(overview string) *MockTMDBAPI {
+	return &MockTMDBAPI{
+		ExecuteGetMediaByID: func(id string) (*GetMediaHTTPResponse, error) {
+			return &GetMediaHTTPResponse{Overview: overview + " " + id}, nil
+		},
+		ExecuteSearchMediaByName: func(name string) (*SearchMediaHTTPResponse, error) {
+			return &SearchMediaHTTPResponse{Results: []GetMediaHTTPResponse{{Overview: overview + " " + name}}}, nil
+		},
+	}
+}
+
+// runAndSaveCache fetches the media 1 and searches "Movie" with a new cached client, then saves the cache.
+func runAndSaveCache(t *testing.T, app *app.ApplicationContext, api APIInterface) (string, string) {
+	client := NewCachedAPIClient(api, app)
+	media, err := client.GetMediaByID("1", MediaTypeMovie)
+	require.NoError(t, err)
+	searchResult, err := client.SearchMediaByName("Movie", 2026, MediaTypeMovie)
+	require.NoError(t, err)
+	require.Len(t, searchResult.Results, 1)
+	require.NoError(t, client.SaveCache())
+	return media.Overview, searchResult.Results[0].Overview
+}
+
+func TestCachedAPIClient_ReusesFreshResponses(t *testing.T) {
+	app := getCacheTestApp(t)
+	runAndSaveCache(t, app, getMockTMDBAPI("first"))
+
+	api := getMockTMDBAPI("second")
+	mediaOverview, searchOverview := runAndSaveCache(t, app, api)
+
+	assert.Equal(t, 0, api.calls)
+	assert.Equal(t, "first 1", mediaOverview)
+	assert.Equal(t, "first Movie", searchOverview)
+}
+
+func TestCachedAPIClient_RefreshesExpiredResponses(t *testing.T) {
+	app := getCacheTestApp(t)
+	runAndSaveCache(t, app, getMockTMDBAPI("first"))
+
+	app.Clock = clock.FixedClock{Time: app.Clock.Now().AddDate(0, 0, 8)}
+	api := getMockTMDBAPI("second")
+	mediaOverview, searchOverview := runAndSaveCache(t, app, api)
+
+	assert.Equal(t, 2, api.calls)
+	assert.Equal(t, "second 1", mediaOverview)
+	assert.Equal(t, "second Movie", searchOverview)
+
+	// The refreshed responses are saved
+	api = getMockTMDBAPI("third")
+	mediaOverview, _ = runAndSaveCache(t, app, api)
+	assert.Equal(t, 0, api.calls)
+	assert.Equal(t, "second 1", mediaOverview)
+}
+
+func TestCachedAPIClient_UsesExpiredResponsesWhenTMDBIsUnreachable(t *testing.T) {
+	app := getCacheTestApp(t)
+	runAndSaveCache(t, app, getMockTMDBAPI("first"))
+
+	app.Clock = clock.FixedClock{Time: app.Clock.Now().AddDate(0, 2, 0)}
+	api := &MockTMDBAPI{
+		ExecuteGetMediaByID: func(_ string) (*GetMediaHTTPResponse, error) {
+			return nil, errTMDBUnreachable
+		},
+		ExecuteSearchMediaByName: func(_ string) (*SearchMediaHTTPResponse, error) {
+			return nil, errTMDBUnreachable
+		},
+	}
+	mediaOverview, searchOverview := runAndSaveCache(t, app, api)
+
+	assert.Equal(t, 2, api.calls)
+	assert.Equal(t, "first 1", mediaOverview)
+	assert.Equal(t, "first Movie", searchOverview)
+
+	_, err := NewCachedAPIClient(api, app).GetMediaByID("2", MediaTypeMovie)
+	require.ErrorIs(t, err, errTMDBUnreachable, "without cached response, the error is returned")
+}
+
+func TestCachedAPIClient_KeysIncludeLanguageAndYear(t *testing.T) {
+	app := getCacheTestApp(t)
+	runAndSaveCache(t, app, getMockTMDBAPI("first"))
+
+	app.Config.EmailTemplate.Language = "fr"
+	api := getMockTMDBAPI("second")
+	mediaOverview, _ := runAndSaveCache(t, app, api)
+	assert.Equal(t, "second 1", mediaOverview)
+	assert.Equal(t, 2, api.calls)
+
+	client := NewCachedAPIClient(api, app)
+	_, err := client.SearchMediaByName("movie", 2026, MediaTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, 2, api.calls, "the search is case insensitive")
+	_, err = client.SearchMediaByName("Movie", 2025, MediaTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, 3, api.calls)
+}
+
+func TestCachedAPIClient_SeasonsAreCachedByNumber(t *testing.T) {
+	app := getCacheTestApp(t)
+	api := getMockTMDBAPI("season")
+	client := NewCachedAPIClient(api, app)
+
+	season, err := client.GetSeasonByNumber("1234", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, season.SeasonNumber)
+	_, err = client.GetSeasonByNumber("1234", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.calls)
+
+	season, err = client.GetSeasonByNumber("1234", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, season.SeasonNumber)
+	assert.Equal(t, 2, api.calls)
+}
+
+func TestCachedAPIClient_ZeroTTLOnlyUsesCacheAsFallback(t *testing.T) {
+	app := getCacheTestApp(t)
+	app.Config.TMDB.CacheTTLDays = 0
+	runAndSaveCache(t, app, getMockTMDBAPI("first"))
+
+	api := getMockTMDBAPI("second")
+	mediaOverview, _ := runAndSaveCache(t, app, api)
+
+	assert.Equal(t, 2, api.calls)
+	assert.Equal(t, "second 1", mediaOverview)
+}
@@ -1,8 +1,23 @@
 package tmdb
 
+import (
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Items are enriched by enrichmentWorkers at the same time. The requests stay within the TMDB limits
+// thanks to the rate limiter of the client, see APIClient.RateLimiter.
+const enrichmentWorkers = 8
+
+const posterBaseURL = "https://image.tmdb.org/t/p/w500"
+
 type ItemDetails struct {
-	Overview  string
-	PosterURL string
+	Overview        string
+	PosterURL       string
+	Genres          []string
+	RuntimeMinutes  int
+	CommunityRating float32
 }
 
 func getDefaultItemDetails() *ItemDetails {
@@ -12,32 +27,77 @@ func getDefaultItemDetails() *ItemDetails {
 	}
 }
 
+// completeItemDetails fills the overview and the poster the item has no value for, keeping the Jellyfin ones.
+func completeItemDetails(overview *string, posterURL *string, details *ItemDetails) {
+	if *overview == "" {
+		*overview = details.Overview
+	}
+	if *posterURL == "" {
+		*posterURL = details.PosterURL
+	}
+}
+
 func getItemDetailsFromHTTPResponse(parsedHTTPResponse *GetMediaHTTPResponse) *ItemDetails {
 	itemDetails := getDefaultItemDetails()
 	if parsedHTTPResponse.Overview != "" {
 		itemDetails.Overview = parsedHTTPResponse.Overview
 	}
 	if parsedHTTPResponse.PosterPath != "" {
-		itemDetails.PosterURL = "https://image.tmdb.org/t/p/w500" + parsedHTTPResponse.PosterPath
+		itemDetails.PosterURL = posterBaseURL + parsedHTTPResponse.PosterPath
+	}
+	for _, genre := range parsedHTTPResponse.Genres {
+		itemDetails.Genres = append(itemDetails.Genres, genre.Name)
 	}
+	itemDetails.RuntimeMinutes = parsedHTTPResponse.Runtime
+	itemDetails.CommunityRating = float32(parsedHTTPResponse.VoteAverage)
 	return itemDetails
 }
 
-// Parse result from the TMDB search based on name
-// The final item will be selected based on popularity.
-func getItemDetailsFromSearchResult(result *SearchMediaHTTPResponse) *ItemDetails {
-	itemDetails := getDefaultItemDetails()
-	popularity := -1.0
-	for _, item := range result.Results {
-		if item.Popularity > popularity {
-			if item.Overview != "" {
-				itemDetails.Overview = item.Overview
-			}
-			if item.PosterPath != "" {
-				itemDetails.PosterURL = "https://image.tmdb.org/t/p/w500" + item.PosterPath
-			}
-			popularity = item.Popularity
+// findTMDBID returns the TMDB id of the media matching imdbID, or else tvdbID. Empty ids are skipped.
+// It returns an empty id when TMDB matches none of them. A failed lookup doesn't prevent the next one:
+// an error is only returned when no id is found and at least one lookup failed.
+func findTMDBID(imdbID string, tvdbID string, mediaType MediaType, tmdbAPIClient APIInterface) (string, error) {
+	externalIDs := []struct {
+		id     string
+		source ExternalSource
+	}{{imdbID, ExternalSourceIMDb}, {tvdbID, ExternalSourceTVDB}}
+	lookupErrors := []error{}
+	for _, externalID := range externalIDs {
+		if externalID.id == "" {
+			continue
+		}
+		result, err := tmdbAPIClient.FindMediaByExternalID(externalID.id, externalID.source)
+		if err != nil {
+			// Error is already logged by FindMediaByExternalID. The next id is tried.
+			lookupErrors = append(lookupErrors, err)
+			continue
+		}
+		results := result.MovieResults
+		if mediaType == MediaTypeSeries {
+			results = result.TVResults
+		}
+		if len(results) > 0 {
+			return strconv.Itoa(results[0].ID), nil
 		}
 	}
-	return itemDetails
+	return "", errors.Join(lookupErrors...)
+}
+
+// enrichConcurrently calls enrich on every item with a pool of enrichmentWorkers goroutines.
+// Each item is enriched in place, so the order of items is kept.
+func enrichConcurrently[T any](items []T, enrich func(item *T)) {
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+	for range min(enrichmentWorkers, len(items)) {
+		waitGroup.Go(func() {
+			for index := range indexes {
+				enrich(&items[index])
+			}
+		})
+	}
+	for index := range items {
+		indexes <- index
+	}
+	close(indexes)
+	waitGroup.Wait()
 }
@@ -0,0 +1,189 @@
+package tmdb
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"go.uber.org/zap"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// A search by name often returns several media. Each result gets a confidence between 0 and 1:
+// the title similarity counts for titleWeight, the release year for the rest. Results of another media type
+// get 0. The most confident result is used as a whole, the popularity only breaks ties.
+
+const (
+	titleWeight = 0.7
+	// yearWeight is granted to the exact release year, nearYearWeight to a year off by one (e.g. a late
+	// release abroad) and unknownYearWeight when the year of the media or of the result is unknown.
+	yearWeight        = 0.3
+	nearYearWeight    = 0.2
+	unknownYearWeight = 0.15
+)
+
+// normalizeTitle lowercases title and removes its accents and punctuation, e.g. "Amélie: Le Film!" → "amelie le film".
+func normalizeTitle(title string) string {
+	withoutAccents, _, err := transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC),
+		title,
+	)
+	if err != nil {
+		withoutAccents = title
+	}
+	words := strings.FieldsFunc(strings.ToLower(withoutAccents), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// levenshteinDistance returns the number of rune insertions, deletions and substitutions turning a into b.
+func levenshteinDistance(a []rune, b []rune) int {
+	previousRow := make([]int, len(b)+1)
+	currentRow := make([]int, len(b)+1)
+	for j := range previousRow {
+		previousRow[j] = j
+	}
+	for i := range a {
+		currentRow[0] = i + 1
+		for j := range b {
+			substitutionCost := 1
+			if a[i] == b[j] {
+				substitutionCost = 0
+			}
+			currentRow[j+1] = min(previousRow[j+1]+1, currentRow[j]+1, previousRow[j]+substitutionCost)
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+	return previousRow[len(b)]
+}
+
+// titleSimilarity returns 1 for titles equal once normalized, down to 0 for unrelated titles.
+func titleSimilarity(a string, b string) float64 {
+	normalizedA := []rune(normalizeTitle(a))
+	normalizedB := []rune(normalizeTitle(b))
+	if len(normalizedA) == 0 || len(normalizedB) == 0 {
+		return 0
+	}
+	longestLength := max(len(normalizedA), len(normalizedB))
+	return 1 - float64(levenshteinDistance(normalizedA, normalizedB))/float64(longestLength)
+}
+
+// releaseYear returns the year of the release date of a movie, or of the first air date of a series.
+// It returns 0 if unknown.
+func (media *GetMediaHTTPResponse) releaseYear() int {
+	date := media.ReleaseDate
+	if date == "" {
+		date = media.FirstAirDate
+	}
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// matchConfidence returns how confident we are that result is the media named name, released in productionYear
+// (0 if unknown).
+func matchConfidence(result *GetMediaHTTPResponse, name string, productionYear int, mediaType MediaType) float64 {
+	if result.MediaType != "" && result.MediaType != mediaType.ToString() {
+		return 0
+	}
+
+	similarity := 0.0
+	for _, title := range []string{result.Title, result.OriginalTitle, result.Name, result.OriginalName} {
+		if title != "" {
+			similarity = max(similarity, titleSimilarity(name, title))
+		}
+	}
+
+	resultYear := result.releaseYear()
+	yearScore := 0.0
+	switch {
+	case productionYear == 0 || resultYear == 0:
+		yearScore = unknownYearWeight
+	case productionYear == resultYear:
+		yearScore = yearWeight
+	case productionYear-resultYear == 1 || resultYear-productionYear == 1:
+		yearScore = nearYearWeight
+	}
+	return titleWeight*similarity + yearScore
+}
+
+// selectSearchResult returns the search result matching the media named name, released in productionYear,
+// with the highest confidence. If no result reaches the configured confidence threshold, it returns nil,
+// so the Jellyfin metadata are kept rather than showing the poster of another media.
+func selectSearchResult(
+	result *SearchMediaHTTPResponse,
+	name string,
+	productionYear int,
+	mediaType MediaType,
+	app *app.ApplicationContext,
+) *GetMediaHTTPResponse {
+	var bestMatch *GetMediaHTTPResponse
+	bestConfidence := -1.0
+	for i := range result.Results {
+		candidate := &result.Results[i]
+		confidence := matchConfidence(candidate, name, productionYear, mediaType)
+		if confidence > bestConfidence || (confidence == bestConfidence && candidate.Popularity > bestMatch.Popularity) {
+			bestMatch = candidate
+			bestConfidence = confidence
+		}
+	}
+	if bestMatch == nil {
+		return nil
+	}
+
+	fields := []zap.Field{
+		zap.String("Name", name),
+		zap.Int("Production year", productionYear),
+		zap.String("TMDB title", bestMatch.Title+bestMatch.Name),
+		zap.Int("TMDB release year", bestMatch.releaseYear()),
+		zap.Int("TMDB id", bestMatch.ID),
+		zap.Float64("Confidence", bestConfidence),
+	}
+	if bestConfidence < app.Config.TMDB.MatchConfidenceThreshold {
+		app.Logger.Info(
+			"No TMDB search result matches the media confidently enough. TMDB metadata are not used.",
+			append(fields, zap.Float64("Threshold", app.Config.TMDB.MatchConfidenceThreshold))...,
+		)
+		return nil
+	}
+	app.Logger.Debug("TMDB search result selected.", fields...)
+	return bestMatch
+}
+
+// searchMedia searches the media named name on TMDB and returns the result matching it best (see selectSearchResult),
+// or nil. TMDB only returns the media released in productionYear, while the year known by Jellyfin may be off by one
+// (e.g. a late release abroad), so without a confident result, the media is searched again without the year,
+// which then only weighs on the confidence.
+func searchMedia(
+	name string,
+	productionYear int,
+	mediaType MediaType,
+	tmdbAPIClient APIInterface,
+	app *app.ApplicationContext,
+) *GetMediaHTTPResponse {
+	searchResult, err := tmdbAPIClient.SearchMediaByName(name, productionYear, mediaType)
+	if err != nil {
+		// Error is already logged by SearchMediaByName
+		return nil
+	}
+	match := selectSearchResult(searchResult, name, productionYear, mediaType, app)
+	if match != nil || productionYear == 0 {
+		return match
+	}
+
+	app.Logger.Debug("Searching the media on TMDB again, without its production year.", zap.String("Name", name))
+	searchResult, err = tmdbAPIClient.SearchMediaByName(name, 0, mediaType)
+	if err != nil {
+		return nil
+	}
+	return selectSearchResult(searchResult, name, productionYear, mediaType, app)
+}
@@ -0,0 +1,110 @@
+package tmdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	assert.Equal(t, "amelie le film", normalizeTitle("Amélie: Le Film!"))
+	assert.Equal(t, "spider man no way home", normalizeTitle("  Spider-Man:  No Way Home "))
+	assert.Equal(t, "wall e", normalizeTitle("WALL·E"))
+	assert.Empty(t, normalizeTitle("?!"))
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	assert.InDelta(t, 1, titleSimilarity("Amélie", "amelie"), 0.001)
+	assert.InDelta(t, 0.9, titleSimilarity("The Matrix", "The Matrx"), 0.02)
+	assert.Less(t, titleSimilarity("Inception", "The Little Mermaid"), 0.3)
+	assert.InDelta(t, 0, titleSimilarity("", "Inception"), 0.001)
+}
+
+func TestMatchConfidence(t *testing.T) {
+	tests := []struct {
+		name               string
+		result             GetMediaHTTPResponse
+		productionYear     int
+		expectedConfidence float64
+	}{
+		{
+			name:               "Same title and year",
+			result:             GetMediaHTTPResponse{Title: "Dune", ReleaseDate: "2021-09-15"},
+			productionYear:     2021,
+			expectedConfidence: 1,
+		},
+		{
+			name:               "Same original title, year off by one",
+			result:             GetMediaHTTPResponse{Title: "Düne", OriginalTitle: "Dune", ReleaseDate: "2022-01-02"},
+			productionYear:     2021,
+			expectedConfidence: 0.9,
+		},
+		{
+			name:               "Same title, unknown year",
+			result:             GetMediaHTTPResponse{Title: "Dune"},
+			productionYear:     2021,
+			expectedConfidence: 0.85,
+		},
+		{
+			name:               "Same title, another year",
+			result:             GetMediaHTTPResponse{Title: "Dune", ReleaseDate: "1984-12-14"},
+			productionYear:     2021,
+			expectedConfidence: 0.7,
+		},
+		{
+			name:               "Another media type",
+			result:             GetMediaHTTPResponse{Name: "Dune", FirstAirDate: "2021-09-15", MediaType: "tv"},
+			productionYear:     2021,
+			expectedConfidence: 0,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			confidence := matchConfidence(&testCase.result, "Dune", testCase.productionYear, MediaTypeMovie)
+			assert.InDelta(t, testCase.expectedConfidence, confidence, 0.001)
+		})
+	}
+}
+
+func TestSelectSearchResult(t *testing.T) {
+	searchResult := &SearchMediaHTTPResponse{Results: []GetMediaHTTPResponse{
+		{ID: 1, Name: "Doctor Who", FirstAirDate: "1963-11-23", Popularity: 80, PosterPath: "/classic.jpg"},
+		{ID: 2, Name: "Doctor Who", FirstAirDate: "2005-03-26", Popularity: 40},
+		{ID: 3, Name: "Doctor Who", FirstAirDate: "2023-11-25", Popularity: 120, Overview: "Overview"},
+		{ID: 4, Name: "Doctor Foster", FirstAirDate: "2005-09-09", Popularity: 500},
+	}}
+	app := getTestApp(zap.NewNop())
+
+	match := selectSearchResult(searchResult, "Doctor Who", 2005, MediaTypeSeries, &app)
+	require.NotNil(t, match)
+	assert.Equal(t, 2, match.ID, "the title and the year matter more than the popularity")
+	assert.Empty(t, match.PosterPath, "the fields of the other results are not mixed in")
+	assert.Empty(t, match.Overview, "the fields of the other results are not mixed in")
+
+	match = selectSearchResult(searchResult, "Doctor Who", 0, MediaTypeSeries, &app)
+	require.NotNil(t, match)
+	assert.Equal(t, 3, match.ID, "without year, the most popular of the best matches is selected")
+
+	assert.Nil(t, selectSearchResult(&SearchMediaHTTPResponse{}, "Doctor Who", 2005, MediaTypeSeries, &app))
+}
+
+func TestSelectSearchResult_BelowThreshold(t *testing.T) {
+	searchResult := &SearchMediaHTTPResponse{Results: []GetMediaHTTPResponse{
+		{ID: 1, Title: "The Little Mermaid", ReleaseDate: "2023-05-18", Popularity: 300},
+	}}
+	loggerCore, recordedLogs := observer.New(zap.InfoLevel)
+	app := getTestApp(zap.New(loggerCore))
+
+	assert.Nil(t, selectSearchResult(searchResult, "The Lighthouse", 2019, MediaTypeMovie, &app))
+	require.Equal(t, 1, recordedLogs.Len())
+	assert.Equal(t, int64(1), recordedLogs.All()[0].ContextMap()["TMDB id"])
+
+	app.Config.TMDB.MatchConfidenceThreshold = 0
+	match := selectSearchResult(searchResult, "The Lighthouse", 2019, MediaTypeMovie, &app)
+	require.NotNil(t, match, "a zero threshold accepts any result")
+	assert.Equal(t, 1, match.ID)
+}
@@ -10,3 +10,15 @@ const (
 func (m MediaType) ToString() string {
 	return string(m)
 }
+
+// ExternalSource is a database whose ids TMDB can match, see APIInterface.FindMediaByExternalID.
+type ExternalSource string
+
+const (
+	ExternalSourceIMDb ExternalSource = "imdb_id"
+	ExternalSourceTVDB ExternalSource = "tvdb_id"
+)
+
+func (s ExternalSource) ToString() string {
+	return string(s)
+}
@@ -7,18 +7,63 @@ import (
 )
 
 func enrichMovieWithDefaultInfos(jellyfinSeriesItem *jellyfin.MovieItem) {
-	defaultDetails := getDefaultItemDetails()
-	jellyfinSeriesItem.Overview = defaultDetails.Overview
-	jellyfinSeriesItem.PosterURL = defaultDetails.PosterURL
+	completeItemDetails(&jellyfinSeriesItem.Overview, &jellyfinSeriesItem.PosterURL, getDefaultItemDetails())
 }
 
+// completeMovieDetails fills the metadata the movie has no value for, keeping the Jellyfin ones.
+func completeMovieDetails(jellyfinMovieItem *jellyfin.MovieItem, details *ItemDetails) {
+	completeItemDetails(&jellyfinMovieItem.Overview, &jellyfinMovieItem.PosterURL, details)
+	if len(jellyfinMovieItem.Genres) == 0 {
+		jellyfinMovieItem.Genres = details.Genres
+	}
+	if jellyfinMovieItem.RuntimeMinutes == 0 {
+		jellyfinMovieItem.RuntimeMinutes = details.RuntimeMinutes
+	}
+	if jellyfinMovieItem.CommunityRating == 0 {
+		jellyfinMovieItem.CommunityRating = details.CommunityRating
+	}
+}
+
+// hasCompleteMetadata reports whether Jellyfin provided the metadata TMDB can complete.
+// Ratings are not required, recent movies often have none.
+func hasCompleteMetadata(jellyfinMovieItem *jellyfin.MovieItem) bool {
+	return jellyfinMovieItem.Overview != "" && jellyfinMovieItem.PosterURL != "" &&
+		len(jellyfinMovieItem.Genres) > 0 && jellyfinMovieItem.RuntimeMinutes > 0
+}
+
+// EnrichMovieItem completes the overview, the poster, the genres, the runtime and the community rating
+// missing in Jellyfin with TMDB. Without TMDB client, the missing overview and poster get default values.
+// The media is looked up on TMDB by TMDB id, else by IMDb or TVDB id, else by name.
 func EnrichMovieItem(
 	jellyfinMovieItem *jellyfin.MovieItem,
 	tmdbAPIClient APIInterface,
 	app *app.ApplicationContext,
 ) {
-	if jellyfinMovieItem.TMDBId != "" {
-		parsedHTTPResponse, err := tmdbAPIClient.GetMediaByID(jellyfinMovieItem.TMDBId, MediaTypeMovie)
+	if hasCompleteMetadata(jellyfinMovieItem) {
+		// Jellyfin metadata are complete, TMDB is not needed
+		return
+	}
+	if tmdbAPIClient == nil {
+		// TMDB is not configured
+		enrichMovieWithDefaultInfos(jellyfinMovieItem)
+		return
+	}
+	tmdbID := jellyfinMovieItem.TMDBId
+	if tmdbID == "" && (jellyfinMovieItem.IMDbId != "" || jellyfinMovieItem.TVDBId != "") {
+		// The IMDb and TVDB ids identify the movie more reliably than its name
+		foundTMDBID, err := findTMDBID(jellyfinMovieItem.IMDbId, jellyfinMovieItem.TVDBId, MediaTypeMovie, tmdbAPIClient)
+		if err != nil {
+			app.Logger.Warn(
+				"Impossible to find the movie on TMDB by its IMDb or TVDB id. It will be searched by name.",
+				zap.String("Movie Name", jellyfinMovieItem.Name),
+				zap.String("Movie ID", jellyfinMovieItem.ID),
+				zap.Error(err),
+			)
+		}
+		tmdbID = foundTMDBID
+	}
+	if tmdbID != "" {
+		parsedHTTPResponse, err := tmdbAPIClient.GetMediaByID(tmdbID, MediaTypeMovie)
 
 		if err != nil {
 			// Error is already logged by GetMediaByID
@@ -27,40 +72,38 @@ func EnrichMovieItem(
 		}
 
 		details := getItemDetailsFromHTTPResponse(parsedHTTPResponse)
-		jellyfinMovieItem.Overview = details.Overview
-		jellyfinMovieItem.PosterURL = details.PosterURL
+		completeMovieDetails(jellyfinMovieItem, details)
 		return
 	}
-	// No TMDB id, we perform a search by name and select the item with the highest popularity
+	// No TMDB id, we perform a search by name and select the result matching best the name and the year
 	app.Logger.Debug(
-		"Movie has no TMDB id. TMDB information will be retrieved by searching with Movie's name. If several media match, the choice will be based on the highest popularity.",
+		"Movie has no TMDB id and TMDB doesn't know its IMDb or TVDB id. TMDB information will be retrieved by searching with Movie's name. If several media match, the choice will be based on the title and the release year.",
 		zap.String("Series Name", jellyfinMovieItem.Name),
 		zap.String("Series ID", jellyfinMovieItem.ID),
 	)
 
-	searchResult, err := tmdbAPIClient.SearchMediaByName(
+	match := searchMedia(
 		jellyfinMovieItem.Name,
 		int(jellyfinMovieItem.ProductionYear),
 		MediaTypeMovie,
+		tmdbAPIClient,
+		app,
 	)
-
-	if err != nil {
-		// Error is already logged by SearchMediaByName
+	if match == nil {
 		enrichMovieWithDefaultInfos(jellyfinMovieItem)
 		return
 	}
-
-	details := getItemDetailsFromSearchResult(searchResult)
-	jellyfinMovieItem.Overview = details.Overview
-	jellyfinMovieItem.PosterURL = details.PosterURL
+	completeMovieDetails(jellyfinMovieItem, getItemDetailsFromHTTPResponse(match))
 }
 
+// EnrichMovieItemsList enriches the items concurrently, see enrichConcurrently.
+// tmdbAPIClient must be safe for concurrent use.
 func EnrichMovieItemsList(
 	jellyfinMovieItem *[]jellyfin.MovieItem,
 	tmdbAPIClient APIInterface,
 	app *app.ApplicationContext,
 ) {
-	for i := range *jellyfinMovieItem {
-		EnrichMovieItem(&(*jellyfinMovieItem)[i], tmdbAPIClient, app)
-	}
+	enrichConcurrently(*jellyfinMovieItem, func(item *jellyfin.MovieItem) {
+		EnrichMovieItem(item, tmdbAPIClient, app)
+	})
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,13 @@ func getTestClient(logger *zap.Logger, testServer *httptest.Server) APIClient {
 	}
 }
 
+func getTestApp(logger *zap.Logger) app.ApplicationContext {
+	return app.ApplicationContext{
+		Logger: logger,
+		Config: &config.Configuration{TMDB: config.TMDBConfig{Enabled: true, MatchConfidenceThreshold: 0.6}},
+	}
+}
+
 func getBaseJellyfinMovieItem() jellyfin.MovieItem {
 	additionDate := time.Date(2026, 01, 01, 01, 01, 01, 01, time.UTC)
 	return jellyfin.MovieItem{
@@ -204,9 +212,7 @@ func TestGetMovieDetailsWithTMDBID(t *testing.T) {
 			client := getTestClient(logger, testServer)
 			jellyfinMovieItem := getBaseJellyfinMovieItem()
 			jellyfinMovieItem.TMDBId = testCase.tmdbID
-			app := app.ApplicationContext{
-				Logger: logger,
-			}
+			app := getTestApp(logger)
 			EnrichMovieItem(&jellyfinMovieItem, client, &app)
 			if testCase.expectErr {
 				assert.NotEmpty(t, recordedLogs.All())
@@ -237,7 +243,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"overview": "This is the description of the most popular media", "popularity": 12.5, "poster_path":"/poster/popular/path"}]}`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"title": "Movie 1", "overview": "This is the description of the most popular media", "popularity": 12.5, "poster_path":"/poster/popular/path"}]}`,
 					),
 				)
 			}),
@@ -252,7 +258,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -282,7 +288,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of`,
 					),
 				)
 			}),
@@ -297,7 +303,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"popularity": 2.8, "poster_path":"/poster/path"}]}`,
+						`{"results": [{"title": "Movie 1", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -312,7 +318,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8}]}`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of a media", "popularity": 2.8}]}`,
 					),
 				)
 			}),
@@ -327,7 +333,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "poster_path":"/poster/path"}]}`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of a media", "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -342,7 +348,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of the most popular media", "poster_path":"/poster/popular/path"}, {"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of the most popular media", "poster_path":"/poster/popular/path"}, {"title": "Movie 1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -357,7 +363,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"overview": "This is the description of the most popular media","popularity": 2.8, "poster_path":"/poster/popular/path"}]}`,
+						`{"results": [{"title": "Movie 1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"title": "Movie 1", "overview": "This is the description of the most popular media","popularity": 2.8, "poster_path":"/poster/popular/path"}]}`,
 					),
 				)
 			}),
@@ -365,6 +371,21 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 			expectedPosterPath: "https://image.tmdb.org/t/p/w500/poster/path",
 			expectErr:          false,
 		},
+		{
+			name:      "Success - Only results of other media",
+			movieName: "Test movie",
+			testServerHandler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(
+					[]byte(
+						`{"results": [{"title": "Another movie", "release_date": "1999-01-01", "overview": "This is the description of another media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
+					),
+				)
+			}),
+			expectedOverview:   defaultOverview,
+			expectedPosterPath: defaultPosterURL,
+			expectErr:          true, // The rejected result is logged
+		},
 		{
 			name:      "Error - Connection reset",
 			movieName: "Test movie",
@@ -433,9 +454,7 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 			client := getTestClient(logger, testServer)
 			jellyfinMovieItem := getBaseJellyfinMovieItem()
 			jellyfinMovieItem.TMDBId = ""
-			app := app.ApplicationContext{
-				Logger: logger,
-			}
+			app := getTestApp(logger)
 			EnrichMovieItem(&jellyfinMovieItem, client, &app)
 			if testCase.expectErr {
 				assert.NotEmpty(t, recordedLogs.All())
@@ -447,3 +466,180 @@ func TestGetMovieDetailsWithSearchByName(t *testing.T) {
 		})
 	}
 }
+
+func TestEnrichMovieItemKeepsJellyfinMetadata(t *testing.T) {
+	isTMDBCalled := false
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		isTMDBCalled = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"overview": "TMDB overview", "popularity": 12.5, "poster_path":"/poster/path"}`))
+	}))
+	defer testServer.Close()
+	logger := zap.NewNop()
+	app := getTestApp(logger)
+
+	// Complete Jellyfin metadata: TMDB is not called
+	jellyfinMovieItem := getBaseJellyfinMovieItem()
+	jellyfinMovieItem.Overview = "Jellyfin overview"
+	jellyfinMovieItem.PosterURL = "https://jellyfin.example.com/Items/aa1111/Images/Primary"
+	jellyfinMovieItem.Genres = []string{"Drama"}
+	jellyfinMovieItem.RuntimeMinutes = 120
+	EnrichMovieItem(&jellyfinMovieItem, getTestClient(logger, testServer), &app)
+	assert.False(t, isTMDBCalled)
+	assert.Equal(t, "Jellyfin overview", jellyfinMovieItem.Overview)
+	assert.Equal(t, "https://jellyfin.example.com/Items/aa1111/Images/Primary", jellyfinMovieItem.PosterURL)
+
+	// Only the missing poster is taken from TMDB
+	jellyfinMovieItem.PosterURL = ""
+	EnrichMovieItem(&jellyfinMovieItem, getTestClient(logger, testServer), &app)
+	assert.True(t, isTMDBCalled)
+	assert.Equal(t, "Jellyfin overview", jellyfinMovieItem.Overview)
+	assert.Equal(t, "https://image.tmdb.org/t/p/w500/poster/path", jellyfinMovieItem.PosterURL)
+}
+
+func TestEnrichMovieItemCompletesRichMetadata(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"overview": "TMDB overview", "poster_path": "/poster/path", "vote_average": 8.4,
+			"runtime": 169, "genres": [{"id": 878, "name": "Science Fiction"}, {"id": 18, "name": "Drama"}]}`))
+	}))
+	defer testServer.Close()
+	logger := zap.NewNop()
+	app := getTestApp(logger)
+
+	jellyfinMovieItem := getBaseJellyfinMovieItem()
+	jellyfinMovieItem.Overview = "Jellyfin overview"
+	jellyfinMovieItem.PosterURL = "https://jellyfin.example.com/Items/aa1111/Images/Primary"
+	jellyfinMovieItem.CommunityRating = 7.9
+	EnrichMovieItem(&jellyfinMovieItem, getTestClient(logger, testServer), &app)
+
+	assert.Equal(t, "Jellyfin overview", jellyfinMovieItem.Overview)
+	assert.Equal(t, []string{"Science Fiction", "Drama"}, jellyfinMovieItem.Genres)
+	assert.Equal(t, 169, jellyfinMovieItem.RuntimeMinutes)
+	assert.InDelta(t, 7.9, jellyfinMovieItem.CommunityRating, 0.001) // Jellyfin rating is kept
+}
+
+func TestEnrichMovieItemWithoutTMDB(t *testing.T) {
+	app := getTestApp(zap.NewNop())
+	jellyfinMovieItem := getBaseJellyfinMovieItem()
+	jellyfinMovieItem.Overview = "Jellyfin overview"
+
+	EnrichMovieItem(&jellyfinMovieItem, nil, &app)
+
+	assert.Equal(t, "Jellyfin overview", jellyfinMovieItem.Overview)
+	assert.Equal(t, "https://placehold.co/200", jellyfinMovieItem.PosterURL)
+}
+
+// getExternalIDTestServer serves the movie 27205 and the series 1399 (with its season 2), found by the IMDb ids
+// "tt1375666" and "tt0944947" or by the TVDB id "121361". The paths of the received requests are recorded,
+// with the external source of the searches by external id.
+func getExternalIDTestServer(requestedPaths *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath := r.URL.Path
+		if source := r.URL.Query().Get("external_source"); source != "" {
+			requestedPath += "?external_source=" + source
+		}
+		*requestedPaths = append(*requestedPaths, requestedPath)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/find/tt1375666":
+			w.Write([]byte(`{"movie_results": [{"id": 27205}], "tv_results": []}`))
+		case "/find/tt0944947", "/find/121361":
+			w.Write([]byte(`{"movie_results": [], "tv_results": [{"id": 1399}]}`))
+		case "/find/tt0000000":
+			w.Write([]byte(`{"movie_results": [], "tv_results": []}`))
+		case "/movie/27205":
+			w.Write([]byte(`{"id": 27205, "overview": "Overview of 27205"}`))
+		case "/tv/1399":
+			w.Write([]byte(`{"id": 1399, "overview": "Overview of 1399"}`))
+		case "/tv/1399/season/2":
+			w.Write([]byte(`{"season_number": 2, "overview": "Overview of season 2 of 1399"}`))
+		case "/search/movie":
+			w.Write([]byte(`{"results": [{"title": "Movie 1", "overview": "Overview of the search result"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestEnrichMovieItemWithExternalID(t *testing.T) {
+	tests := []struct {
+		name             string
+		imdbID           string
+		expectedOverview string
+		expectedRequests []string
+	}{
+		{
+			name:             "Found by IMDb id",
+			imdbID:           "tt1375666",
+			expectedOverview: "Overview of 27205",
+			expectedRequests: []string{"/find/tt1375666?external_source=imdb_id", "/movie/27205"},
+		},
+		{
+			name:             "Unknown IMDb id, found by name",
+			imdbID:           "tt0000000",
+			expectedOverview: "Overview of the search result",
+			expectedRequests: []string{"/find/tt0000000?external_source=imdb_id", "/search/movie"},
+		},
+		{
+			name:             "IMDb lookup failed, found by name",
+			imdbID:           "tt9999999",
+			expectedOverview: "Overview of the search result",
+			expectedRequests: []string{"/find/tt9999999?external_source=imdb_id", "/search/movie"},
+		},
+		{
+			name:             "Series IMDb id, found by name",
+			imdbID:           "tt0944947",
+			expectedOverview: "Overview of the search result",
+			expectedRequests: []string{"/find/tt0944947?external_source=imdb_id", "/search/movie"},
+		},
+		{
+			name:             "No external id, found by name",
+			expectedOverview: "Overview of the search result",
+			expectedRequests: []string{"/search/movie"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			requestedPaths := []string{}
+			testServer := getExternalIDTestServer(&requestedPaths)
+			defer testServer.Close()
+			logger := zap.NewNop()
+			app := getTestApp(logger)
+
+			jellyfinMovieItem := getBaseJellyfinMovieItem()
+			jellyfinMovieItem.TMDBId = ""
+			jellyfinMovieItem.IMDbId = testCase.imdbID
+			EnrichMovieItem(&jellyfinMovieItem, getTestClient(logger, testServer), &app)
+
+			assert.Equal(t, testCase.expectedOverview, jellyfinMovieItem.Overview)
+			assert.Equal(t, testCase.expectedRequests, requestedPaths)
+		})
+	}
+}
+
+func TestEnrichMovieItemSearchedAgainWithoutYear(t *testing.T) {
+	searchedYears := []string{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searchedYears = append(searchedYears, r.URL.Query().Get("year"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Has("year") {
+			// TMDB filters the results by release year
+			w.Write([]byte(`{"results": []}`))
+			return
+		}
+		w.Write([]byte(`{"results": [{"id": 42, "title": "Movie 1", "release_date": "2025-11-20",
+			"overview": "Released the year before"}]}`))
+	}))
+	defer testServer.Close()
+	logger := zap.NewNop()
+	app := getTestApp(logger)
+
+	jellyfinMovieItem := getBaseJellyfinMovieItem()
+	jellyfinMovieItem.TMDBId = ""
+	EnrichMovieItem(&jellyfinMovieItem, getTestClient(logger, testServer), &app)
+
+	assert.Equal(t, []string{"2026", ""}, searchedYears)
+	assert.Equal(t, "Released the year before", jellyfinMovieItem.Overview)
+}
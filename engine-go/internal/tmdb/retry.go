@@ -0,0 +1,89 @@
+package tmdb
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	// Longer Retry-After delays are not waited for, the request fails instead.
+	maxRetryAfter = time.Minute
+)
+
+// parseRetryAfter returns the delay of a Retry-After header, given in seconds or as an HTTP date.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryDelay tells whether the request must be sent again, and after which delay.
+// Rate limited requests (429) are retried after their Retry-After delay, server errors (5xx) and
+// network errors after an exponential backoff.
+func (client APIClient) retryDelay(resp *http.Response, httpErr error, attempt int) (time.Duration, bool) {
+	if attempt >= client.MaxRetries {
+		return 0, false
+	}
+	backoff := client.RetryBaseDelay << attempt
+	switch {
+	case httpErr != nil:
+		return backoff, true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			return backoff, true
+		}
+		if delay > maxRetryAfter {
+			return 0, false
+		}
+		// The other requests would be rate limited as well
+		client.RateLimiter.Pause(delay)
+		return delay, true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return backoff, true
+	default:
+		return 0, false
+	}
+}
+
+// doRequest sends request within the rate limit, and retries it when TMDB is rate limiting or unavailable.
+// The response or the error of the last attempt is returned, to be checked with checkHTTPResponse.
+func (client APIClient) doRequest(request *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		client.RateLimiter.Wait()
+		resp, err := client.HTTPClient.Do(request)
+		delay, retry := client.retryDelay(resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		fields := []zap.Field{
+			zap.String("URL", request.URL.String()),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("retry in", delay),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		} else {
+			fields = append(fields, zap.Int("status", resp.StatusCode))
+			// The body is drained so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		client.Logger.Warn("TMDB request failed. It will be retried.", fields...)
+		time.Sleep(delay)
+	}
+}
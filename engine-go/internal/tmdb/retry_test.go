@@ -0,0 +1,152 @@
+package tmdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// getRetryingTestClient returns a test client retrying the failed requests twice, without waiting.
+func getRetryingTestClient(testServer *httptest.Server) APIClient {
+	client := getTestClient(zap.NewNop(), testServer)
+	client.MaxRetries = 2
+	client.RetryBaseDelay = time.Millisecond
+	return client
+}
+
+func TestDoRequest_Retries(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      []int
+		expectedCalls int32
+		expectErr     bool
+	}{
+		{name: "Rate limited, then success", failures: []int{http.StatusTooManyRequests}, expectedCalls: 2},
+		{
+			name:          "Server errors, then success",
+			failures:      []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			expectedCalls: 3,
+		},
+		{
+			name: "Server errors on every attempt",
+			failures: []int{
+				http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError,
+			},
+			expectedCalls: 3,
+			expectErr:     true,
+		},
+		{name: "Client errors are not retried", failures: []int{http.StatusNotFound}, expectedCalls: 1, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				call := int(calls.Add(1))
+				if call <= len(tt.failures) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.failures[call-1])
+					return
+				}
+				w.Write([]byte(`{"overview": "This is the description of a media"}`))
+			}))
+			defer testServer.Close()
+
+			response, err := getRetryingTestClient(testServer).GetMediaByID("12345", MediaTypeMovie)
+
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "This is the description of a media", response.Overview)
+		})
+	}
+}
+
+func TestDoRequest_LongRetryAfterIsNotWaitedFor(t *testing.T) {
+	var calls atomic.Int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer testServer.Close()
+
+	_, err := getRetryingTestClient(testServer).SearchMediaByName("Movie", 2026, MediaTypeMovie)
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("2", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	delay, ok = parseRetryAfter(now.Add(5*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, delay)
+
+	delay, ok = parseRetryAfter(now.Add(-time.Hour).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
+
+func TestInitTMDBApiClient_ProfilesShareTheRateLimiter(t *testing.T) {
+	rootApp := app.InitApplicationContext(&config.Configuration{
+		Profiles: []config.ProfileConfig{
+			{Name: "family", EmailTemplate: config.EmailTemplateConfig{Language: "en"}},
+			{Name: "friends", EmailTemplate: config.EmailTemplateConfig{Language: "fr"}},
+		},
+	}, zap.NewNop(), nil, nil)
+	newsletterApps, err := rootApp.NewsletterContexts()
+	require.NoError(t, err)
+	require.Len(t, newsletterApps, 2)
+
+	limiter := InitTMDBApiClient(http.DefaultClient, rootApp).RateLimiter
+	require.NotNil(t, limiter)
+	for _, newsletterApp := range newsletterApps {
+		assert.Same(t, limiter, InitTMDBApiClient(http.DefaultClient, newsletterApp).RateLimiter)
+	}
+}
+
+func TestEnrichMovieItemsList_KeepsOrder(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		w.Write([]byte(`{"overview": "Overview of ` + id + `"}`))
+	}))
+	defer testServer.Close()
+
+	movies := []jellyfin.MovieItem{}
+	for i := range 50 {
+		movies = append(movies, jellyfin.MovieItem{ID: strconv.Itoa(i), TMDBId: strconv.Itoa(i)})
+	}
+
+	EnrichMovieItemsList(&movies, getRetryingTestClient(testServer), new(getTestApp(zap.NewNop())))
+
+	require.Len(t, movies, 50)
+	for i, movie := range movies {
+		assert.Equal(t, strconv.Itoa(i), movie.ID)
+		assert.Equal(t, "Overview of "+strconv.Itoa(i), movie.Overview)
+	}
+}
@@ -1,67 +1,188 @@
 package tmdb
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
 	"go.uber.org/zap"
 )
 
 func enrichSeriesItemWithDefaultInfos(jellyfinSeriesItem *jellyfin.NewlyAddedSeriesItem) {
-	defaultDetails := getDefaultItemDetails()
-	jellyfinSeriesItem.Overview = defaultDetails.Overview
-	jellyfinSeriesItem.PosterURL = defaultDetails.PosterURL
+	completeItemDetails(&jellyfinSeriesItem.Overview, &jellyfinSeriesItem.PosterURL, getDefaultItemDetails())
 }
 
+// EnrichSeriesItem completes the overview and the poster missing in Jellyfin with TMDB, as well as the details
+// of the new seasons and episodes (see completeNewSeasonsDetails).
+// Without TMDB client, the missing overview and poster get default values.
 func EnrichSeriesItem(
 	jellyfinSeriesItem *jellyfin.NewlyAddedSeriesItem,
 	tmdbAPIClient APIInterface,
 	app *app.ApplicationContext,
 ) {
-	if jellyfinSeriesItem.TMDBId != "" {
-		parsedHTTPResponse, err := tmdbAPIClient.GetMediaByID(jellyfinSeriesItem.TMDBId, MediaTypeSeries)
+	if tmdbAPIClient == nil {
+		// TMDB is not configured
+		enrichSeriesItemWithDefaultInfos(jellyfinSeriesItem)
+		return
+	}
+	areSeriesDetailsMissing := jellyfinSeriesItem.Overview == "" || jellyfinSeriesItem.PosterURL == ""
+	areSeasonsDetailed := !jellyfinSeriesItem.IsSeriesNew && len(jellyfinSeriesItem.NewSeasons) > 0
+	if !areSeriesDetailsMissing && !areSeasonsDetailed {
+		// Jellyfin metadata are complete, TMDB is not needed
+		return
+	}
 
+	tmdbID, searchMatch := findSeriesTMDBID(jellyfinSeriesItem, tmdbAPIClient, app)
+	if areSeriesDetailsMissing {
+		completeSeriesDetails(jellyfinSeriesItem, tmdbID, searchMatch, tmdbAPIClient)
+	}
+	completeNewSeasonsDetails(jellyfinSeriesItem, tmdbID, tmdbAPIClient)
+}
+
+// findSeriesTMDBID returns the TMDB id of the series, empty if the series is not found.
+// The series is looked up on TMDB by TMDB id, else by IMDb or TVDB id, else by name. When found by name,
+// the selected search result is returned as well, it already holds the series details.
+func findSeriesTMDBID(
+	jellyfinSeriesItem *jellyfin.NewlyAddedSeriesItem,
+	tmdbAPIClient APIInterface,
+	app *app.ApplicationContext,
+) (string, *GetMediaHTTPResponse) {
+	if jellyfinSeriesItem.TMDBId != "" {
+		return jellyfinSeriesItem.TMDBId, nil
+	}
+	if jellyfinSeriesItem.IMDbId != "" || jellyfinSeriesItem.TVDBId != "" {
+		// The IMDb and TVDB ids identify the series more reliably than its name
+		foundTMDBID, err := findTMDBID(
+			jellyfinSeriesItem.IMDbId,
+			jellyfinSeriesItem.TVDBId,
+			MediaTypeSeries,
+			tmdbAPIClient,
+		)
 		if err != nil {
-			// Error is already logged by GetMediaByID
-			enrichSeriesItemWithDefaultInfos(jellyfinSeriesItem)
-			return
+			app.Logger.Warn(
+				"Impossible to find the series on TMDB by its IMDb or TVDB id. It will be searched by name.",
+				zap.String("Series Name", jellyfinSeriesItem.SeriesName),
+				zap.String("Series ID", jellyfinSeriesItem.SeriesID),
+				zap.Error(err),
+			)
+		}
+		if foundTMDBID != "" {
+			return foundTMDBID, nil
 		}
-
-		details := getItemDetailsFromHTTPResponse(parsedHTTPResponse)
-		jellyfinSeriesItem.Overview = details.Overview
-		jellyfinSeriesItem.PosterURL = details.PosterURL
-		return
 	}
-	// No TMDB id, we perform a search by name and select the item with the highest popularity
+	// No TMDB id, we perform a search by name and select the result matching best the name and the year
 	app.Logger.Debug(
-		"Series has no TMDB id. TMDB information will be retrieved by searching with Series's name. If several media match, the choice will be based on the highest popularity.",
+		"Series has no TMDB id and TMDB doesn't know its IMDb or TVDB id. TMDB information will be retrieved by searching with Series's name. If several media match, the choice will be based on the title and the release year.",
 		zap.String("Series Name", jellyfinSeriesItem.SeriesName),
 		zap.String("Series ID", jellyfinSeriesItem.SeriesID),
 	)
 
-	searchResult, err := tmdbAPIClient.SearchMediaByName(
+	match := searchMedia(
 		jellyfinSeriesItem.SeriesName,
 		jellyfinSeriesItem.ProductionYear,
 		MediaTypeSeries,
+		tmdbAPIClient,
+		app,
 	)
+	if match == nil {
+		return "", nil
+	}
+	return strconv.Itoa(match.ID), match
+}
 
+// completeSeriesDetails completes the overview and the poster of the series with the search result
+// it has been found with, else with the TMDB details of tmdbID. If the series is not found on TMDB,
+// default values are used.
+func completeSeriesDetails(
+	jellyfinSeriesItem *jellyfin.NewlyAddedSeriesItem,
+	tmdbID string,
+	searchMatch *GetMediaHTTPResponse,
+	tmdbAPIClient APIInterface,
+) {
+	if searchMatch != nil {
+		details := getItemDetailsFromHTTPResponse(searchMatch)
+		completeItemDetails(&jellyfinSeriesItem.Overview, &jellyfinSeriesItem.PosterURL, details)
+		return
+	}
+	if tmdbID == "" {
+		enrichSeriesItemWithDefaultInfos(jellyfinSeriesItem)
+		return
+	}
+
+	parsedHTTPResponse, err := tmdbAPIClient.GetMediaByID(tmdbID, MediaTypeSeries)
 	if err != nil {
-		// Error is already logged by SearchMediaByName
+		// Error is already logged by GetMediaByID
 		enrichSeriesItemWithDefaultInfos(jellyfinSeriesItem)
 		return
 	}
+	details := getItemDetailsFromHTTPResponse(parsedHTTPResponse)
+	completeItemDetails(&jellyfinSeriesItem.Overview, &jellyfinSeriesItem.PosterURL, details)
+}
+
+// completeNewSeasonsDetails completes the overview and the poster of the new seasons, and the name and
+// the air date of their new episodes, with the season details of TMDB. The seasons of a brand new series
+// are not detailed, the series is presented as a whole.
+func completeNewSeasonsDetails(
+	jellyfinSeriesItem *jellyfin.NewlyAddedSeriesItem,
+	tmdbID string,
+	tmdbAPIClient APIInterface,
+) {
+	if jellyfinSeriesItem.IsSeriesNew || tmdbID == "" {
+		return
+	}
+	for seasonID, season := range jellyfinSeriesItem.NewSeasons {
+		if season.IsSeasonNew && season.Overview != "" && season.PosterURL != "" {
+			// Jellyfin metadata are complete, TMDB is not needed
+			continue
+		}
+		seasonDetails, err := tmdbAPIClient.GetSeasonByNumber(tmdbID, int(season.SeasonNumber))
+		if err != nil {
+			// Error is already logged by GetSeasonByNumber
+			continue
+		}
+		completeSeasonDetails(&season, seasonDetails)
+		jellyfinSeriesItem.NewSeasons[seasonID] = season
+	}
+}
 
-	details := getItemDetailsFromSearchResult(searchResult)
+// completeSeasonDetails fills the season overview and poster, and the episode names and air dates the season has
+// no value for. Without TMDB poster, the season keeps no poster, so the series one is shown instead.
+func completeSeasonDetails(season *jellyfin.SeasonItem, seasonDetails *GetSeasonHTTPResponse) {
+	if season.Overview == "" {
+		season.Overview = seasonDetails.Overview
+	}
+	if season.PosterURL == "" && seasonDetails.PosterPath != "" {
+		season.PosterURL = posterBaseURL + seasonDetails.PosterPath
+	}
 
-	jellyfinSeriesItem.Overview = details.Overview
-	jellyfinSeriesItem.PosterURL = details.PosterURL
+	episodesDetails := map[int]EpisodeHTTPResponse{}
+	for _, episodeDetails := range seasonDetails.Episodes {
+		episodesDetails[episodeDetails.EpisodeNumber] = episodeDetails
+	}
+	for episodeID, episode := range season.Episodes {
+		episodeDetails, ok := episodesDetails[int(episode.EpisodeNumber)]
+		if !ok {
+			continue
+		}
+		if episode.Name == "" {
+			episode.Name = episodeDetails.Name
+		}
+		if airDate, err := time.Parse(time.DateOnly, episodeDetails.AirDate); err == nil && episode.AirDate.IsZero() {
+			episode.AirDate = airDate
+		}
+		season.Episodes[episodeID] = episode
+	}
 }
 
+// EnrichSeriesItemsList enriches the items concurrently, see enrichConcurrently.
+// tmdbAPIClient must be safe for concurrent use.
 func EnrichSeriesItemsList(
 	jellyfinSeriesItem *[]jellyfin.NewlyAddedSeriesItem,
 	tmdbAPIClient APIInterface,
 	app *app.ApplicationContext,
 ) {
-	for i := range *jellyfinSeriesItem {
-		EnrichSeriesItem(&(*jellyfinSeriesItem)[i], tmdbAPIClient, app)
-	}
+	enrichConcurrently(*jellyfinSeriesItem, func(item *jellyfin.NewlyAddedSeriesItem) {
+		EnrichSeriesItem(item, tmdbAPIClient, app)
+	})
 }
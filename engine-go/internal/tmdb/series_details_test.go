@@ -6,7 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -204,9 +203,7 @@ func TestGetSeriesDetailsWithTMDBID(t *testing.T) {
 			client := getSeriesDetailsTestClient(logger, testServer)
 			jellyfinSeriesItem := getBaseJellyfinSeriesItem()
 			jellyfinSeriesItem.TMDBId = testCase.tmdbID
-			app := app.ApplicationContext{
-				Logger: logger,
-			}
+			app := getTestApp(logger)
 			EnrichSeriesItem(&jellyfinSeriesItem, client, &app)
 			if testCase.expectErr {
 				assert.NotEmpty(t, recordedLogs.All())
@@ -237,7 +234,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"overview": "This is the description of the most popular media", "popularity": 12.5, "poster_path":"/poster/popular/path"}]}`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"name": "Series1", "overview": "This is the description of the most popular media", "popularity": 12.5, "poster_path":"/poster/popular/path"}]}`,
 					),
 				)
 			}),
@@ -252,7 +249,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -282,7 +279,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of`,
 					),
 				)
 			}),
@@ -297,7 +294,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"popularity": 2.8, "poster_path":"/poster/path"}]}`,
+						`{"results": [{"name": "Series1", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -312,7 +309,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8}]}`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of a media", "popularity": 2.8}]}`,
 					),
 				)
 			}),
@@ -327,7 +324,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "poster_path":"/poster/path"}]}`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of a media", "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -342,7 +339,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of the most popular media", "poster_path":"/poster/popular/path"}, {"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of the most popular media", "poster_path":"/poster/popular/path"}, {"name": "Series1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}]}`,
 					),
 				)
 			}),
@@ -357,7 +354,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write(
 					[]byte(
-						`{"results": [{"overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"overview": "This is the description of the most popular media","popularity": 2.8, "poster_path":"/poster/popular/path"}]}`,
+						`{"results": [{"name": "Series1", "overview": "This is the description of a media", "popularity": 2.8, "poster_path":"/poster/path"}, {"name": "Series1", "overview": "This is the description of the most popular media","popularity": 2.8, "poster_path":"/poster/popular/path"}]}`,
 					),
 				)
 			}),
@@ -433,9 +430,7 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 			client := getSeriesDetailsTestClient(logger, testServer)
 			jellyfinSeriesItem := getBaseJellyfinSeriesItem()
 			jellyfinSeriesItem.TMDBId = ""
-			app := app.ApplicationContext{
-				Logger: logger,
-			}
+			app := getTestApp(logger)
 			EnrichSeriesItem(&jellyfinSeriesItem, client, &app)
 			if testCase.expectErr {
 				assert.NotEmpty(t, recordedLogs.All())
@@ -447,3 +442,179 @@ func TestGetSeriesDetailsWithSearchByName(t *testing.T) {
 		})
 	}
 }
+
+func TestEnrichSeriesItemWithoutTMDB(t *testing.T) {
+	app := getTestApp(zap.NewNop())
+	jellyfinSeriesItem := getBaseJellyfinSeriesItem()
+	jellyfinSeriesItem.PosterURL = "https://jellyfin.example.com/Items/aa1111/Images/Primary"
+
+	EnrichSeriesItem(&jellyfinSeriesItem, nil, &app)
+
+	assert.Equal(t, "No description available.", jellyfinSeriesItem.Overview)
+	assert.Equal(t, "https://jellyfin.example.com/Items/aa1111/Images/Primary", jellyfinSeriesItem.PosterURL)
+}
+
+func TestEnrichSeriesItemWithExternalID(t *testing.T) {
+	tests := []struct {
+		name             string
+		imdbID           string
+		tvdbID           string
+		expectedRequests []string
+	}{
+		{
+			name:             "Found by IMDb id",
+			imdbID:           "tt0944947",
+			expectedRequests: []string{"/find/tt0944947?external_source=imdb_id", "/tv/1399"},
+		},
+		{
+			name:   "Unknown IMDb id, found by TVDB id",
+			imdbID: "tt0000000",
+			tvdbID: "121361",
+			expectedRequests: []string{
+				"/find/tt0000000?external_source=imdb_id",
+				"/find/121361?external_source=tvdb_id",
+				"/tv/1399",
+			},
+		},
+		{
+			name:   "IMDb lookup failed, found by TVDB id",
+			imdbID: "tt9999999",
+			tvdbID: "121361",
+			expectedRequests: []string{
+				"/find/tt9999999?external_source=imdb_id",
+				"/find/121361?external_source=tvdb_id",
+				"/tv/1399",
+			},
+		},
+		{
+			name:             "Found by TVDB id",
+			tvdbID:           "121361",
+			expectedRequests: []string{"/find/121361?external_source=tvdb_id", "/tv/1399"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			requestedPaths := []string{}
+			testServer := getExternalIDTestServer(&requestedPaths)
+			defer testServer.Close()
+			logger := zap.NewNop()
+			app := getTestApp(logger)
+
+			jellyfinSeriesItem := getBaseJellyfinSeriesItem()
+			jellyfinSeriesItem.TMDBId = ""
+			jellyfinSeriesItem.IMDbId = testCase.imdbID
+			jellyfinSeriesItem.TVDBId = testCase.tvdbID
+			EnrichSeriesItem(&jellyfinSeriesItem, getSeriesDetailsTestClient(logger, testServer), &app)
+
+			assert.Equal(t, "Overview of 1399", jellyfinSeriesItem.Overview)
+			assert.Equal(t, testCase.expectedRequests, requestedPaths)
+		})
+	}
+}
+
+func TestEnrichSeriesItemWithNewSeasons(t *testing.T) {
+	requestedPaths := []string{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		switch r.URL.Path {
+		case "/tv/1234/season/2":
+			w.Write([]byte(`{
+				"season_number": 2,
+				"overview": "Overview of season 2",
+				"poster_path": "/season2.jpg",
+				"episodes": [
+					{"episode_number": 4, "name": "Episode title 4", "air_date": "2026-02-04"},
+					{"episode_number": 5, "name": "Episode title 5", "air_date": "2026-02-11"}
+				]
+			}`))
+		case "/tv/1234/season/3":
+			w.Write([]byte(`{"season_number": 3, "overview": "Overview of season 3", "episodes": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	logger := zap.NewNop()
+	app := getTestApp(logger)
+
+	jellyfinSeriesItem := getBaseJellyfinSeriesItem()
+	jellyfinSeriesItem.Overview = "Overview of the series"
+	jellyfinSeriesItem.PosterURL = "https://jellyfin.example.com/Items/aa1111/Images/Primary"
+	jellyfinSeriesItem.NewSeasons = map[string]jellyfin.SeasonItem{
+		"season2": {
+			SeasonNumber: 2,
+			Episodes: map[string]jellyfin.EpisodeItem{
+				"episode4": {EpisodeNumber: 4},
+				"episode5": {EpisodeNumber: 5, Name: "Name in Jellyfin"},
+			},
+		},
+		"season3": {SeasonNumber: 3, IsSeasonNew: true},
+		"season4": {SeasonNumber: 4, IsSeasonNew: true, Overview: "Overview in Jellyfin", PosterURL: "/jellyfin.jpg"},
+	}
+
+	EnrichSeriesItem(&jellyfinSeriesItem, getSeriesDetailsTestClient(logger, testServer), &app)
+
+	assert.ElementsMatch(t, []string{"/tv/1234/season/2", "/tv/1234/season/3"}, requestedPaths,
+		"the series details are complete, and season 4 is complete in Jellyfin")
+
+	season2 := jellyfinSeriesItem.NewSeasons["season2"]
+	assert.Equal(t, "Overview of season 2", season2.Overview)
+	assert.Equal(t, "https://image.tmdb.org/t/p/w500/season2.jpg", season2.PosterURL)
+	assert.Equal(t, "Episode title 4", season2.Episodes["episode4"].Name)
+	assert.Equal(t, time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), season2.Episodes["episode4"].AirDate)
+	assert.Equal(t, "Name in Jellyfin", season2.Episodes["episode5"].Name)
+	assert.Equal(t, time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC), season2.Episodes["episode5"].AirDate)
+
+	season3 := jellyfinSeriesItem.NewSeasons["season3"]
+	assert.Equal(t, "Overview of season 3", season3.Overview)
+	assert.Empty(t, season3.PosterURL, "without season poster, the series poster is shown")
+
+	assert.Equal(t, "Overview in Jellyfin", jellyfinSeriesItem.NewSeasons["season4"].Overview)
+}
+
+func TestEnrichSeriesItemWithNewSeasons_WholeNewSeries(t *testing.T) {
+	requestedPaths := []string{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+	}))
+	defer testServer.Close()
+	logger := zap.NewNop()
+	app := getTestApp(logger)
+
+	jellyfinSeriesItem := getBaseJellyfinSeriesItem()
+	jellyfinSeriesItem.Overview = "Overview of the series"
+	jellyfinSeriesItem.PosterURL = "https://jellyfin.example.com/Items/aa1111/Images/Primary"
+	jellyfinSeriesItem.IsSeriesNew = true
+	jellyfinSeriesItem.NewSeasons = map[string]jellyfin.SeasonItem{"season1": {SeasonNumber: 1, IsSeasonNew: true}}
+
+	EnrichSeriesItem(&jellyfinSeriesItem, getSeriesDetailsTestClient(logger, testServer), &app)
+
+	assert.Empty(t, requestedPaths, "a whole new series is presented as a whole")
+}
+
+func TestEnrichSeriesItemWithNewSeasons_CompleteSeriesFoundByExternalID(t *testing.T) {
+	requestedPaths := []string{}
+	testServer := getExternalIDTestServer(&requestedPaths)
+	defer testServer.Close()
+	logger := zap.NewNop()
+	app := getTestApp(logger)
+
+	jellyfinSeriesItem := getBaseJellyfinSeriesItem()
+	jellyfinSeriesItem.TMDBId = ""
+	jellyfinSeriesItem.IMDbId = "tt0944947"
+	jellyfinSeriesItem.Overview = "Overview of the series"
+	jellyfinSeriesItem.PosterURL = "https://jellyfin.example.com/Items/aa1111/Images/Primary"
+	jellyfinSeriesItem.NewSeasons = map[string]jellyfin.SeasonItem{"season2": {SeasonNumber: 2, IsSeasonNew: true}}
+
+	EnrichSeriesItem(&jellyfinSeriesItem, getSeriesDetailsTestClient(logger, testServer), &app)
+
+	assert.Equal(
+		t,
+		[]string{"/find/tt0944947?external_source=imdb_id", "/tv/1399/season/2"},
+		requestedPaths,
+		"the series details are complete in Jellyfin, only the season details are requested",
+	)
+	assert.Equal(t, "Overview of the series", jellyfinSeriesItem.Overview)
+	assert.Equal(t, "Overview of season 2 of 1399", jellyfinSeriesItem.NewSeasons["season2"].Overview)
+}
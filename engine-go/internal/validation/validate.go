@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/template"
+	"go.uber.org/zap"
+)
+
+// ValidateConfiguration checks the configuration file and everything that depends on it
+// (language, theme and placeholders), and returns all the problems found.
+// An empty slice means the configuration is valid.
+func ValidateConfiguration(configPath string, themesDir string) []config.Problem {
+	report := config.ValidateConfigFile(configPath, themesDir, os.LookupEnv)
+	if report.Config == nil {
+		return report.Problems
+	}
+	conf := report.Config
+
+	validateEmailTemplate(report, conf, nil, "")
+	// Profiles values are only checked when they differ from the top-level ones, to avoid reporting twice the same problem
+	for i, profile := range conf.Profiles {
+		validateEmailTemplate(report, conf.ForProfile(profile), conf, fmt.Sprintf("profiles[%d].", i))
+	}
+	for i, jellyfinUser := range conf.JellyfinUsers {
+		if !conf.IsNewsletterRecipient(jellyfinUser.Recipient) {
+			report.AddProblem(
+				fmt.Sprintf("jellyfin_users[%d].recipient", i),
+				"'"+jellyfinUser.Recipient+"' is not a newsletter recipient. Add it to the recipients to send it a newsletter",
+			)
+		}
+	}
+
+	return report.Problems
+}
+
+// validateEmailTemplate checks the language, theme and placeholders of conf. Problems are reported at pathPrefix.
+// If defaultConf isn't nil, values equal to the default ones are not checked.
+func validateEmailTemplate(
+	report *config.ValidationReport,
+	conf *config.Configuration,
+	defaultConf *config.Configuration,
+	pathPrefix string,
+) {
+	isInherited := func(value func(c *config.Configuration) string) bool {
+		return defaultConf != nil && value(conf) == value(defaultConf)
+	}
+
+	localizer, err := i18n.NewLocalizer(conf.EmailTemplate.Language)
+	if err != nil && !isInherited(func(c *config.Configuration) string { return c.EmailTemplate.Language }) {
+		report.AddProblem(pathPrefix+"email_template.language", err.Error())
+	}
+
+	validationApp := app.InitApplicationContext(conf, zap.NewNop(), localizer, clock.RealClock{})
+
+	err = template.CheckIfThemeIsAvailable(validationApp)
+	if err != nil && !isInherited(func(c *config.Configuration) string { return c.EmailTemplate.Theme }) {
+		report.AddProblem(
+			pathPrefix+"email_template.theme",
+			"theme '"+conf.EmailTemplate.Theme+"' is not available: "+err.Error(),
+		)
+	}
+
+	placeholderTemplates := []struct {
+		key   string
+		value func(c *config.Configuration) string
+	}{
+		{key: "email_template.subject", value: func(c *config.Configuration) string { return c.EmailTemplate.Subject }},
+		{key: "email_template.title", value: func(c *config.Configuration) string { return c.EmailTemplate.Title }},
+		{key: "email_template.subtitle", value: func(c *config.Configuration) string { return c.EmailTemplate.Subtitle }},
+	}
+	for _, placeholderTemplate := range placeholderTemplates {
+		if isInherited(placeholderTemplate.value) {
+			continue
+		}
+		_, err = template.BuildEmailTitleWithPlaceholders(
+			placeholderTemplate.value(conf),
+			conf.Jellyfin.ObservedPeriodDays,
+			validationApp,
+		)
+		if err != nil {
+			report.AddProblem(pathPrefix+placeholderTemplate.key, "invalid placeholder: "+err.Error())
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	"go.uber.org/zap"
+)
+
+const (
+	Path         = "/webhook"
+	SecretHeader = "X-Webhook-Secret"
+
+	itemAddedNotificationType = "ItemAdded"
+	maxPayloadBytes           = 64 * 1024
+	readHeaderTimeout         = 10 * time.Second
+)
+
+// NewslettersProvider returns the context of each newsletter to send (see app.ApplicationContext.NewsletterContexts).
+// It is called on each request, so the events are queued for the newsletters of the latest loaded configuration.
+type NewslettersProvider func() []*app.ApplicationContext
+
+// payload is the body sent by the Jellyfin Webhook plugin. The plugin template is free,
+// only these fields are read (see the README for the expected template).
+type payload struct {
+	NotificationType string `json:"NotificationType"`
+	ItemID           string `json:"ItemId"`
+	ItemType         string `json:"ItemType"`
+	Name             string `json:"Name"`
+}
+
+// Handler receives the notifications of the Jellyfin Webhook plugin.
+// ItemAdded notifications are queued for the next run of each newsletter, the other ones are ignored.
+type Handler struct {
+	rootApp     *app.ApplicationContext
+	newsletters NewslettersProvider
+}
+
+func NewHandler(rootApp *app.ApplicationContext, newsletters NewslettersProvider) Handler {
+	return Handler{rootApp: rootApp, newsletters: newsletters}
+}
+
+func (handler Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	logger := handler.rootApp.Logger
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", http.MethodPost)
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	expectedSecret := handler.rootApp.Config.Webhook.Secret.SafeString()
+	receivedSecret := request.Header.Get(SecretHeader)
+	if subtle.ConstantTimeCompare([]byte(receivedSecret), []byte(expectedSecret)) != 1 {
+		logger.Warn("Webhook request rejected because of an invalid secret.", zap.String("remote", request.RemoteAddr))
+		http.Error(writer, "invalid secret", http.StatusUnauthorized)
+		return
+	}
+
+	var notification payload
+	err := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxPayloadBytes)).Decode(&notification)
+	if err != nil {
+		logger.Warn("Webhook request rejected because of an invalid payload.", zap.Error(err))
+		http.Error(writer, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	if notification.NotificationType != itemAddedNotificationType {
+		logger.Debug("Webhook notification ignored.", zap.String("NotificationType", notification.NotificationType))
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if notification.ItemID == "" {
+		http.Error(writer, "ItemId is required", http.StatusBadRequest)
+		return
+	}
+
+	for _, newsletterApp := range handler.newsletters() {
+		event := persistentdata.WebhookEvent{
+			ItemID:     notification.ItemID,
+			ItemType:   notification.ItemType,
+			Name:       notification.Name,
+			ReceivedAt: newsletterApp.Clock.Now().UTC(),
+		}
+		if err = persistentdata.AppendWebhookEvent(event, newsletterApp); err != nil {
+			newsletterApp.Logger.Error("Failed to queue the webhook event.", zap.Error(err))
+			http.Error(writer, "failed to queue the event", http.StatusInternalServerError)
+			return
+		}
+	}
+	logger.Info(
+		"Item added to Jellyfin queued for the next newsletter.",
+		zap.String("ItemID", notification.ItemID),
+		zap.String("ItemType", notification.ItemType),
+		zap.String("Name", notification.Name),
+	)
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// StartListener serves the webhook handler on the configured listen address, in the background.
+// The application exits if the listener cannot be started (e.g. the port is already used).
+func StartListener(rootApp *app.ApplicationContext, newsletters NewslettersProvider) {
+	mux := http.NewServeMux()
+	mux.Handle(Path, NewHandler(rootApp, newsletters))
+	server := &http.Server{
+		Addr:              rootApp.Config.Webhook.ListenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	rootApp.Logger.Info(
+		"Listening for Jellyfin webhook notifications.",
+		zap.String("address", server.Addr),
+		zap.String("path", Path),
+	)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			rootApp.Logger.Fatal("Webhook listener stopped.", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
+	persistentdata "github.com/SeaweedbrainCY/jellyfin-newsletter/internal/persistentData"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testSecret = "a-very-long-webhook-secret"
+
+type fakeClock struct{}
+
+func (fakeClock) Now() time.Time {
+	return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func initApp(t *testing.T) *app.ApplicationContext {
+	t.Helper()
+	return &app.ApplicationContext{
+		Logger: zap.NewNop(),
+		Clock:  fakeClock{},
+		Config: &config.Configuration{
+			ConfigFilePath: filepath.Join(t.TempDir(), "config.yaml"),
+			Webhook: config.WebhookConfig{
+				Enabled: true,
+				Secret:  testSecret,
+				Mode:    config.WebhookModeMerge,
+			},
+		},
+	}
+}
+
+// withProfile returns a copy of rootApp for the given profile, sharing the same config folder.
+func withProfile(rootApp *app.ApplicationContext, profileName string) *app.ApplicationContext {
+	profileConfig := *rootApp.Config
+	profileConfig.ProfileName = profileName
+	return &app.ApplicationContext{Logger: rootApp.Logger, Clock: rootApp.Clock, Config: &profileConfig}
+}
+
+func postNotification(handler http.Handler, secret string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(body))
+	request.Header.Set(SecretHeader, secret)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestHandlerQueuesItemAddedEventForEachNewsletter(t *testing.T) {
+	rootApp := initApp(t)
+	newsletters := []*app.ApplicationContext{withProfile(rootApp, "family"), withProfile(rootApp, "friends")}
+	handler := NewHandler(rootApp, func() []*app.ApplicationContext { return newsletters })
+
+	response := postNotification(
+		handler,
+		testSecret,
+		`{"NotificationType":"ItemAdded","ItemId":"8b54388aca994d4fb867944d3150a7e0","ItemType":"Movie","Name":"Dune"}`,
+	)
+
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	expectedEvent := persistentdata.WebhookEvent{
+		ItemID:     "8b54388aca994d4fb867944d3150a7e0",
+		ItemType:   "Movie",
+		Name:       "Dune",
+		ReceivedAt: fakeClock{}.Now(),
+	}
+	for _, newsletterApp := range newsletters {
+		events, err := persistentdata.GetWebhookEvents(newsletterApp)
+		require.NoError(t, err)
+		assert.Equal(t, []persistentdata.WebhookEvent{expectedEvent}, events)
+	}
+}
+
+func TestHandlerRejectsInvalidSecret(t *testing.T) {
+	rootApp := initApp(t)
+	handler := NewHandler(rootApp, func() []*app.ApplicationContext { return []*app.ApplicationContext{rootApp} })
+
+	for _, secret := range []string{"", "wrong-secret"} {
+		response := postNotification(handler, secret, `{"NotificationType":"ItemAdded","ItemId":"id"}`)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	}
+
+	events, err := persistentdata.GetWebhookEvents(rootApp)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestHandlerRejectsInvalidRequests(t *testing.T) {
+	rootApp := initApp(t)
+	handler := NewHandler(rootApp, func() []*app.ApplicationContext { return []*app.ApplicationContext{rootApp} })
+
+	assert.Equal(t, http.StatusBadRequest, postNotification(handler, testSecret, "not-json").Code)
+	assert.Equal(t, http.StatusBadRequest, postNotification(handler, testSecret, `{"NotificationType":"ItemAdded"}`).Code)
+
+	request := httptest.NewRequest(http.MethodGet, Path, nil)
+	request.Header.Set(SecretHeader, testSecret)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	events, err := persistentdata.GetWebhookEvents(rootApp)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestHandlerIgnoresOtherNotificationTypes(t *testing.T) {
+	rootApp := initApp(t)
+	handler := NewHandler(rootApp, func() []*app.ApplicationContext { return []*app.ApplicationContext{rootApp} })
+
+	response := postNotification(handler, testSecret, `{"NotificationType":"PlaybackStart","ItemId":"id"}`)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	events, err := persistentdata.GetWebhookEvents(rootApp)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -1,31 +1,154 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/app"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/clock"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/config"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/cron"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/demo"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/i18n"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/jellyfin"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/logger"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/newsletter"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/template"
 	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/tmdb"
-	"github.com/go-co-op/gocron/v2"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/validation"
+	"github.com/SeaweedbrainCY/jellyfin-newsletter/internal/webhook"
 	"go.uber.org/zap"
 )
 
 var version = "dev" // Will be set during build time
 
+func buildNewsletterWorkflow(app *app.ApplicationContext) newsletter.Workflow {
+	additionalJellyfinClients := []jellyfin.APIClient{}
+	for _, serverApp := range app.JellyfinServerContexts()[1:] {
+		additionalJellyfinClients = append(
+			additionalJellyfinClients,
+			jellyfin.NewJellyfinAPIClient(http.DefaultClient, serverApp),
+		)
+	}
+	return newsletter.Workflow{
+		JellyfinClient:            jellyfin.NewJellyfinAPIClient(http.DefaultClient, app),
+		AdditionalJellyfinClients: additionalJellyfinClients,
+		TMDBClient:                tmdb.NewCachedAPIClient(tmdb.InitTMDBApiClient(http.DefaultClient, app), app),
+	}
+}
+
+// runValidateCommand implements `jellyfin-newsletter validate`. It prints every configuration problem found
+// and returns the exit code.
+// --config and --themes-dir can be given before or after the command name.
+func runValidateCommand(configPath string, themesDir string, args []string) int {
+	validateFlags := flag.NewFlagSet("validate", flag.ExitOnError)
+	validateFlags.StringVar(&configPath, "config", configPath, "path to config file")
+	validateFlags.StringVar(&themesDir, "themes-dir", themesDir, "path to a folder with new/replacing themes files")
+	_ = validateFlags.Parse(args) // ExitOnError
+
+	problems := validation.ValidateConfiguration(configPath, themesDir)
+	if len(problems) == 0 {
+		fmt.Println(configPath + ": configuration is valid.")
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %d problem(s) found:\n", configPath, len(problems))
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, "  - "+problem.String())
+	}
+	return 1
+}
+
+// runSchemaCommand implements `jellyfin-newsletter schema`. It prints the JSON Schema of the configuration file.
+func runSchemaCommand() int {
+	schema, err := config.JSONSchema()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to generate the configuration JSON schema: "+err.Error())
+		return 1
+	}
+	fmt.Println(string(schema))
+	return 0
+}
+
+// runDemoCommand implements `jellyfin-newsletter demo`. It builds a newsletter from a fictional library,
+// without Jellyfin, TMDB or SMTP server, and writes it like the dry-run mode does. It returns the exit code.
+// Useful to preview a theme or a translation.
+func runDemoCommand(themesDir string, args []string) int {
+	var theme, language, outputDir string
+	demoFlags := flag.NewFlagSet("demo", flag.ExitOnError)
+	demoFlags.StringVar(&themesDir, "themes-dir", themesDir, "path to a folder with new/replacing themes files")
+	demoFlags.StringVar(&theme, "theme", "", "theme of the newsletter (default: classic)")
+	demoFlags.StringVar(&language, "language", "", "language of the newsletter (default: en)")
+	demoFlags.StringVar(&outputDir, "output-dir", "./previews", "folder the newsletter is written to")
+	_ = demoFlags.Parse(args) // ExitOnError
+
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create the output folder: "+err.Error())
+		return 1
+	}
+	// The data saved between runs are thrown away, so each demo starts from the same state
+	stateDir, err := os.MkdirTemp("", "jellyfin-newsletter-demo-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create the demo state folder: "+err.Error())
+		return 1
+	}
+	defer os.RemoveAll(stateDir)
+
+	config, err := config.LoadDemoConfig(stateDir, outputDir, themesDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load the demo configuration: "+err.Error())
+		return 1
+	}
+	if theme != "" {
+		config.EmailTemplate.Theme = theme
+	}
+	if language != "" {
+		config.EmailTemplate.Language = language
+	}
+
+	logger, err := logger.LoadLogger(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load the logger: "+err.Error())
+		return 1
+	}
+	localizer, err := i18n.NewLocalizer(config.EmailTemplate.Language)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load the language "+config.EmailTemplate.Language+": "+err.Error())
+		return 1
+	}
+	app := app.InitApplicationContext(config, logger, localizer, demo.Clock)
+	if err = template.CheckIfThemeIsAvailable(app); err != nil {
+		fmt.Fprintln(os.Stderr, "Theme "+config.EmailTemplate.Theme+" doesn't exist or is not usable: "+err.Error())
+		return 1
+	}
+
+	if err = demo.Run(app); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to build the demo newsletter: "+err.Error())
+		return 1
+	}
+	fmt.Println("Demo newsletter written to " + filepath.Join(outputDir, config.DryRun.OutputFilename))
+	return 0
+}
+
 func main() {
 	var configPath = flag.String("config", "./config/config.yml", "path to config file")
 	var themesDir = flag.String("themes-dir", "", "path to a folder with new/replacing themes files")
 	flag.Parse()
 
+	// The docker entrypoint always gives -config, so commands are looked up after the flags
+	switch flag.Arg(0) {
+	case "validate":
+		os.Exit(runValidateCommand(*configPath, *themesDir, flag.Args()[1:]))
+	case "schema":
+		os.Exit(runSchemaCommand())
+	case "demo":
+		os.Exit(runDemoCommand(*themesDir, flag.Args()[1:]))
+	}
+
 	config, err := config.LoadConfig(*configPath, *themesDir)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to load configuration: %v", err))
@@ -43,39 +166,48 @@ func main() {
 
 	app := app.InitApplicationContext(config, logger, localizer, clock.RealClock{})
 
-	err = template.CheckIfThemeIsAvailable(app)
+	newsletterApps, err := app.NewsletterContexts()
 	if err != nil {
-		app.Logger.Fatal(
-			"Chosen theme doesn't exist or is not usable right now.",
-			zap.String("Theme name", app.Config.EmailTemplate.Theme),
-			zap.Error(err),
-		)
+		logger.Fatal("Failed to build the newsletter profiles", zap.Error(err))
+	}
+
+	for _, newsletterApp := range newsletterApps {
+		err = template.CheckIfThemeIsAvailable(newsletterApp)
+		if err != nil {
+			newsletterApp.Logger.Fatal(
+				"Chosen theme doesn't exist or is not usable right now.",
+				zap.String("Theme name", newsletterApp.Config.EmailTemplate.Theme),
+				zap.Error(err),
+			)
+		}
 	}
 
 	app.Logger.Info("Starting Jellyfin Newsletter ...", zap.String("version", version))
 	app.Logger.Info("Copyright (C) 2025 Nathan Stchepinsky (Seaweedbrain). Licensed under the AGPLv3.0")
 	app.Logger.Info("Configuration loaded successfully")
 
-	newsletterWorkflow := newsletter.Workflow{
-		JellyfinClient: jellyfin.NewJellyfinAPIClient(http.DefaultClient, app),
-		TMDBClient:     tmdb.InitTMDBApiClient(http.DefaultClient, app),
-	}
-
 	if app.Config.Scheduler.Enabled {
-		var scheduler gocron.Scheduler
-		var job gocron.Job
-		scheduler, job, err = cron.CreateNewsletterScheduler(newsletterWorkflow, app)
+		var scheduler *cron.NewsletterScheduler
+		scheduler, err = cron.CreateNewsletterScheduler(buildNewsletterWorkflow, app, *themesDir)
 		if err != nil {
 			app.Logger.Fatal("Error while creating the scheduler. Exiting now.", zap.Error(err))
 		}
 		scheduler.Start()
-		cron.LogNextRun(job, app)
-		// Block forever (or until signal)
-		select {}
+		if app.Config.Webhook.Enabled {
+			webhook.StartListener(app, scheduler.Newsletters)
+		}
+		// Block forever, reloading the configuration when the file changes or on SIGHUP
+		scheduler.WatchConfigChanges(context.Background())
+	}
+
+	if app.Config.Webhook.Enabled {
+		app.Logger.Warn("The webhook listener requires the scheduler, it is not started. Queued items are still announced.")
 	}
 
 	// One time trigger
-	newsletterWorkflow.Run(app)
+	for _, newsletterApp := range newsletterApps {
+		buildNewsletterWorkflow(newsletterApp).Run(newsletterApp)
+	}
 
 	app.Logger.Info("Jellyfin-Newsletter exiting gracefully.")
 }